@@ -17,6 +17,8 @@ package main
 
 import (
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/mia-platform/miactl/internal/authorization"
 	"github.com/mia-platform/miactl/internal/cmd"
@@ -24,8 +26,15 @@ import (
 
 func main() {
 	rootCmd := cmd.NewRootCommand()
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
-	os.Exit(0)
+
+	// cancel the in-flight requests at the first interrupt, a second one will terminate the process immediately
+	ctx, stop := signal.NotifyContext(rootCmd.Context(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	code := cmd.Execute(ctx, rootCmd)
+	stop()
+	os.Exit(code)
 }
@@ -14,6 +14,7 @@ require (
 	github.com/stretchr/testify v1.11.1
 	golang.org/x/oauth2 v0.34.0
 	golang.org/x/sync v0.19.0
+	golang.org/x/term v0.34.0
 	golang.org/x/text v0.33.0
 	sigs.k8s.io/kustomize/kyaml v0.21.0
 )
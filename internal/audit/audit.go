@@ -0,0 +1,226 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit contains the format of the local audit log, where miactl append a json line for every
+// request that can change the remote state, and the functions for writing and reading it.
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// FileEnvVarName is the environment variable that can set the path of the audit log
+	FileEnvVarName = "MIACTL_AUDIT_FILE"
+
+	redactedValue = "REDACTED"
+)
+
+// sensitiveNameParts are the parts of the flag and field names whose values are masked in the command line
+var sensitiveNameParts = []string{"secret", "password", "token", "private-key", "privatekey", "passphrase"}
+
+// sensitiveHeaderParts are the parts of the header names, in addition to sensitiveNameParts, whose values
+// are masked in the header flags of the command line
+var sensitiveHeaderParts = []string{"authorization", "cookie", "api-key", "apikey"}
+
+// Entry is a line of the audit log, describing a request sent to the Console
+type Entry struct {
+	Timestamp   time.Time `json:"timestamp" yaml:"timestamp"`
+	User        string    `json:"user,omitempty" yaml:"user,omitempty"`
+	Host        string    `json:"host,omitempty" yaml:"host,omitempty"`
+	Context     string    `json:"context,omitempty" yaml:"context,omitempty"`
+	CompanyID   string    `json:"companyId,omitempty" yaml:"companyId,omitempty"`
+	ProjectID   string    `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	Environment string    `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Command     string    `json:"command,omitempty" yaml:"command,omitempty"`
+	Method      string    `json:"method" yaml:"method"`
+	Path        string    `json:"path" yaml:"path"`
+	StatusCode  int       `json:"statusCode,omitempty" yaml:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty" yaml:"error,omitempty"`
+	BodyHash    string    `json:"bodyHash,omitempty" yaml:"bodyHash,omitempty"`
+}
+
+// Filter select the entries of the audit log, the zero value match every entry
+type Filter struct {
+	// Since match the entries written at or after this time
+	Since time.Time
+	// Until match the entries written before this time
+	Until time.Time
+	// ProjectID match the entries of a project
+	ProjectID string
+	// Method match the entries of an HTTP method, ignoring the case
+	Method string
+}
+
+// Match return true if entry is selected by the filter
+func (f Filter) Match(entry *Entry) bool {
+	switch {
+	case !f.Since.IsZero() && entry.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !entry.Timestamp.Before(f.Until):
+		return false
+	case len(f.ProjectID) > 0 && entry.ProjectID != f.ProjectID:
+		return false
+	case len(f.Method) > 0 && !strings.EqualFold(entry.Method, f.Method):
+		return false
+	default:
+		return true
+	}
+}
+
+// HashBody return the sha256 hash of body prefixed by the algorithm name, or an empty string if there is no body
+func HashBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// CommandLine return args joined by spaces, with the values of the flags, of the key=value arguments and of
+// the headers that can contain credentials masked
+func CommandLine(args []string) string {
+	masked := make([]string, 0, len(args))
+	maskNext := false
+	headerNext := false
+	for _, arg := range args {
+		switch {
+		case maskNext:
+			masked = append(masked, redactedValue)
+			maskNext = false
+			continue
+		case headerNext:
+			masked = append(masked, maskHeader(arg))
+			headerNext = false
+			continue
+		case arg == "-H" || arg == "--header":
+			headerNext = true
+		case strings.HasPrefix(arg, "--header="):
+			arg = "--header=" + maskHeader(strings.TrimPrefix(arg, "--header="))
+		case strings.HasPrefix(arg, "-H"):
+			// the short flag can be followed by its value, with or without the equal sign
+			value := strings.TrimPrefix(strings.TrimPrefix(arg, "-H"), "=")
+			arg = arg[:len(arg)-len(value)] + maskHeader(value)
+		case strings.HasPrefix(arg, "-"):
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if isSensitive(name) {
+				if hasValue {
+					arg = arg[:strings.Index(arg, "=")+1] + redactedValue
+				} else {
+					maskNext = true
+				}
+			}
+		default:
+			if key, _, found := strings.Cut(arg, "="); found && isSensitive(key) {
+				arg = key + "=" + redactedValue
+			}
+		}
+		masked = append(masked, arg)
+	}
+	return strings.Join(masked, " ")
+}
+
+// maskHeader return header, in the "Key: value" format, with its value masked if the key can be used for
+// credentials; a header in an unknown format is masked entirely
+func maskHeader(header string) string {
+	key, _, found := strings.Cut(header, ":")
+	if !found {
+		return redactedValue
+	}
+
+	lowerKey := strings.ToLower(key)
+	for _, part := range sensitiveHeaderParts {
+		if strings.Contains(lowerKey, part) {
+			return key + ": " + redactedValue
+		}
+	}
+	if isSensitive(key) {
+		return key + ": " + redactedValue
+	}
+	return header
+}
+
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Append write entry at the end of the audit log at path, creating it and its directory if needed; the
+// line is written with a single call, so the entries of parallel processes are not mixed
+func Append(path string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("cannot create the audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot open the audit log: %w", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write the audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// Read return the entries of the audit log at path selected by filter, in the order they have been written;
+// a missing file is an empty log
+func Read(path string, filter Filter) ([]*Entry, error) {
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return []*Entry{}, nil
+	case err != nil:
+		return nil, fmt.Errorf("cannot read the audit log: %w", err)
+	}
+
+	entries := make([]*Entry, 0)
+	for index, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		entry := new(Entry)
+		if err := json.Unmarshal(line, entry); err != nil {
+			return nil, fmt.Errorf("invalid audit log entry at line %d: %w", index+1, err)
+		}
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,144 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandLine(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"no sensitive values": {
+			args:     []string{"miactl", "deploy", "trigger", "project", "--environment", "dev"},
+			expected: "miactl deploy trigger project --environment dev",
+		},
+		"flag with separated value": {
+			args:     []string{"miactl", "context", "auth", "name", "--client-secret", "secret", "--client-id", "id"},
+			expected: "miactl context auth name --client-secret REDACTED --client-id id",
+		},
+		"flag with inline value": {
+			args:     []string{"miactl", "deploy", "trigger", "--trigger-token=token"},
+			expected: "miactl deploy trigger --trigger-token=REDACTED",
+		},
+		"key value argument": {
+			args:     []string{"miactl", "api", "/api/secrets", "-f", "password=value", "-f", "name=value"},
+			expected: "miactl api /api/secrets -f password=REDACTED -f name=value",
+		},
+		"sensitive headers": {
+			args:     []string{"miactl", "api", "/api/projects", "-H", "Authorization: Bearer token", "--header", "Cookie: sid=value", "--header=Proxy-Authorization: Basic value"},
+			expected: "miactl api /api/projects -H Authorization: REDACTED --header Cookie: REDACTED --header=Proxy-Authorization: REDACTED",
+		},
+		"short header flag with attached value": {
+			args:     []string{"miactl", "api", "/api/projects", "-HX-Api-Key: value", "-H=Accept: application/json"},
+			expected: "miactl api /api/projects -HX-Api-Key: REDACTED -H=Accept: application/json",
+		},
+		"header in unknown format": {
+			args:     []string{"miactl", "api", "/api/projects", "-H", "value"},
+			expected: "miactl api /api/projects -H REDACTED",
+		},
+		"flag at the end": {
+			args:     []string{"miactl", "--jwt-private-key-file"},
+			expected: "miactl --jwt-private-key-file",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, CommandLine(testCase.args))
+		})
+	}
+}
+
+func TestHashBody(t *testing.T) {
+	assert.Empty(t, HashBody(nil))
+	assert.Equal(t, "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", HashBody([]byte("{}")))
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.log")
+	base := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	entries, err := Read(path, Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	written := []*Entry{
+		{Timestamp: base, ProjectID: "first", Method: "POST", Path: "/api/projects/", StatusCode: 200},
+		{Timestamp: base.Add(time.Hour), ProjectID: "second", Method: "DELETE", Path: "/api/projects/second", StatusCode: 204},
+		{Timestamp: base.Add(2 * time.Hour), ProjectID: "first", Method: "PATCH", Path: "/api/projects/first", Error: "connection refused"},
+	}
+	for _, entry := range written {
+		require.NoError(t, Append(path, entry))
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	testCases := map[string]struct {
+		filter   Filter
+		expected []*Entry
+	}{
+		"all entries": {
+			expected: written,
+		},
+		"since": {
+			filter:   Filter{Since: base.Add(time.Hour)},
+			expected: written[1:],
+		},
+		"until": {
+			filter:   Filter{Until: base.Add(time.Hour)},
+			expected: written[:1],
+		},
+		"project": {
+			filter:   Filter{ProjectID: "first"},
+			expected: []*Entry{written[0], written[2]},
+		},
+		"method ignoring case": {
+			filter:   Filter{Method: "delete"},
+			expected: written[1:2],
+		},
+		"no match": {
+			filter:   Filter{ProjectID: "second", Method: "POST"},
+			expected: []*Entry{},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			entries, err := Read(path, testCase.filter)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, entries)
+		})
+	}
+}
+
+func TestReadInvalidEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, os.WriteFile(path, []byte("{\"method\":\"POST\"}\nnot json\n"), 0600))
+
+	_, err := Read(path, Filter{})
+	assert.ErrorContains(t, err, "invalid audit log entry at line 2")
+}
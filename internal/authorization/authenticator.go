@@ -52,15 +52,24 @@ func (a *authenticator) Wrap(rt http.RoundTripper) http.RoundTripper {
 	}
 
 	authConfig := a.authConfig
+	refreshWindow := refreshWindowForConfig(a.config)
 	var userAuth http.RoundTripper
 	switch {
+	case authConfig.Exec != nil:
+		userAuth = &execAuthenticator{
+			next:          rt,
+			userAuth:      a.cacheReadWriter,
+			config:        authConfig.Exec,
+			refreshWindow: refreshWindow,
+		}
 	case len(authConfig.ClientID) > 0 && len(authConfig.ClientSecret) > 0:
 		userAuth = &serviceAccountAuthenticator{
-			client:       client,
-			next:         rt,
-			userAuth:     a.cacheReadWriter,
-			clientID:     authConfig.ClientID,
-			clientSecret: authConfig.ClientSecret,
+			client:        client,
+			next:          rt,
+			userAuth:      a.cacheReadWriter,
+			clientID:      authConfig.ClientID,
+			clientSecret:  authConfig.ClientSecret,
+			refreshWindow: refreshWindow,
 		}
 	case len(authConfig.ClientID) > 0 && len(authConfig.JWTKeyID) > 0 && len(authConfig.JWTPrivateKeyData) > 0:
 		userAuth = &serviceAccountAuthenticator{
@@ -70,12 +79,26 @@ func (a *authenticator) Wrap(rt http.RoundTripper) http.RoundTripper {
 			clientID:       authConfig.ClientID,
 			keyID:          authConfig.JWTKeyID,
 			privateKeyData: authConfig.JWTPrivateKeyData,
+			refreshWindow:  refreshWindow,
+		}
+	case len(authConfig.ClientID) > 0 && len(authConfig.JWTKeyID) > 0 && len(authConfig.JWTPrivateKeyFile) > 0:
+		userAuth = &serviceAccountAuthenticator{
+			client:         client,
+			next:           rt,
+			userAuth:       a.cacheReadWriter,
+			clientID:       authConfig.ClientID,
+			keyID:          authConfig.JWTKeyID,
+			privateKeyFile: authConfig.JWTPrivateKeyFile,
+			passphrase:     authConfig.JWTPrivateKeyPassphrase,
+			refreshWindow:  refreshWindow,
 		}
 	default:
 		userAuth = &userAuthenticator{
-			client:   client,
-			next:     rt,
-			userAuth: a.cacheReadWriter,
+			client:        client,
+			next:          rt,
+			userAuth:      a.cacheReadWriter,
+			loginMode:     authConfig.LoginMode,
+			refreshWindow: refreshWindow,
 			serverReadyHandler: func(url string) error {
 				if err := open(url); err != nil {
 					return fmt.Errorf("could not open the browser: %w", err)
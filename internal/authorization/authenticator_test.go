@@ -41,3 +41,17 @@ func TestAuthenticatorWrapping(t *testing.T) {
 	assert.NotNil(t, wrappedRt)
 	assert.IsType(t, &userAuthenticator{}, wrappedRt)
 }
+
+func TestAuthenticatorWrappingExec(t *testing.T) {
+	authProvider := &authenticator{
+		config:          &client.Config{Host: "http://example.com"},
+		cacheReadWriter: &testAuthCacheProvider{},
+		authConfig: client.AuthConfig{
+			Exec: &client.ExecConfig{Command: "echo"},
+		},
+	}
+
+	wrappedRt := authProvider.Wrap(http.DefaultTransport)
+	assert.NotNil(t, wrappedRt)
+	assert.IsType(t, &execAuthenticator{}, wrappedRt)
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -91,6 +92,16 @@ type Config struct {
 
 	// ServerReadyHandler function to call when the local server is ready to receive traffic
 	ServerReadyHandler LocalServerReadyHandler
+
+	// In is used for reading the user input in the paste login flow, if nil os.Stdin is used
+	In io.Reader
+
+	// Out is used for printing the instructions in the device and paste login flows, if nil os.Stderr is used
+	Out io.Writer
+
+	// PollInterval if set override the interval between two token requests returned by the server in
+	// the device login flow
+	PollInterval time.Duration
 }
 
 // GetToken performs the authorization flow against the Mia-Platform Console
@@ -138,18 +149,29 @@ func (c *Config) startLoginFlow(ctx context.Context) (*oauth2.Token, error) {
 	}
 
 	redirectURI := "http://" + listener.Addr().String() + callbackEndpointString
-	startFlowURL := c.Client.Get().
+	authResponse, err := startLocalServerForToken(ctx, c.startFlowURL(providerID, redirectURI), listener, c.ServerReadyHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwtToken(ctx, authResponse, c.Client)
+}
+
+// startFlowURL return the url to open in a browser for starting the login flow
+func (c *Config) startFlowURL(providerID, redirectURI string) string {
+	return c.Client.Get().
 		APIPath(authorizeEndpointString).
 		SetParam(appIDKey, c.AppID).
 		SetParam(providerIDKey, providerID).
 		SetParam("redirect_uri", redirectURI).URL().String()
+}
 
-	authResponse, err := startLocalServerForToken(ctx, startFlowURL, listener, c.ServerReadyHandler)
-	if err != nil {
-		return nil, err
+// out return the writer to use for printing instructions to the user
+func (c *Config) out() io.Writer {
+	if c.Out != nil {
+		return c.Out
 	}
-
-	return jwtToken(ctx, authResponse, c.Client)
+	return os.Stderr
 }
 
 // newListener return the first listener that can be opened or an error if all the ports are already used
@@ -0,0 +1,163 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+const (
+	deviceCodeEndpointString = "/api/oauth/device/code"
+	// disable gosec for false positive in G101, because it is not an hardcoded credentials...
+	deviceTokenEndpointString = "/api/oauth/device/token" // #nosec G101
+	defaultDevicePollInterval = 5 * time.Second
+	slowDownIntervalIncrement = 5 * time.Second
+	authorizationPendingError = "authorization_pending"
+	slowDownError             = "slow_down"
+)
+
+// GetDeviceToken performs the device authorization flow against the Mia-Platform Console
+// This perform the following actions:
+// 1. Send a request for getting the provider id for the configured AppID
+// 2. Request a new device code and user code
+// 3. Print the url to open on another device and the user code to insert
+// 4. Poll the token endpoint until the user authorize the request or the code expires
+// 5. Return the oauth2 token
+func (c *Config) GetDeviceToken(ctx context.Context) (*oauth2.Token, error) {
+	if len(c.AppID) == 0 {
+		return nil, errors.New("missing appId for device login flow")
+	}
+
+	if c.Client == nil {
+		return nil, errors.New("cannot setup device login flow without a valid client")
+	}
+
+	providerID, err := providerIDForApplication(ctx, c.AppID, c.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCode, err := c.requestDeviceCode(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := c.out()
+	fmt.Fprintf(out, "To login open the following url in a browser and enter the code %s\n\n\t%s\n\n", deviceCode.UserCode, deviceCode.VerificationURI)
+	if len(deviceCode.VerificationURIComplete) > 0 {
+		fmt.Fprintf(out, "or open the following url that already contains the code\n\n\t%s\n\n", deviceCode.VerificationURIComplete)
+	}
+
+	if deviceCode.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(deviceCode.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	return c.pollDeviceToken(ctx, deviceCode)
+}
+
+// requestDeviceCode start a new device authorization returning the codes to use
+func (c *Config) requestDeviceCode(ctx context.Context, providerID string) (*resources.DeviceCode, error) {
+	bodyData, err := resources.EncodeResourceToJSON(&resources.DeviceCodeRequest{AppID: c.AppID, ProviderID: providerID})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.Client.
+		Post().
+		APIPath(deviceCodeEndpointString).
+		Body(bodyData).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := response.Error(); err != nil {
+		return nil, err
+	}
+
+	deviceCode := new(resources.DeviceCode)
+	if err := response.ParseResponse(deviceCode); err != nil {
+		return nil, err
+	}
+
+	if len(deviceCode.DeviceCode) == 0 || len(deviceCode.UserCode) == 0 {
+		return nil, errors.New("the server has not returned a valid device code")
+	}
+
+	return deviceCode, nil
+}
+
+// pollDeviceToken ask for the token of the device authorization until the user has completed the login,
+// or ctx expires
+func (c *Config) pollDeviceToken(ctx context.Context, deviceCode *resources.DeviceCode) (*oauth2.Token, error) {
+	bodyData, err := resources.EncodeResourceToJSON(&resources.DeviceTokenRequest{DeviceCode: deviceCode.DeviceCode})
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(deviceCode.Interval) * time.Second
+	switch {
+	case c.PollInterval > 0:
+		interval = c.PollInterval
+	case interval <= 0:
+		interval = defaultDevicePollInterval
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("device authorization not completed: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		response, err := c.Client.
+			Post().
+			APIPath(deviceTokenEndpointString).
+			Body(bodyData).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.Error() == nil {
+			return parseJWTResponse(response)
+		}
+
+		authorizationError := new(resources.DeviceAuthorizationError)
+		if err := response.ParseResponse(authorizationError); err != nil {
+			return nil, err
+		}
+
+		switch authorizationError.Error {
+		case authorizationPendingError:
+		case slowDownError:
+			if c.PollInterval == 0 {
+				interval += slowDownIntervalIncrement
+			}
+		default:
+			return nil, fmt.Errorf("device authorization error: %w", response.Error())
+		}
+	}
+}
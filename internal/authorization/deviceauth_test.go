@@ -0,0 +1,145 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+func testServerForDeviceFlow(t *testing.T, expiresIn int64, pollingErrors ...string) *httptest.Server {
+	t.Helper()
+	return testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.RequestURI == fmt.Sprintf(providerEndpointStringTemplate, appID):
+			err := json.NewEncoder(w).Encode([]*resources.AuthProvider{{ID: "foo", Label: "Foo", Type: "foo-type"}})
+			assert.NoError(t, err)
+		case r.Method == http.MethodPost && r.RequestURI == deviceCodeEndpointString:
+			request := new(resources.DeviceCodeRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+			assert.Equal(t, resources.DeviceCodeRequest{AppID: appID, ProviderID: "foo"}, *request)
+			err := json.NewEncoder(w).Encode(&resources.DeviceCode{
+				DeviceCode:      "device-code",
+				UserCode:        "USER-CODE",
+				VerificationURI: "https://example.com/device",
+				ExpiresIn:       expiresIn,
+				Interval:        1,
+			})
+			assert.NoError(t, err)
+		case r.Method == http.MethodPost && r.RequestURI == deviceTokenEndpointString:
+			request := new(resources.DeviceTokenRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+			assert.Equal(t, "device-code", request.DeviceCode)
+			if len(pollingErrors) > 0 {
+				errorCode := pollingErrors[0]
+				pollingErrors = pollingErrors[1:]
+				w.WriteHeader(http.StatusBadRequest)
+				err := json.NewEncoder(w).Encode(&resources.DeviceAuthorizationError{Error: errorCode})
+				assert.NoError(t, err)
+				return
+			}
+			err := json.NewEncoder(w).Encode(&resources.UserToken{
+				AccessToken:  "device",
+				RefreshToken: "refresh",
+				ExpiresAt:    time.Now().Add(1 * time.Hour).Unix(),
+			})
+			assert.NoError(t, err)
+		default:
+			assert.Failf(t, "unexpected request", "%s request %s", r.Method, r.RequestURI)
+		}
+	})
+}
+
+func TestGetDeviceToken(t *testing.T) {
+	testCases := map[string]struct {
+		expiresIn     int64
+		pollingErrors []string
+		expectedToken string
+		expectedError string
+	}{
+		"user authorize immediately": {
+			expiresIn:     60,
+			expectedToken: "device",
+		},
+		"authorization pending and slow down": {
+			expiresIn:     60,
+			pollingErrors: []string{authorizationPendingError, slowDownError, authorizationPendingError},
+			expectedToken: "device",
+		},
+		"user deny the authorization": {
+			expiresIn:     60,
+			pollingErrors: []string{authorizationPendingError, "access_denied"},
+			expectedError: "device authorization error",
+		},
+		"device code expires": {
+			expiresIn:     1,
+			pollingErrors: slices.Repeat([]string{authorizationPendingError}, 20),
+			expectedError: "device authorization not completed",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			server := testServerForDeviceFlow(t, testCase.expiresIn, testCase.pollingErrors...)
+			defer server.Close()
+
+			apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL, Transport: http.DefaultTransport})
+			require.NoError(t, err)
+
+			out := new(bytes.Buffer)
+			config := &Config{
+				AppID:        appID,
+				Client:       apiClient,
+				Out:          out,
+				PollInterval: 100 * time.Millisecond,
+			}
+
+			token, err := config.GetDeviceToken(t.Context())
+			assert.Contains(t, out.String(), "USER-CODE")
+			assert.Contains(t, out.String(), "https://example.com/device")
+			if len(testCase.expectedError) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedError)
+				assert.Nil(t, token)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedToken, token.AccessToken)
+		})
+	}
+}
+
+func TestGetDeviceTokenError(t *testing.T) {
+	config := &Config{}
+	_, err := config.GetDeviceToken(t.Context())
+	assert.ErrorContains(t, err, "missing appId")
+
+	config.AppID = appID
+	_, err = config.GetDeviceToken(t.Context())
+	assert.ErrorContains(t, err, "without a valid client")
+}
@@ -0,0 +1,120 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+const execCredentialKind = "ExecCredential"
+
+type execAuthenticator struct {
+	mutex    sync.Mutex
+	userAuth client.AuthCacheReadWriter
+	next     http.RoundTripper
+	config   *client.ExecConfig
+	// refreshWindow is the time before the expiration when the cached token is refreshed
+	refreshWindow time.Duration
+
+	// token keep in memory the last token without expiration, because it cannot be cached safely
+	token *oauth2.Token
+}
+
+func (ea *execAuthenticator) RoundTrip(req *http.Request) (*http.Response, error) {
+	return roundTrip(req, ea.next, ea.AccessToken)
+}
+
+func (ea *execAuthenticator) AccessToken() (*oauth2.Token, error) {
+	ea.mutex.Lock()
+	defer ea.mutex.Unlock()
+
+	if ea.token != nil {
+		return ea.token, nil
+	}
+
+	// hold the lock until the new token is saved, so other processes will wait and then reuse it
+	unlock := lockCache(ea.userAuth)
+	defer unlock()
+
+	cached := ea.userAuth.ReadJWTToken()
+	if tokenValid(cached, ea.refreshWindow) {
+		return cached, nil
+	}
+
+	jwt, err := runExecCredential(ea.config)
+	if err != nil {
+		if cached.Valid() {
+			// the token is only near to its expiration, it can still be used
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if jwt.Expiry.IsZero() {
+		ea.token = jwt
+	} else {
+		ea.userAuth.WriteJWTToken(jwt)
+	}
+
+	return jwt, nil
+}
+
+// runExecCredential run the command set in config and parse its standard output as an ExecCredential
+func runExecCredential(config *client.ExecConfig) (*oauth2.Token, error) {
+	if config == nil || len(config.Command) == 0 {
+		return nil, errors.New("missing command for exec credential")
+	}
+
+	stdout := new(bytes.Buffer)
+	// disable gosec G204, running a command set by the user is the purpose of this function
+	cmd := exec.Command(config.Command, config.Args...) // #nosec G204
+	cmd.Env = append(os.Environ(), config.Env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("exec credential command %q failed: %w", config.Command, err)
+	}
+
+	credential := new(resources.ExecCredential)
+	if err := json.Unmarshal(stdout.Bytes(), credential); err != nil {
+		return nil, fmt.Errorf("cannot parse exec credential output: %w", err)
+	}
+
+	switch {
+	case credential.Kind != execCredentialKind:
+		return nil, fmt.Errorf("exec credential output has kind %q, expected %q", credential.Kind, execCredentialKind)
+	case credential.Status == nil || len(strings.TrimSpace(credential.Status.Token)) == 0:
+		return nil, errors.New("exec credential output does not contain a token")
+	}
+
+	return credential.JWTToken(), nil
+}
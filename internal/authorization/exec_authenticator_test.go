@@ -0,0 +1,150 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+// TestExecHelperProcess is not a real test, it is used as exec credential plugin by the other tests
+func TestExecHelperProcess(_ *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, os.Getenv("TEST_EXEC_OUTPUT"))
+	os.Exit(0)
+}
+
+func testExecConfig(output string) *client.ExecConfig {
+	return &client.ExecConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestExecHelperProcess"},
+		Env: []string{
+			"GO_WANT_HELPER_PROCESS=1",
+			"TEST_EXEC_OUTPUT=" + output,
+		},
+	}
+}
+
+type testWritableCacheProvider struct {
+	token *oauth2.Token
+}
+
+func (ap *testWritableCacheProvider) ReadJWTToken() *oauth2.Token {
+	if ap.token == nil {
+		return &oauth2.Token{}
+	}
+	return ap.token
+}
+
+func (ap *testWritableCacheProvider) WriteJWTToken(token *oauth2.Token) { ap.token = token }
+
+func TestExecAuthenticator(t *testing.T) {
+	testCases := map[string]struct {
+		authCacheProvider client.AuthCacheReadWriter
+		config            *client.ExecConfig
+		expectedToken     string
+		expectCached      bool
+		expectErr         bool
+	}{
+		"valid cached token": {
+			authCacheProvider: &testAuthCacheProvider{},
+			config:            testExecConfig(""),
+			expectedToken:     "foo",
+		},
+		"token with expiration": {
+			authCacheProvider: &testWritableCacheProvider{},
+			config:            testExecConfig(`{"kind":"ExecCredential","status":{"token":"new","expirationTimestamp":"2100-01-01T00:00:00Z"}}`),
+			expectedToken:     "new",
+			expectCached:      true,
+		},
+		"token without expiration": {
+			authCacheProvider: &testWritableCacheProvider{},
+			config:            testExecConfig(`{"kind":"ExecCredential","status":{"token":"new"}}`),
+			expectedToken:     "new",
+		},
+		"wrong kind": {
+			authCacheProvider: &testWritableCacheProvider{},
+			config:            testExecConfig(`{"kind":"Credential","status":{"token":"new"}}`),
+			expectErr:         true,
+		},
+		"missing token": {
+			authCacheProvider: &testWritableCacheProvider{},
+			config:            testExecConfig(`{"kind":"ExecCredential","status":{}}`),
+			expectErr:         true,
+		},
+		"invalid output": {
+			authCacheProvider: &testWritableCacheProvider{},
+			config:            testExecConfig("invalid"),
+			expectErr:         true,
+		},
+		"missing command": {
+			authCacheProvider: &testWritableCacheProvider{},
+			config:            &client.ExecConfig{},
+			expectErr:         true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			ea := &execAuthenticator{
+				userAuth: testCase.authCacheProvider,
+				config:   testCase.config,
+			}
+
+			token, err := ea.AccessToken()
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedToken, token.AccessToken)
+			if cacheProvider, ok := testCase.authCacheProvider.(*testWritableCacheProvider); ok {
+				assert.Equal(t, testCase.expectCached, cacheProvider.token != nil)
+			}
+		})
+	}
+}
+
+func TestExecAuthenticatorRoundTrip(t *testing.T) {
+	rt := &testRoundTripper{}
+	auth := &execAuthenticator{
+		userAuth: &testWritableCacheProvider{},
+		config:   testExecConfig(`{"kind":"ExecCredential","status":{"token":"exec"}}`),
+		next:     rt,
+	}
+
+	req := &http.Request{
+		Header: make(http.Header),
+	}
+
+	auth.RoundTrip(req) //nolint:bodyclose
+	rtRequest := rt.Request
+	require.NotNil(t, rtRequest)
+	assert.NotSame(t, rtRequest, req)
+	assert.Equal(t, "Bearer exec", rtRequest.Header.Get("Authorization"))
+}
@@ -0,0 +1,117 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// defaultPasteRedirectAddress is the address used for the redirect uri of the paste login flow if no
+// LocalServerBindAddress is configured, no server will listen on it
+const defaultPasteRedirectAddress = "127.0.0.1:53535"
+
+// GetPastedToken performs the authorization flow against the Mia-Platform Console without starting a local server
+// This perform the following actions:
+// 1. Send a request for getting the provider id for the configured AppID
+// 2. Print the url to open in a browser on another device
+// 3. Read the address of the page the browser has been redirected to after the login
+// 4. Exchange the code found in the address
+// 5. Return the oauth2 token
+func (c *Config) GetPastedToken(ctx context.Context) (*oauth2.Token, error) {
+	if len(c.AppID) == 0 {
+		return nil, errors.New("missing appId for paste login flow")
+	}
+
+	if c.Client == nil {
+		return nil, errors.New("cannot setup paste login flow without a valid client")
+	}
+
+	providerID, err := providerIDForApplication(ctx, c.AppID, c.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectAddress := defaultPasteRedirectAddress
+	if len(c.LocalServerBindAddress) > 0 {
+		redirectAddress = c.LocalServerBindAddress[0]
+	}
+	redirectURI := "http://" + redirectAddress + callbackEndpointString
+
+	out := c.out()
+	fmt.Fprintf(out, "To login open the following url in a browser\n\n\t%s\n\n", c.startFlowURL(providerID, redirectURI))
+	fmt.Fprintf(out, "After the login the browser will be redirected to a page that cannot be loaded,\n")
+	fmt.Fprintf(out, "copy its address from the browser and paste it here: ")
+
+	var in io.Reader = os.Stdin
+	if c.In != nil {
+		in = c.In
+	}
+
+	pasted, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	authResponse, err := parsePastedResponse(pasted)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwtToken(ctx, authResponse, c.Client)
+}
+
+// parsePastedResponse return the authorization response contained in pasted, that can be the complete
+// redirect url or only its query string
+func parsePastedResponse(pasted string) (*authResponse, error) {
+	pasted = strings.TrimSpace(pasted)
+	if len(pasted) == 0 {
+		return nil, errors.New("no address has been pasted")
+	}
+
+	rawQuery := pasted
+	if strings.Contains(pasted, "?") {
+		redirectURL, err := url.Parse(pasted)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pasted address: %w", err)
+		}
+		rawQuery = redirectURL.RawQuery
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pasted address: %w", err)
+	}
+
+	if errorCode := query.Get("error"); len(errorCode) > 0 {
+		return nil, fmt.Errorf("authorization error from server: %s %s", errorCode, query.Get("error_description"))
+	}
+
+	code := query.Get("code")
+	if len(code) == 0 {
+		return nil, errors.New("no authorization code found in the pasted address")
+	}
+
+	return &authResponse{Code: code, State: query.Get("state")}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+func TestParsePastedResponse(t *testing.T) {
+	testCases := map[string]struct {
+		pasted           string
+		expectedResponse *authResponse
+		expectedError    string
+	}{
+		"complete redirect url": {
+			pasted:           "http://127.0.0.1:53535/oauth/callback?code=foo&state=bar\n",
+			expectedResponse: &authResponse{Code: "foo", State: "bar"},
+		},
+		"only query string": {
+			pasted:           "  code=foo&state=bar  ",
+			expectedResponse: &authResponse{Code: "foo", State: "bar"},
+		},
+		"empty input": {
+			pasted:        "\n",
+			expectedError: "no address has been pasted",
+		},
+		"missing code": {
+			pasted:        "http://127.0.0.1:53535/oauth/callback?state=bar",
+			expectedError: "no authorization code found",
+		},
+		"error from server": {
+			pasted:        "http://127.0.0.1:53535/oauth/callback?error=access_denied&error_description=denied",
+			expectedError: "authorization error from server: access_denied denied",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			response, err := parsePastedResponse(testCase.pasted)
+			if len(testCase.expectedError) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedError)
+				assert.Nil(t, response)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedResponse, response)
+		})
+	}
+}
+
+func TestGetPastedToken(t *testing.T) {
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.RequestURI == fmt.Sprintf(providerEndpointStringTemplate, appID):
+			err := json.NewEncoder(w).Encode([]*resources.AuthProvider{{ID: "foo", Label: "Foo", Type: "foo-type"}})
+			assert.NoError(t, err)
+		case r.Method == http.MethodPost && r.RequestURI == getTokenEndpointString:
+			err := json.NewEncoder(w).Encode(&resources.UserToken{
+				AccessToken:  "pasted",
+				RefreshToken: "refresh",
+				ExpiresAt:    time.Now().Add(1 * time.Hour).Unix(),
+			})
+			assert.NoError(t, err)
+		default:
+			assert.Failf(t, "unexpected request", "%s request %s", r.Method, r.RequestURI)
+		}
+	})
+	defer server.Close()
+
+	apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL, Transport: http.DefaultTransport})
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	config := &Config{
+		AppID:  appID,
+		Client: apiClient,
+		In:     strings.NewReader("http://127.0.0.1:53535/oauth/callback?code=foo&state=bar\n"),
+		Out:    out,
+	}
+
+	token, err := config.GetPastedToken(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "pasted", token.AccessToken)
+	assert.Contains(t, out.String(), server.URL+authorizeEndpointString)
+	assert.Contains(t, out.String(), "redirect_uri=http%3A%2F%2F127.0.0.1%3A53535%2Foauth%2Fcallback")
+}
@@ -0,0 +1,181 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1" //nolint:gosec // hmacWithSHA1 is the default prf of PBKDF2 and is still used by some tools
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+const (
+	// encryptedPrivateKeyPEMType is the pem type of a PKCS#8 EncryptedPrivateKeyInfo
+	encryptedPrivateKeyPEMType = "ENCRYPTED PRIVATE KEY"
+)
+
+// object identifiers defined in RFC 8018 and RFC 3565 that are supported for decrypting PKCS#8 keys
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// maxPBKDF2Iterations limit the work needed for deriving the key, an untrusted file could otherwise block
+// miactl with a huge iteration count; openssl uses 2048 iterations by default
+const maxPBKDF2Iterations = 10_000_000
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 return the PKCS#8 PrivateKeyInfo contained in the der encoded EncryptedPrivateKeyInfo,
+// only the PBES2 scheme with PBKDF2 and AES-CBC is supported, that is the default of openssl
+func decryptPKCS8(der []byte, passphrase string) ([]byte, error) {
+	keyInfo := new(encryptedPrivateKeyInfo)
+	if _, err := asn1.Unmarshal(der, keyInfo); err != nil {
+		return nil, fmt.Errorf("invalid encrypted private key: %w", err)
+	}
+
+	if !keyInfo.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption algorithm %s, only PBES2 is supported", keyInfo.Algorithm.Algorithm)
+	}
+
+	params := new(pbes2Params)
+	if _, err := asn1.Unmarshal(keyInfo.Algorithm.Parameters.FullBytes, params); err != nil {
+		return nil, fmt.Errorf("invalid PBES2 parameters: %w", err)
+	}
+
+	keySize, err := aesKeySize(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("invalid encryption parameters: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid encryption parameters: wrong iv length")
+	}
+
+	key, err := deriveKey(params.KeyDerivationFunc, passphrase, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data := keyInfo.EncryptedData
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted private key length")
+	}
+
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+	return removePadding(decrypted)
+}
+
+func aesKeySize(oid asn1.ObjectIdentifier) (int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, nil
+	case oid.Equal(oidAES192CBC):
+		return 24, nil
+	case oid.Equal(oidAES256CBC):
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("unsupported private key cipher %s, only AES-CBC is supported", oid)
+	}
+}
+
+func deriveKey(kdf pkix.AlgorithmIdentifier, passphrase string, keySize int) ([]byte, error) {
+	if !kdf.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s, only PBKDF2 is supported", kdf.Algorithm)
+	}
+
+	params := new(pbkdf2Params)
+	if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, params); err != nil {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters: %w", err)
+	}
+
+	if params.IterationCount < 1 || params.IterationCount > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters: the iteration count must be between 1 and %d", maxPBKDF2Iterations)
+	}
+
+	if params.KeyLength != 0 && params.KeyLength != keySize {
+		return nil, errors.New("invalid PBKDF2 parameters: key length does not match the cipher")
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(params.PRF.Algorithm) == 0, params.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %s", params.PRF.Algorithm)
+	}
+
+	return pbkdf2.Key(prf, passphrase, params.Salt, params.IterationCount, keySize)
+}
+
+// removePadding strip the PKCS#7 padding from data, a wrong padding is usually caused by a wrong passphrase
+func removePadding(data []byte) ([]byte, error) {
+	errWrongPassphrase := errors.New("cannot decrypt the private key, the passphrase may be wrong")
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(data) {
+		return nil, errWrongPassphrase
+	}
+
+	for _, value := range data[len(data)-padding:] {
+		if int(value) != padding {
+			return nil, errWrongPassphrase
+		}
+	}
+
+	return data[:len(data)-padding], nil
+}
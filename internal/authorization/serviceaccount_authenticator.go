@@ -16,21 +16,32 @@
 package authorization
 
 import (
+	"bufio"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/term"
 
+	"github.com/mia-platform/miactl/internal/cliconfig"
 	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/jws"
 )
@@ -49,6 +60,14 @@ type serviceAccountAuthenticator struct {
 	clientSecret   string
 	keyID          string
 	privateKeyData string
+	privateKeyFile string
+	passphrase     string
+	refreshWindow  time.Duration
+
+	// in and out are used for asking the passphrase of an encrypted privateKeyFile, if nil
+	// os.Stdin and os.Stderr will be used
+	in  io.Reader
+	out io.Writer
 }
 
 func (saa *serviceAccountAuthenticator) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -59,13 +78,23 @@ func (saa *serviceAccountAuthenticator) AccessToken() (*oauth2.Token, error) {
 	saa.mutex.Lock()
 	defer saa.mutex.Unlock()
 
+	// hold the lock until the new token is saved, so other processes will wait and then reuse it
+	unlock := lockCache(saa.userAuth)
+	defer unlock()
+
 	jwt := saa.userAuth.ReadJWTToken()
 
-	if jwt.Valid() {
+	if tokenValid(jwt, saa.refreshWindow) {
 		return jwt, nil
 	}
 
-	return saa.basicAuth()
+	newJWT, err := saa.basicAuth()
+	if err != nil && jwt.Valid() {
+		// the token is only near to its expiration, it can still be used
+		return jwt, nil
+	}
+
+	return newJWT, err
 }
 
 func (saa *serviceAccountAuthenticator) basicAuth() (*oauth2.Token, error) {
@@ -75,8 +104,15 @@ func (saa *serviceAccountAuthenticator) basicAuth() (*oauth2.Token, error) {
 	case len(saa.clientID) > 0 && len(saa.clientSecret) > 0:
 		jwt, err = getClientCredentialsToken(context.Background(), saa.client, saa.clientID, saa.clientSecret)
 	case len(saa.clientID) > 0 && len(saa.keyID) > 0 && len(saa.privateKeyData) > 0:
-		var key *rsa.PrivateKey
-		key, err = rsaKeyFromBase64(saa.privateKeyData)
+		var key crypto.PrivateKey
+		key, err = privateKeyFromBase64(saa.privateKeyData)
+		if err != nil {
+			break
+		}
+		jwt, err = getJWTToken(context.Background(), saa.client, saa.keyID, saa.clientID, key)
+	case len(saa.clientID) > 0 && len(saa.keyID) > 0 && len(saa.privateKeyFile) > 0:
+		var key crypto.PrivateKey
+		key, err = privateKeyFromFile(saa.privateKeyFile, saa.readPassphrase)
 		if err != nil {
 			break
 		}
@@ -92,6 +128,44 @@ func (saa *serviceAccountAuthenticator) basicAuth() (*oauth2.Token, error) {
 	return jwt, err
 }
 
+// readPassphrase return the configured passphrase, or ask it to the user if is not set; when reading from
+// os.Stdin the passphrase is asked only if it is a terminal, and the typed characters are not echoed
+func (saa *serviceAccountAuthenticator) readPassphrase() (string, error) {
+	if len(saa.passphrase) > 0 {
+		return saa.passphrase, nil
+	}
+
+	var out io.Writer = os.Stderr
+	if saa.out != nil {
+		out = saa.out
+	}
+
+	if saa.in == nil && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("the private key %s is encrypted and its passphrase cannot be asked without a terminal, set it with the %s environment variable", saa.privateKeyFile, cliconfig.JWTPrivateKeyPassphraseEnvVarName)
+	}
+
+	fmt.Fprintf(out, "Enter the passphrase for the private key %s: ", saa.privateKeyFile)
+	var passphrase string
+	var err error
+	if saa.in != nil {
+		passphrase, err = bufio.NewReader(saa.in).ReadString('\n')
+	} else {
+		var data []byte
+		data, err = term.ReadPassword(int(os.Stdin.Fd()))
+		passphrase = string(data)
+	}
+	fmt.Fprintln(out)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	passphrase = strings.TrimRight(passphrase, "\r\n")
+	if len(passphrase) == 0 {
+		return "", errors.New("no passphrase has been provided for the encrypted private key")
+	}
+	return passphrase, nil
+}
+
 func getClientCredentialsToken(ctx context.Context, apiClient client.Interface, clientID, clientSecret string) (*oauth2.Token, error) {
 	req := apiClient.Post().APIPath(serviceAccountAuthEndpoint)
 	endpoint := req.URL()
@@ -107,10 +181,15 @@ func getClientCredentialsToken(ctx context.Context, apiClient client.Interface,
 	return config.Token(tokenContext)
 }
 
-func getJWTToken(ctx context.Context, apiClient client.Interface, keyID, clientID string, key *rsa.PrivateKey) (*oauth2.Token, error) {
+func getJWTToken(ctx context.Context, apiClient client.Interface, keyID, clientID string, key crypto.PrivateKey) (*oauth2.Token, error) {
+	signer, algorithm, err := jws.SignerForKey(key)
+	if err != nil {
+		return nil, err
+	}
+
 	jwsHeader := &jws.Header{
 		Typ:       "JWT",
-		Algorithm: "RS256",
+		Algorithm: algorithm,
 		KeyID:     keyID,
 	}
 
@@ -123,7 +202,7 @@ func getJWTToken(ctx context.Context, apiClient client.Interface, keyID, clientI
 		},
 	}
 
-	signedJWS, err := jws.Encode(jwsHeader, jwsClaim, key)
+	signedJWS, err := jws.EncodeWithSigner(jwsHeader, jwsClaim, signer)
 	if err != nil {
 		return nil, err
 	}
@@ -168,18 +247,69 @@ func getJWTToken(ctx context.Context, apiClient client.Interface, keyID, clientI
 	}, nil
 }
 
-func rsaKeyFromBase64(base64Data string) (*rsa.PrivateKey, error) {
+// privateKeyFromBase64 decode a PKCS#8 private key saved as base64 encoded pem, only RSA, ECDSA P-256
+// and Ed25519 keys are supported
+func privateKeyFromBase64(base64Data string) (crypto.PrivateKey, error) {
 	keyData, err := base64.StdEncoding.DecodeString(base64Data)
 	if err != nil {
 		return nil, err
 	}
 
+	return privateKeyFromPEM(keyData, nil)
+}
+
+// privateKeyFromFile read a PKCS#8 private key from the pem file at path, if the key is encrypted
+// passphrase is called for obtaining the passphrase for decrypting it
+func privateKeyFromFile(path string, passphrase func() (string, error)) (crypto.PrivateKey, error) {
+	keyData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the private key file: %w", err)
+	}
+
+	return privateKeyFromPEM(keyData, passphrase)
+}
+
+func privateKeyFromPEM(keyData []byte, passphrase func() (string, error)) (crypto.PrivateKey, error) {
 	pemData, _ := pem.Decode(keyData)
-	key, err := x509.ParsePKCS8PrivateKey(pemData.Bytes)
-	rsaKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, errors.New("only rsa key are supported")
+	if pemData == nil {
+		return nil, errors.New("private key data is not a valid pem block")
+	}
+
+	der := pemData.Bytes
+	if pemData.Type == encryptedPrivateKeyPEMType {
+		if passphrase == nil {
+			return nil, errors.New("encrypted private keys are supported only when read from a file")
+		}
+
+		password, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+
+		if der, err = decryptPKCS8(der, password); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		if pemData.Type == encryptedPrivateKeyPEMType {
+			return nil, errors.New("cannot decrypt the private key, the passphrase may be wrong")
+		}
+		return nil, err
 	}
 
-	return rsaKey, err
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("only ecdsa keys on the P-256 curve are supported")
+		}
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, errors.New("only rsa, ecdsa and ed25519 keys are supported")
+	}
 }
@@ -17,20 +17,31 @@ package authorization
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
 	"encoding/pem"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/jws"
 )
 
 func TestBasicAuthenticator(t *testing.T) {
@@ -168,11 +179,84 @@ func testServerForServiceAccount(t *testing.T) *httptest.Server {
 	})
 }
 
+func TestJWTTokenKeyTypes(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		keyData           string
+		expectedAlgorithm string
+		expectedErr       string
+	}{
+		"rsa key": {
+			keyData:           encodeTestKey(t, rsaKey),
+			expectedAlgorithm: "RS256",
+		},
+		"ecdsa key": {
+			keyData:           encodeTestKey(t, ecKey),
+			expectedAlgorithm: "ES256",
+		},
+		"ed25519 key": {
+			keyData:           encodeTestKey(t, edKey),
+			expectedAlgorithm: "EdDSA",
+		},
+		"ecdsa key on unsupported curve": {
+			keyData:     encodeTestKey(t, p384Key),
+			expectedErr: "only ecdsa keys on the P-256 curve are supported",
+		},
+		"not a pem": {
+			keyData:     base64.StdEncoding.EncodeToString([]byte("not a pem")),
+			expectedErr: "private key data is not a valid pem block",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			key, err := privateKeyFromBase64(testCase.keyData)
+			if len(testCase.expectedErr) > 0 {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				assertion := r.PostForm.Get("client_assertion")
+				header, err := base64.RawURLEncoding.DecodeString(strings.Split(assertion, ".")[0])
+				require.NoError(t, err)
+				assert.Contains(t, string(header), fmt.Sprintf("\"alg\":\"%s\"", testCase.expectedAlgorithm))
+				assert.NoError(t, jws.VerifyWithKey(assertion, key.(crypto.Signer).Public()))
+				w.Header().Add("Content-Type", "application/json")
+				w.Write([]byte("{\"access_token\":\"new\",\"token_type\":\"Bearer\",\"expires_in\":3600}"))
+			})
+			defer server.Close()
+
+			restClient, err := client.APIClientForConfig(&client.Config{Host: server.URL, Transport: http.DefaultTransport})
+			require.NoError(t, err)
+			token, err := getJWTToken(t.Context(), restClient, "miactl", "id", key)
+			require.NoError(t, err)
+			assert.Equal(t, "new", token.AccessToken)
+		})
+	}
+}
+
 func testKeyData(t *testing.T) string {
 	t.Helper()
 
 	key, err := rsa.GenerateKey(rand.Reader, 4096)
 	require.NoError(t, err)
+	return encodeTestKey(t, key)
+}
+
+func encodeTestKey(t *testing.T, key any) string {
+	t.Helper()
+
 	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
 	require.NoError(t, err)
 
@@ -183,3 +267,138 @@ func testKeyData(t *testing.T) string {
 
 	return base64.StdEncoding.EncodeToString(der)
 }
+
+func TestPrivateKeyFromFile(t *testing.T) {
+	plainKeyFile := filepath.Join(t.TempDir(), "key.pem")
+	keyData, err := base64.StdEncoding.DecodeString(testKeyData(t))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(plainKeyFile, keyData, 0600))
+
+	testCases := map[string]struct {
+		path        string
+		passphrase  string
+		expectedErr string
+	}{
+		"plain key": {
+			path: plainKeyFile,
+		},
+		"encrypted key": {
+			path:       filepath.Join("testdata", "encrypted-key.pem"),
+			passphrase: "miactl",
+		},
+		"encrypted key with sha1 prf": {
+			path:       filepath.Join("testdata", "encrypted-key-sha1.pem"),
+			passphrase: "miactl",
+		},
+		"wrong passphrase": {
+			path:        filepath.Join("testdata", "encrypted-key.pem"),
+			passphrase:  "wrong",
+			expectedErr: "cannot decrypt the private key, the passphrase may be wrong",
+		},
+		"missing file": {
+			path:        filepath.Join(t.TempDir(), "missing.pem"),
+			expectedErr: "cannot read the private key file",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			passphraseCalled := false
+			key, err := privateKeyFromFile(testCase.path, func() (string, error) {
+				passphraseCalled = true
+				return testCase.passphrase, nil
+			})
+			assert.Equal(t, len(testCase.passphrase) > 0, passphraseCalled)
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Implements(t, (*crypto.Signer)(nil), key)
+		})
+	}
+}
+
+func TestDeriveKeyIterationCount(t *testing.T) {
+	testCases := map[string]struct {
+		iterations  int
+		expectedErr string
+	}{
+		"default openssl iterations": {
+			iterations: 2048,
+		},
+		"zero iterations": {
+			iterations:  0,
+			expectedErr: "the iteration count must be between 1 and",
+		},
+		"too many iterations": {
+			iterations:  maxPBKDF2Iterations + 1,
+			expectedErr: "the iteration count must be between 1 and",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			params, err := asn1.Marshal(pbkdf2Params{
+				Salt:           []byte("saltsalt"),
+				IterationCount: testCase.iterations,
+				PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+			})
+			require.NoError(t, err)
+
+			kdf := pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: params}}
+			key, err := deriveKey(kdf, "miactl", 32)
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, key, 32)
+		})
+	}
+}
+
+func TestReadPassphraseWithoutTerminal(t *testing.T) {
+	stdin, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer stdin.Close()
+
+	originalStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = originalStdin }()
+
+	out := new(bytes.Buffer)
+	ua := &serviceAccountAuthenticator{privateKeyFile: "key.pem", out: out}
+	_, err = ua.readPassphrase()
+	assert.ErrorContains(t, err, "MIACTL_JWT_PRIVATE_KEY_PASSPHRASE")
+	assert.Empty(t, out.String())
+
+	ua.passphrase = "miactl"
+	passphrase, err := ua.readPassphrase()
+	require.NoError(t, err)
+	assert.Equal(t, "miactl", passphrase)
+}
+
+func TestJWTAuthenticatorWithKeyFile(t *testing.T) {
+	server := testServerForServiceAccount(t)
+	defer server.Close()
+
+	restClient, err := client.APIClientForConfig(&client.Config{Host: server.URL, Transport: http.DefaultTransport})
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	ua := &serviceAccountAuthenticator{
+		userAuth:       &testAuthCacheProvider{expired: true},
+		client:         restClient,
+		clientID:       "id",
+		keyID:          "miactl",
+		privateKeyFile: filepath.Join("testdata", "encrypted-key.pem"),
+		in:             strings.NewReader("miactl\n"),
+		out:            out,
+	}
+
+	token, err := ua.AccessToken()
+	require.NoError(t, err)
+	assert.Equal(t, "new", token.AccessToken)
+	assert.Contains(t, out.String(), "Enter the passphrase for the private key")
+}
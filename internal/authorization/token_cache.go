@@ -0,0 +1,61 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+// lockCache acquire the lock shared between processes of cache if it support it, and return the function
+// for releasing it
+func lockCache(cache client.AuthCacheReadWriter) func() {
+	locker, ok := cache.(client.AuthCacheLocker)
+	if !ok {
+		return func() {}
+	}
+
+	unlock, err := locker.LockJWTToken()
+	if err != nil {
+		// without the lock the worst case is that more processes will refresh the same token
+		return func() {}
+	}
+	return unlock
+}
+
+// tokenValid return true if token is usable and will not expire within window
+func tokenValid(token *oauth2.Token, window time.Duration) bool {
+	if token == nil || len(token.AccessToken) == 0 {
+		return false
+	}
+
+	if token.Expiry.IsZero() {
+		return true
+	}
+
+	return time.Until(token.Expiry) > window
+}
+
+// refreshWindowForConfig return the refresh window to use for the tokens of config
+func refreshWindowForConfig(config *client.Config) time.Duration {
+	if config.TokenRefreshWindow > 0 {
+		return config.TokenRefreshWindow
+	}
+	return client.DefaultTokenRefreshWindow
+}
@@ -0,0 +1,153 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+// testLockingCache is an in memory cache that record how many times it has been locked
+type testLockingCache struct {
+	token   *oauth2.Token
+	locks   int
+	written bool
+}
+
+func (c *testLockingCache) ReadJWTToken() *oauth2.Token { return c.token }
+
+func (c *testLockingCache) WriteJWTToken(token *oauth2.Token) {
+	c.token = token
+	c.written = true
+}
+func (c *testLockingCache) LockJWTToken() (func(), error) {
+	c.locks++
+	return func() {}, nil
+}
+
+func TestTokenValid(t *testing.T) {
+	testCases := map[string]struct {
+		token    *oauth2.Token
+		window   time.Duration
+		expected bool
+	}{
+		"nil token": {
+			expected: false,
+		},
+		"empty access token": {
+			token:    &oauth2.Token{Expiry: time.Now().Add(time.Hour)},
+			expected: false,
+		},
+		"without expiry": {
+			token:    &oauth2.Token{AccessToken: "token"},
+			window:   time.Hour,
+			expected: true,
+		},
+		"expiring outside the window": {
+			token:    &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(10 * time.Minute)},
+			window:   5 * time.Minute,
+			expected: true,
+		},
+		"expiring inside the window": {
+			token:    &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(2 * time.Minute)},
+			window:   5 * time.Minute,
+			expected: false,
+		},
+		"expired": {
+			token:    &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(-time.Minute)},
+			expected: false,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, tokenValid(testCase.token, testCase.window))
+		})
+	}
+}
+
+func TestRefreshWindowForConfig(t *testing.T) {
+	assert.Equal(t, client.DefaultTokenRefreshWindow, refreshWindowForConfig(&client.Config{}))
+	assert.Equal(t, 5*time.Minute, refreshWindowForConfig(&client.Config{TokenRefreshWindow: 5 * time.Minute}))
+}
+
+func TestUserAuthenticatorProactiveRefresh(t *testing.T) {
+	testCases := map[string]struct {
+		refreshFails  bool
+		expectedToken string
+	}{
+		"token refreshed before its expiration": {
+			expectedToken: "refreshed",
+		},
+		"refresh fails and the token is still valid": {
+			refreshFails:  true,
+			expectedToken: "near-expiration",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, refreshTokenEndpointString, r.RequestURI)
+				if testCase.refreshFails {
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"statusCode":401,"message":"expired"}`))
+					return
+				}
+				err := json.NewEncoder(w).Encode(&resources.UserToken{
+					AccessToken:  "refreshed",
+					RefreshToken: "refresh",
+					ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+				})
+				assert.NoError(t, err)
+			})
+			defer server.Close()
+
+			restClient, err := client.APIClientForConfig(&client.Config{Host: server.URL, Transport: http.DefaultTransport})
+			require.NoError(t, err)
+
+			cache := &testLockingCache{token: &oauth2.Token{
+				AccessToken:  "near-expiration",
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(2 * time.Minute),
+			}}
+			ua := &userAuthenticator{
+				userAuth:      cache,
+				client:        restClient,
+				refreshWindow: 5 * time.Minute,
+				serverReadyHandler: func(string) error {
+					assert.Fail(t, "a new login must not be started")
+					return nil
+				},
+			}
+
+			token, err := ua.AccessToken()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedToken, token.AccessToken)
+			assert.Equal(t, 1, cache.locks)
+			assert.Equal(t, !testCase.refreshFails, cache.written)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"net/http"
 	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
 
@@ -37,6 +38,8 @@ type userAuthenticator struct {
 	client             client.Interface
 	next               http.RoundTripper
 	serverReadyHandler LocalServerReadyHandler
+	loginMode          string
+	refreshWindow      time.Duration
 }
 
 func (ua *userAuthenticator) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -47,24 +50,33 @@ func (ua *userAuthenticator) AccessToken() (*oauth2.Token, error) {
 	ua.mutex.Lock()
 	defer ua.mutex.Unlock()
 
+	// hold the lock until the new token is saved, so other processes will wait and then reuse it
+	unlock := lockCache(ua.userAuth)
+	defer unlock()
+
 	jwt := ua.userAuth.ReadJWTToken()
 
-	if jwt.Valid() {
+	if tokenValid(jwt, ua.refreshWindow) {
 		return jwt, nil
 	}
 
 	if refreshToken := jwt.RefreshToken; len(refreshToken) > 0 {
-		return ua.refreshAuthWithToken(refreshToken)
+		return ua.refreshAuthWithToken(jwt)
 	}
 
 	return ua.logUser()
 }
 
-func (ua *userAuthenticator) refreshAuthWithToken(refreshToken string) (*oauth2.Token, error) {
-	if token, err := ua.refreshToken(refreshToken); err == nil {
+func (ua *userAuthenticator) refreshAuthWithToken(jwt *oauth2.Token) (*oauth2.Token, error) {
+	if token, err := ua.refreshToken(jwt.RefreshToken); err == nil {
 		return token, nil
 	}
 
+	// the token is only near to its expiration, avoid to start a new login
+	if jwt.Valid() {
+		return jwt, nil
+	}
+
 	return ua.logUser()
 }
 
@@ -76,7 +88,17 @@ func (ua *userAuthenticator) logUser() (*oauth2.Token, error) {
 		ServerReadyHandler:     ua.serverReadyHandler,
 	}
 
-	jwt, err := browserLoginConfig.GetToken(context.Background())
+	var jwt *oauth2.Token
+	var err error
+	switch ua.loginMode {
+	case client.LoginModeDevice:
+		jwt, err = browserLoginConfig.GetDeviceToken(context.Background())
+	case client.LoginModePaste:
+		jwt, err = browserLoginConfig.GetPastedToken(context.Background())
+	default:
+		jwt, err = browserLoginConfig.GetToken(context.Background())
+	}
+
 	if jwt != nil {
 		ua.userAuth.WriteJWTToken(jwt)
 	}
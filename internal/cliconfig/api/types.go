@@ -15,32 +15,94 @@
 
 package api
 
+const (
+	// ConfigAPIVersion is the version of the config file format written by this version of miactl
+	ConfigAPIVersion = "v1"
+	// ConfigKind is the kind used for identifying a miactl config file
+	ConfigKind = "Config"
+)
+
 type Config struct {
-	Contexts       map[string]*ContextConfig `yaml:"contexts"`
-	CurrentContext string                    `yaml:"current-context"` //nolint:tagliatelle
-	Auth           map[string]*AuthConfig    `yaml:"credentials"`     //nolint:tagliatelle
+	APIVersion      string                    `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind            string                    `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Contexts        map[string]*ContextConfig `json:"contexts" yaml:"contexts"`
+	CurrentContext  string                    `json:"current-context" yaml:"current-context"` //nolint:tagliatelle
+	Auth            map[string]*AuthConfig    `json:"credentials" yaml:"credentials"`         //nolint:tagliatelle
+	Preferences     *Preferences              `json:"preferences,omitempty" yaml:"preferences,omitempty"`
+	CacheEncryption *CacheEncryption          `json:"cache-encryption,omitempty" yaml:"cache-encryption,omitempty"` //nolint:tagliatelle
 }
 
 type ContextConfig struct {
-	Endpoint              string `yaml:"endpoint"`
-	CertificateAuthority  string `yaml:"certificate-authority,omitempty"`    //nolint:tagliatelle
-	InsecureSkipTLSVerify bool   `yaml:"insecure-skip-tls-verify,omitempty"` //nolint:tagliatelle
-	CompanyID             string `yaml:"company-id,omitempty"`               //nolint:tagliatelle
-	ProjectID             string `yaml:"project-id,omitempty"`               //nolint:tagliatelle
-	AuthName              string `yaml:"credential,omitempty"`               //nolint:tagliatelle
-	Environment           string `yaml:"environment,omitempty"`
+	Endpoint              string `json:"endpoint" yaml:"endpoint"`
+	CertificateAuthority  string `json:"certificate-authority,omitempty" yaml:"certificate-authority,omitempty"`       //nolint:tagliatelle
+	InsecureSkipTLSVerify bool   `json:"insecure-skip-tls-verify,omitempty" yaml:"insecure-skip-tls-verify,omitempty"` //nolint:tagliatelle
+	CompanyID             string `json:"company-id,omitempty" yaml:"company-id,omitempty"`                             //nolint:tagliatelle
+	ProjectID             string `json:"project-id,omitempty" yaml:"project-id,omitempty"`                             //nolint:tagliatelle
+	AuthName              string `json:"credential,omitempty" yaml:"credential,omitempty"`                             //nolint:tagliatelle
+	Environment           string `json:"environment,omitempty" yaml:"environment,omitempty"`
+	LoginMode             string `json:"login-mode,omitempty" yaml:"login-mode,omitempty"`                           //nolint:tagliatelle
+	ClientCertificate     string `json:"client-certificate,omitempty" yaml:"client-certificate,omitempty"`           //nolint:tagliatelle
+	ClientCertificateData string `json:"client-certificate-data,omitempty" yaml:"client-certificate-data,omitempty"` //nolint:tagliatelle
+	ClientKey             string `json:"client-key,omitempty" yaml:"client-key,omitempty"`                           //nolint:tagliatelle
+	ClientKeyData         string `json:"client-key-data,omitempty" yaml:"client-key-data,omitempty"`                 //nolint:tagliatelle
+	ProxyURL              string `json:"proxy-url,omitempty" yaml:"proxy-url,omitempty"`                             //nolint:tagliatelle
+	NoProxy               string `json:"no-proxy,omitempty" yaml:"no-proxy,omitempty"`                               //nolint:tagliatelle
+
+	Preferences *Preferences `json:"preferences,omitempty" yaml:"preferences,omitempty"`
 }
 
 type AuthConfig struct {
-	ClientID          string `yaml:"client-id,omitempty"`        //nolint:tagliatelle
-	ClientSecret      string `yaml:"client-secret,omitempty"`    //nolint:tagliatelle
-	JWTKeyID          string `yaml:"key-id,omitempty"`           //nolint:tagliatelle
-	JWTPrivateKeyData string `yaml:"private-key-data,omitempty"` //nolint:tagliatelle
+	ClientID          string      `json:"client-id,omitempty" yaml:"client-id,omitempty"`               //nolint:tagliatelle
+	ClientSecret      string      `json:"client-secret,omitempty" yaml:"client-secret,omitempty"`       //nolint:tagliatelle
+	JWTKeyID          string      `json:"key-id,omitempty" yaml:"key-id,omitempty"`                     //nolint:tagliatelle
+	JWTPrivateKeyData string      `json:"private-key-data,omitempty" yaml:"private-key-data,omitempty"` //nolint:tagliatelle
+	JWTPrivateKeyFile string      `json:"private-key-file,omitempty" yaml:"private-key-file,omitempty"` //nolint:tagliatelle
+	Exec              *ExecConfig `json:"exec,omitempty" yaml:"exec,omitempty"`
+}
+
+// Preferences contains the defaults applied to the commands when the corresponding flag is not set
+type Preferences struct {
+	OutputFormat              string `json:"output-format,omitempty" yaml:"output-format,omitempty"` //nolint:tagliatelle
+	WrapLines                 *bool  `json:"wrap-lines,omitempty" yaml:"wrap-lines,omitempty"`       //nolint:tagliatelle
+	Revision                  string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	Branch                    string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	DeployType                string `json:"deploy-type,omitempty" yaml:"deploy-type,omitempty"`                                 //nolint:tagliatelle
+	ConfirmDestructiveActions *bool  `json:"confirm-destructive-actions,omitempty" yaml:"confirm-destructive-actions,omitempty"` //nolint:tagliatelle
+	TokenRefreshWindow        string `json:"token-refresh-window,omitempty" yaml:"token-refresh-window,omitempty"`               //nolint:tagliatelle
+	RetryMaxAttempts          int    `json:"retry-max-attempts,omitempty" yaml:"retry-max-attempts,omitempty"`                   //nolint:tagliatelle
+	RetryMaxDelay             string `json:"retry-max-delay,omitempty" yaml:"retry-max-delay,omitempty"`                         //nolint:tagliatelle
+	AuditFile                 string `json:"audit-file,omitempty" yaml:"audit-file,omitempty"`                                   //nolint:tagliatelle
+}
+
+// CacheEncryption contains the source of the key used for encrypting the cached access tokens, only one
+// of the sources can be set
+type CacheEncryption struct {
+	// KeyEnv is the name of the environment variable containing the key
+	KeyEnv string `json:"key-env,omitempty" yaml:"key-env,omitempty"` //nolint:tagliatelle
+	// KeyFile is the path of the file containing the key
+	KeyFile string `json:"key-file,omitempty" yaml:"key-file,omitempty"` //nolint:tagliatelle
+	// KeyExec is a command that will print the key on its standard output
+	KeyExec *ExecConfig `json:"key-exec,omitempty" yaml:"key-exec,omitempty"` //nolint:tagliatelle
+}
+
+// ExecConfig contains the command to run for retrieving an access token from an external credential plugin
+type ExecConfig struct {
+	Command string       `json:"command" yaml:"command"`
+	Args    []string     `json:"args,omitempty" yaml:"args,omitempty"`
+	Env     []ExecEnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// ExecEnvVar is an additional environment variable to set when running an exec credential plugin
+type ExecEnvVar struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
 }
 
 func NewConfig() *Config {
 	return &Config{
-		Contexts: make(map[string]*ContextConfig),
-		Auth:     make(map[string]*AuthConfig),
+		APIVersion: ConfigAPIVersion,
+		Kind:       ConfigKind,
+		Contexts:   make(map[string]*ContextConfig),
+		Auth:       make(map[string]*AuthConfig),
 	}
 }
@@ -23,6 +23,11 @@ package api
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthConfig) DeepCopyInto(out *AuthConfig) {
 	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -36,6 +41,27 @@ func (in *AuthConfig) DeepCopy() *AuthConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheEncryption) DeepCopyInto(out *CacheEncryption) {
+	*out = *in
+	if in.KeyExec != nil {
+		in, out := &in.KeyExec, &out.KeyExec
+		*out = new(ExecConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheEncryption.
+func (in *CacheEncryption) DeepCopy() *CacheEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -49,7 +75,7 @@ func (in *Config) DeepCopyInto(out *Config) {
 			} else {
 				in, out := &val, &outVal
 				*out = new(ContextConfig)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 			(*out)[key] = outVal
 		}
@@ -64,11 +90,21 @@ func (in *Config) DeepCopyInto(out *Config) {
 			} else {
 				in, out := &val, &outVal
 				*out = new(AuthConfig)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 			(*out)[key] = outVal
 		}
 	}
+	if in.Preferences != nil {
+		in, out := &in.Preferences, &out.Preferences
+		*out = new(Preferences)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheEncryption != nil {
+		in, out := &in.CacheEncryption, &out.CacheEncryption
+		*out = new(CacheEncryption)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -85,6 +121,11 @@ func (in *Config) DeepCopy() *Config {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContextConfig) DeepCopyInto(out *ContextConfig) {
 	*out = *in
+	if in.Preferences != nil {
+		in, out := &in.Preferences, &out.Preferences
+		*out = new(Preferences)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -97,3 +138,71 @@ func (in *ContextConfig) DeepCopy() *ContextConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecConfig) DeepCopyInto(out *ExecConfig) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ExecEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecConfig.
+func (in *ExecConfig) DeepCopy() *ExecConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecEnvVar) DeepCopyInto(out *ExecEnvVar) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecEnvVar.
+func (in *ExecEnvVar) DeepCopy() *ExecEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Preferences) DeepCopyInto(out *Preferences) {
+	*out = *in
+	if in.WrapLines != nil {
+		in, out := &in.WrapLines, &out.WrapLines
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConfirmDestructiveActions != nil {
+		in, out := &in.ConfirmDestructiveActions, &out.ConfirmDestructiveActions
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Preferences.
+func (in *Preferences) DeepCopy() *Preferences {
+	if in == nil {
+		return nil
+	}
+	out := new(Preferences)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -22,6 +22,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"golang.org/x/oauth2"
 
@@ -32,6 +35,11 @@ type AuthReadWriter struct {
 	locator *ConfigPathLocator
 	config  *api.ContextConfig
 	auth    *api.AuthConfig
+
+	// locked is true while the lock returned by LockJWTToken is held, for avoiding to lock the cache again
+	// inside ReadJWTToken and WriteJWTToken
+	mutex  sync.Mutex
+	locked bool
 }
 
 func NewAuthReadWriter(locator *ConfigPathLocator, config *api.ContextConfig, auth *api.AuthConfig) *AuthReadWriter {
@@ -42,42 +50,197 @@ func NewAuthReadWriter(locator *ConfigPathLocator, config *api.ContextConfig, au
 	}
 }
 
-func (rw *AuthReadWriter) ReadJWTToken() *oauth2.Token {
-	cacheKey := cacheKeyForConfig(rw.config, rw.auth)
-	tokenPath := filepath.Join(CacheFolderPath(), cacheKey)
-	tokenData, err := os.ReadFile(tokenPath)
+// LockJWTToken acquire an exclusive lock on the cached token shared with the other miactl processes, and
+// return the function for releasing it
+func (rw *AuthReadWriter) LockJWTToken() (func(), error) {
+	file, err := rw.lockCache(true)
 	if err != nil {
-		return &oauth2.Token{}
+		return nil, err
 	}
 
-	decoder := json.NewDecoder(bytes.NewBuffer(tokenData))
-	jwt := new(oauth2.Token)
-	if err := decoder.Decode(&jwt); err != nil {
+	rw.setLocked(true)
+	return func() {
+		rw.setLocked(false)
+		unlockFile(file)
+	}, nil
+}
+
+func (rw *AuthReadWriter) ReadJWTToken() *oauth2.Token {
+	tokenData, err := rw.readCacheFile(rw.tokenPath())
+	if err != nil {
 		return &oauth2.Token{}
 	}
-	return jwt
+
+	return decodeToken(tokenData)
 }
 
 func (rw *AuthReadWriter) WriteJWTToken(jwt *oauth2.Token) {
-	cacheKey := cacheKeyForConfig(rw.config, rw.auth)
-	tokenPath := filepath.Join(CacheFolderPath(), cacheKey)
-	dir := filepath.Dir(tokenPath)
+	tokenData, err := encodeToken(jwt)
+	if err != nil {
+		return
+	}
+
+	_ = rw.writeCacheFile(rw.tokenPath(), tokenData)
+}
+
+// readCacheFile return the content of the cache file at path, holding a shared lock if the cache is not
+// already locked
+func (rw *AuthReadWriter) readCacheFile(path string) ([]byte, error) {
+	if !rw.isLocked() {
+		if file, err := rw.lockCache(false); err == nil {
+			defer unlockFile(file)
+		}
+	}
+
+	return os.ReadFile(path)
+}
+
+// writeCacheFile save data in the cache file at path, holding an exclusive lock if the cache is not
+// already locked
+func (rw *AuthReadWriter) writeCacheFile(path string, data []byte) error {
+	if !rw.isLocked() {
+		file, err := rw.lockCache(true)
+		if err != nil {
+			return err
+		}
+		defer unlockFile(file)
+	}
+
+	return writeFileAtomically(path, data)
+}
+
+func (rw *AuthReadWriter) tokenPath() string {
+	return filepath.Join(CacheFolderPath(), cacheKeyForConfig(rw.config, rw.auth))
+}
+
+// lockCache acquire a shared or exclusive lock on the lock file of the cached token, the cache folder is
+// created only for exclusive locks
+func (rw *AuthReadWriter) lockCache(exclusive bool) (*os.File, error) {
+	dir := CacheFolderPath()
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if !exclusive {
+			return nil, err
+		}
 		if err = os.MkdirAll(dir, 0755); err != nil {
-			return
+			return nil, err
+		}
+	}
+
+	return lockFile(rw.tokenPath()+lockFileSuffix, exclusive)
+}
+
+func (rw *AuthReadWriter) setLocked(locked bool) {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+	rw.locked = locked
+}
+
+func (rw *AuthReadWriter) isLocked() bool {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+	return rw.locked
+}
+
+// writeFileAtomically write data in a temporary file in the same folder of path and then rename it, so
+// that no other process can read a partially written file
+func writeFileAtomically(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tempPath := file.Name()
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tempPath)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteJWTToken remove the cached token of the context, return false if no token was cached
+func (rw *AuthReadWriter) DeleteJWTToken() (bool, error) {
+	err := os.Remove(rw.tokenPath())
+	switch {
+	case os.IsNotExist(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
+
+// DeleteAllJWTTokens remove the cached tokens of every context; the lock files and the files still being
+// written are left in place, because other miactl processes can be using them
+func DeleteAllJWTTokens() error {
+	entries, err := os.ReadDir(CacheFolderPath())
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, lockFileSuffix) || strings.Contains(name, ".tmp-") {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(CacheFolderPath(), name)); err != nil && !os.IsNotExist(err) {
+			return err
 		}
 	}
+	return nil
+}
+
+func decodeToken(data []byte) *oauth2.Token {
+	decoder := json.NewDecoder(bytes.NewBuffer(data))
+	jwt := new(oauth2.Token)
+	if err := decoder.Decode(&jwt); err != nil {
+		return &oauth2.Token{}
+	}
+	return jwt
+}
 
+func encodeToken(jwt *oauth2.Token) ([]byte, error) {
 	jwtBuffer := bytes.NewBuffer([]byte{})
 	encoder := json.NewEncoder(jwtBuffer)
-
 	if err := encoder.Encode(jwt); err != nil {
-		return
+		return nil, err
 	}
-	_ = os.WriteFile(tokenPath, jwtBuffer.Bytes(), 0600)
+	return jwtBuffer.Bytes(), nil
 }
 
 func cacheKeyForConfig(config *api.ContextConfig, auth *api.AuthConfig) string {
-	stringKey := config.Endpoint + auth.ClientID + auth.JWTKeyID + auth.JWTPrivateKeyData
+	stringKey := config.Endpoint + auth.ClientID + auth.JWTKeyID + auth.JWTPrivateKeyData + auth.JWTPrivateKeyFile
+	stringKey += execCacheKey(auth.Exec)
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(stringKey)))
 }
+
+// execCacheKey return the part of the cache key for an exec command: the same command can return the token
+// of a different identity depending on its environment, so the variables are part of the key
+func execCacheKey(exec *api.ExecConfig) string {
+	if exec == nil {
+		return ""
+	}
+
+	env := make([]string, 0, len(exec.Env))
+	for _, envVar := range exec.Env {
+		env = append(env, envVar.Name+"="+envVar.Value)
+	}
+	sort.Strings(env)
+	return exec.Command + strings.Join(exec.Args, " ") + strings.Join(env, "\n")
+}
@@ -0,0 +1,123 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+func TestAuthReadWriter(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	first := NewAuthReadWriter(nil, &api.ContextConfig{Endpoint: "https://first.example.com"}, &api.AuthConfig{})
+	second := NewAuthReadWriter(nil, &api.ContextConfig{Endpoint: "https://second.example.com"}, &api.AuthConfig{})
+
+	token := &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour).Round(time.Second)}
+	first.WriteJWTToken(token)
+	second.WriteJWTToken(token)
+	assert.Equal(t, "token", first.ReadJWTToken().AccessToken)
+
+	deleted, err := first.DeleteJWTToken()
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Empty(t, first.ReadJWTToken().AccessToken)
+	assert.Equal(t, "token", second.ReadJWTToken().AccessToken)
+
+	deleted, err = first.DeleteJWTToken()
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	require.NoError(t, DeleteAllJWTTokens())
+	assert.Empty(t, second.ReadJWTToken().AccessToken)
+	// the lock files can be held by other processes and must survive
+	_, err = os.Stat(second.tokenPath() + lockFileSuffix)
+	assert.NoError(t, err)
+
+	t.Run("missing cache folder", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", t.TempDir())
+		assert.NoError(t, DeleteAllJWTTokens())
+	})
+}
+
+func TestAuthReadWriterLock(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	contextConfig := &api.ContextConfig{Endpoint: "https://example.com"}
+	first := NewAuthReadWriter(nil, contextConfig, &api.AuthConfig{})
+	second := NewAuthReadWriter(nil, contextConfig, &api.AuthConfig{})
+
+	unlock, err := first.LockJWTToken()
+	require.NoError(t, err)
+
+	// reading and writing while holding the lock must not block
+	first.WriteJWTToken(&oauth2.Token{AccessToken: "first"})
+	assert.Equal(t, "first", first.ReadJWTToken().AccessToken)
+
+	acquired := make(chan struct{})
+	go func() {
+		secondUnlock, err := second.LockJWTToken()
+		assert.NoError(t, err)
+		close(acquired)
+		second.WriteJWTToken(&oauth2.Token{AccessToken: "second"})
+		secondUnlock()
+	}()
+
+	select {
+	case <-acquired:
+		assert.Fail(t, "the lock has been acquired while held by another reader")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the lock has not been acquired after its release")
+	}
+
+	assert.Eventually(t, func() bool {
+		return first.ReadJWTToken().AccessToken == "second"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entries, err := os.ReadDir(CacheFolderPath())
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".tmp-", "temporary files must be renamed or removed")
+	}
+}
+
+func TestCacheKeyForConfig(t *testing.T) {
+	contextConfig := &api.ContextConfig{Endpoint: "https://example.com"}
+	execAuth := func(env ...api.ExecEnvVar) *api.AuthConfig {
+		return &api.AuthConfig{Exec: &api.ExecConfig{Command: "vault", Args: []string{"read"}, Env: env}}
+	}
+
+	reader := execAuth(api.ExecEnvVar{Name: "VAULT_ROLE", Value: "reader"}, api.ExecEnvVar{Name: "VAULT_ADDR", Value: "addr"})
+	sameReader := execAuth(api.ExecEnvVar{Name: "VAULT_ADDR", Value: "addr"}, api.ExecEnvVar{Name: "VAULT_ROLE", Value: "reader"})
+	admin := execAuth(api.ExecEnvVar{Name: "VAULT_ROLE", Value: "admin"}, api.ExecEnvVar{Name: "VAULT_ADDR", Value: "addr"})
+
+	assert.Equal(t, cacheKeyForConfig(contextConfig, reader), cacheKeyForConfig(contextConfig, sameReader))
+	assert.NotEqual(t, cacheKeyForConfig(contextConfig, reader), cacheKeyForConfig(contextConfig, admin))
+	assert.NotEqual(t, cacheKeyForConfig(contextConfig, reader), cacheKeyForConfig(contextConfig, execAuth()))
+}
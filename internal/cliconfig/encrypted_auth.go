@@ -0,0 +1,219 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/logger"
+)
+
+const encryptedCacheSuffix = ".enc"
+
+// EncryptedAuthReadWriter is an AuthReadWriter that encrypt the cached tokens with AES-GCM
+type EncryptedAuthReadWriter struct {
+	*AuthReadWriter
+
+	encryption *api.CacheEncryption
+	once       sync.Once
+	aead       cipher.AEAD
+	aeadErr    error
+}
+
+// NewEncryptedAuthReadWriter return a new EncryptedAuthReadWriter using the key read from the source set in
+// encryption; the key is read only when a token is read or written, so the commands that do not use the
+// cache, like the ones using a bearer token, work also when the key is not available
+func NewEncryptedAuthReadWriter(locator *ConfigPathLocator, config *api.ContextConfig, auth *api.AuthConfig, encryption *api.CacheEncryption) *EncryptedAuthReadWriter {
+	return &EncryptedAuthReadWriter{
+		AuthReadWriter: NewAuthReadWriter(locator, config, auth),
+		encryption:     encryption,
+	}
+}
+
+// loadCipher return the cipher built from the encryption key, the key is read only the first time; if
+// it cannot be read the cache is not used, and the error is logged once
+func (rw *EncryptedAuthReadWriter) loadCipher() (cipher.AEAD, error) {
+	rw.once.Do(func() {
+		rw.aead, rw.aeadErr = newCipher(rw.encryption)
+		if rw.aeadErr != nil {
+			logger.NewLogger(os.Stderr).Info(fmt.Sprintf("the access token cache is not used: %s", rw.aeadErr))
+		}
+	})
+	return rw.aead, rw.aeadErr
+}
+
+func (rw *EncryptedAuthReadWriter) ReadJWTToken() *oauth2.Token {
+	aead, err := rw.loadCipher()
+	if err != nil {
+		return &oauth2.Token{}
+	}
+
+	encryptedData, err := rw.readCacheFile(rw.encryptedTokenPath())
+	if err != nil {
+		return &oauth2.Token{}
+	}
+
+	tokenData, err := rw.decrypt(aead, encryptedData)
+	if err != nil {
+		return &oauth2.Token{}
+	}
+
+	return decodeToken(tokenData)
+}
+
+func (rw *EncryptedAuthReadWriter) WriteJWTToken(jwt *oauth2.Token) {
+	aead, err := rw.loadCipher()
+	if err != nil {
+		return
+	}
+
+	tokenData, err := encodeToken(jwt)
+	if err != nil {
+		return
+	}
+
+	if err := rw.writeCacheFile(rw.encryptedTokenPath(), rw.encrypt(aead, tokenData)); err != nil {
+		return
+	}
+
+	// remove the plaintext token saved before enabling the encryption
+	_ = os.Remove(rw.tokenPath())
+}
+
+// DeleteJWTToken remove the encrypted and the plaintext cached tokens of the context, return false if no
+// token was cached
+func (rw *EncryptedAuthReadWriter) DeleteJWTToken() (bool, error) {
+	deleted, err := rw.AuthReadWriter.DeleteJWTToken()
+	if err != nil {
+		return false, err
+	}
+
+	err = os.Remove(rw.encryptedTokenPath())
+	switch {
+	case os.IsNotExist(err):
+		return deleted, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
+
+func (rw *EncryptedAuthReadWriter) encryptedTokenPath() string {
+	return rw.tokenPath() + encryptedCacheSuffix
+}
+
+// encrypt return the nonce followed by data encrypted, the cache key is used as additional data so the
+// file cannot be reused for another context
+func (rw *EncryptedAuthReadWriter) encrypt(aead cipher.AEAD, data []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	_, _ = rand.Read(nonce)
+
+	additionalData := []byte(cacheKeyForConfig(rw.config, rw.auth))
+	return aead.Seal(nonce, nonce, data, additionalData)
+}
+
+func (rw *EncryptedAuthReadWriter) decrypt(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("encrypted token too short")
+	}
+
+	additionalData := []byte(cacheKeyForConfig(rw.config, rw.auth))
+	return aead.Open(nil, data[:nonceSize], data[nonceSize:], additionalData)
+}
+
+// newCipher return the AES-GCM cipher using the key read from the source set in encryption
+func newCipher(encryption *api.CacheEncryption) (cipher.AEAD, error) {
+	key, err := readEncryptionKey(encryption)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the cache encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// readEncryptionKey return the AES-256 key derived from the key material read from the source set in encryption
+func readEncryptionKey(encryption *api.CacheEncryption) ([]byte, error) {
+	sources := 0
+	for _, set := range []bool{len(encryption.KeyEnv) > 0, len(encryption.KeyFile) > 0, encryption.KeyExec != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, errors.New("exactly one of key-env, key-file and key-exec must be set")
+	}
+
+	var material []byte
+	var err error
+	switch {
+	case len(encryption.KeyEnv) > 0:
+		material = []byte(os.Getenv(encryption.KeyEnv))
+	case len(encryption.KeyFile) > 0:
+		material, err = os.ReadFile(encryption.KeyFile)
+	default:
+		material, err = runKeyExec(encryption.KeyExec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	material = bytes.TrimSpace(material)
+	if len(material) == 0 {
+		return nil, errors.New("the key is empty")
+	}
+
+	key := sha256.Sum256(material)
+	return key[:], nil
+}
+
+// runKeyExec run the command in config and return its standard output
+func runKeyExec(config *api.ExecConfig) ([]byte, error) {
+	if len(config.Command) == 0 {
+		return nil, errors.New("no command set in key-exec")
+	}
+
+	cmd := exec.Command(config.Command, config.Args...) // #nosec G204
+	cmd.Env = os.Environ()
+	for _, envVar := range config.Env {
+		cmd.Env = append(cmd.Env, envVar.Name+"="+envVar.Value)
+	}
+	cmd.Stderr = os.Stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", config.Command, err)
+	}
+	return output, nil
+}
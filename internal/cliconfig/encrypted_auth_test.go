@@ -0,0 +1,157 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+// TestKeyExecHelperProcess is not a real test, it is used as key-exec helper by the other tests
+func TestKeyExecHelperProcess(_ *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprintln(os.Stdout, os.Getenv("TEST_KEY_OUTPUT"))
+	os.Exit(0)
+}
+
+func TestReadEncryptionKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret\n"), 0600))
+	t.Setenv("TEST_CACHE_KEY", "secret")
+
+	testCases := map[string]struct {
+		encryption *api.CacheEncryption
+		expectErr  bool
+	}{
+		"key from env": {
+			encryption: &api.CacheEncryption{KeyEnv: "TEST_CACHE_KEY"},
+		},
+		"key from file": {
+			encryption: &api.CacheEncryption{KeyFile: keyFile},
+		},
+		"key from exec": {
+			encryption: &api.CacheEncryption{KeyExec: &api.ExecConfig{
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestKeyExecHelperProcess"},
+				Env: []api.ExecEnvVar{
+					{Name: "GO_WANT_HELPER_PROCESS", Value: "1"},
+					{Name: "TEST_KEY_OUTPUT", Value: "secret"},
+				},
+			}},
+		},
+		"empty key": {
+			encryption: &api.CacheEncryption{KeyEnv: "TEST_MISSING_CACHE_KEY"},
+			expectErr:  true,
+		},
+		"missing file": {
+			encryption: &api.CacheEncryption{KeyFile: filepath.Join(t.TempDir(), "missing")},
+			expectErr:  true,
+		},
+		"no source": {
+			encryption: &api.CacheEncryption{},
+			expectErr:  true,
+		},
+		"more sources": {
+			encryption: &api.CacheEncryption{KeyEnv: "TEST_CACHE_KEY", KeyFile: keyFile},
+			expectErr:  true,
+		},
+	}
+
+	var expectedKey []byte
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			key, err := readEncryptionKey(testCase.encryption)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, key, 32)
+			if expectedKey == nil {
+				expectedKey = key
+			}
+			// the same key material must produce the same key from every source
+			assert.Equal(t, expectedKey, key)
+		})
+	}
+}
+
+func TestEncryptedAuthReadWriter(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("TEST_CACHE_KEY", "secret")
+	t.Setenv("TEST_OTHER_CACHE_KEY", "other-secret")
+
+	contextConfig := &api.ContextConfig{Endpoint: "https://example.com"}
+	plaintext := NewAuthReadWriter(nil, contextConfig, &api.AuthConfig{})
+	plaintext.WriteJWTToken(&oauth2.Token{AccessToken: "plaintext-token"})
+
+	encrypted := NewEncryptedAuthReadWriter(nil, contextConfig, &api.AuthConfig{}, &api.CacheEncryption{KeyEnv: "TEST_CACHE_KEY"})
+	assert.Empty(t, encrypted.ReadJWTToken().AccessToken, "the plaintext token must not be read")
+
+	encrypted.WriteJWTToken(&oauth2.Token{AccessToken: "access-token", RefreshToken: "refresh-token"})
+	token := encrypted.ReadJWTToken()
+	assert.Equal(t, "access-token", token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
+
+	data, err := os.ReadFile(encrypted.encryptedTokenPath())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "refresh-token")
+	_, err = os.Stat(plaintext.tokenPath())
+	assert.True(t, os.IsNotExist(err), "the plaintext token must be removed")
+
+	otherKey := NewEncryptedAuthReadWriter(nil, contextConfig, &api.AuthConfig{}, &api.CacheEncryption{KeyEnv: "TEST_OTHER_CACHE_KEY"})
+	assert.Empty(t, otherKey.ReadJWTToken().AccessToken, "the token must not be decrypted with another key")
+
+	deleted, err := encrypted.DeleteJWTToken()
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Empty(t, encrypted.ReadJWTToken().AccessToken)
+}
+
+func TestEncryptedAuthReadWriterWithoutKey(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	contextConfig := &api.ContextConfig{Endpoint: "https://example.com"}
+	keyFile := filepath.Join(t.TempDir(), "missing-key")
+	encrypted := NewEncryptedAuthReadWriter(nil, contextConfig, &api.AuthConfig{}, &api.CacheEncryption{KeyFile: keyFile})
+
+	// the key is read only when the cache is used, and without it the cache is skipped
+	encrypted.WriteJWTToken(&oauth2.Token{AccessToken: "access-token"})
+	assert.Empty(t, encrypted.ReadJWTToken().AccessToken)
+	_, err := os.Stat(encrypted.encryptedTokenPath())
+	assert.True(t, os.IsNotExist(err))
+
+	// the key is read once, so a key that becomes available later is ignored by the same process
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret"), 0600))
+	encrypted.WriteJWTToken(&oauth2.Token{AccessToken: "access-token"})
+	assert.Empty(t, encrypted.ReadJWTToken().AccessToken)
+
+	deleted, err := encrypted.DeleteJWTToken()
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
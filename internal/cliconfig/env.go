@@ -0,0 +1,87 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// environment variables that can be used to override the values read from the config file
+const (
+	EndpointEnvVarName                = "MIACTL_ENDPOINT"
+	CertificateAuthorityEnvVarName    = "MIACTL_CERTIFICATE_AUTHORITY"
+	InsecureSkipTLSVerifyEnvVarName   = "MIACTL_INSECURE_SKIP_TLS_VERIFY"
+	CompanyIDEnvVarName               = "MIACTL_COMPANY_ID"
+	ProjectIDEnvVarName               = "MIACTL_PROJECT_ID"
+	EnvironmentEnvVarName             = "MIACTL_ENVIRONMENT"
+	ContextEnvVarName                 = "MIACTL_CONTEXT"
+	AuthNameEnvVarName                = "MIACTL_AUTH_NAME"
+	ClientIDEnvVarName                = "MIACTL_CLIENT_ID"
+	ClientSecretEnvVarName            = "MIACTL_CLIENT_SECRET" // #nosec G101
+	JWTKeyIDEnvVarName                = "MIACTL_JWT_KEY_ID"
+	JWTPrivateKeyDataEnvVarName       = "MIACTL_JWT_PRIVATE_KEY_DATA"
+	JWTPrivateKeyFileEnvVarName       = "MIACTL_JWT_PRIVATE_KEY_FILE"
+	JWTPrivateKeyPassphraseEnvVarName = "MIACTL_JWT_PRIVATE_KEY_PASSPHRASE" // #nosec G101
+	TokenEnvVarName                   = "MIACTL_TOKEN"                      // #nosec G101
+	LoginModeEnvVarName               = "MIACTL_LOGIN_MODE"
+	ClientCertificateEnvVarName       = "MIACTL_CLIENT_CERTIFICATE"
+	ClientCertificateDataEnvVarName   = "MIACTL_CLIENT_CERTIFICATE_DATA"
+	ClientKeyEnvVarName               = "MIACTL_CLIENT_KEY"
+	ClientKeyDataEnvVarName           = "MIACTL_CLIENT_KEY_DATA" // #nosec G101
+	ProxyURLEnvVarName                = "MIACTL_PROXY_URL"
+	NoProxyEnvVarName                 = "MIACTL_NO_PROXY"
+)
+
+// EnvOverrides return the ConfigOverrides read from the environment variables
+func EnvOverrides() (*ConfigOverrides, error) {
+	overrides := &ConfigOverrides{
+		Endpoint:             os.Getenv(EndpointEnvVarName),
+		CertificateAuthority: os.Getenv(CertificateAuthorityEnvVarName),
+		CompanyID:            os.Getenv(CompanyIDEnvVarName),
+		ProjectID:            os.Getenv(ProjectIDEnvVarName),
+		Environment:          os.Getenv(EnvironmentEnvVarName),
+		Context:              os.Getenv(ContextEnvVarName),
+		AuthName:             os.Getenv(AuthNameEnvVarName),
+		ClientID:             os.Getenv(ClientIDEnvVarName),
+		ClientSecret:         os.Getenv(ClientSecretEnvVarName),
+		JWTKeyID:             os.Getenv(JWTKeyIDEnvVarName),
+		JWTPrivateKeyData:    os.Getenv(JWTPrivateKeyDataEnvVarName),
+		JWTPrivateKeyFile:    os.Getenv(JWTPrivateKeyFileEnvVarName),
+		Token:                os.Getenv(TokenEnvVarName),
+		LoginMode:            os.Getenv(LoginModeEnvVarName),
+		ProxyURL:             os.Getenv(ProxyURLEnvVarName),
+		NoProxy:              os.Getenv(NoProxyEnvVarName),
+
+		ClientCertificate:     os.Getenv(ClientCertificateEnvVarName),
+		ClientCertificateData: os.Getenv(ClientCertificateDataEnvVarName),
+		ClientKey:             os.Getenv(ClientKeyEnvVarName),
+		ClientKeyData:         os.Getenv(ClientKeyDataEnvVarName),
+
+		JWTPrivateKeyPassphrase: os.Getenv(JWTPrivateKeyPassphraseEnvVarName),
+	}
+
+	if insecure := os.Getenv(InsecureSkipTLSVerifyEnvVarName); len(insecure) > 0 {
+		value, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", InsecureSkipTLSVerifyEnvVarName, err)
+		}
+		overrides.InsecureSkipTLSVerify = value
+	}
+
+	return overrides, nil
+}
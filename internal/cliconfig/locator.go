@@ -16,32 +16,40 @@
 package cliconfig
 
 import (
-	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
-
-	"sigs.k8s.io/kustomize/kyaml/yaml"
+	"reflect"
+	"slices"
 
 	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/logger"
 )
 
 const ConfigPathEnvVarName = "MIACONFIG"
 
 type ConfigPathLocator struct {
 	ExplicitPath string
+	// ReadOnly disable the upgrade on disk of the config files written by older versions of miactl
+	ReadOnly bool
 
-	filePath string
+	filePaths []string
 }
 
 func NewConfigPathLocator() *ConfigPathLocator {
-	filePath := ConfigFilePath()
+	filePaths := []string{ConfigFilePath()}
 
 	if envVarFiles := os.Getenv(ConfigPathEnvVarName); len(envVarFiles) != 0 {
-		filePath = filepath.SplitList(envVarFiles)[0]
+		filePaths = make([]string, 0)
+		for _, path := range filepath.SplitList(envVarFiles) {
+			if len(path) > 0 && !slices.Contains(filePaths, path) {
+				filePaths = append(filePaths, path)
+			}
+		}
 	}
 
 	return &ConfigPathLocator{
-		filePath: filePath,
+		filePaths: filePaths,
 	}
 }
 
@@ -49,23 +57,222 @@ func (cr *ConfigPathLocator) DefaultConfigPath() string {
 	return ConfigFilePathString()
 }
 
+// ReadConfig return the configuration read from the selected files; if more than one file
+// is selected, contexts and credentials are merged and the first file defining an entry wins
 func (cr *ConfigPathLocator) ReadConfig() (*api.Config, error) {
-	return readFile(cr.ConfigLocation())
+	paths := cr.ConfigLocations()
+	if len(paths) == 1 {
+		return readFile(paths[0], !cr.ReadOnly)
+	}
+
+	configs, err := readFiles(paths, !cr.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeConfigs(paths, configs), nil
 }
 
+// WriteConfig save config on the selected files; if more than one file is selected, every entry is
+// saved in the file that has defined it, and new entries are saved in the first file
 func (cr *ConfigPathLocator) WriteConfig(config *api.Config) error {
-	return writeFile(cr.ConfigLocation(), config)
+	paths := cr.ConfigLocations()
+	if len(paths) == 1 {
+		return writeFile(paths[0], config)
+	}
+
+	configs, err := readFiles(paths, true)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range splitConfig(paths, configs, config) {
+		if err := writeFile(path, configs[path]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// ConfigLocation return the path of the file where new entries will be saved
 func (cr *ConfigPathLocator) ConfigLocation() string {
+	return cr.ConfigLocations()[0]
+}
+
+// ConfigLocations return all the paths of the files used for reading the configuration in order of precedence
+func (cr *ConfigPathLocator) ConfigLocations() []string {
 	if len(cr.ExplicitPath) > 0 {
-		return cr.ExplicitPath
+		return []string{cr.ExplicitPath}
 	}
 
-	return cr.filePath
+	if len(cr.filePaths) == 0 {
+		return []string{ConfigFilePath()}
+	}
+
+	return cr.filePaths
+}
+
+// readFiles read all the files in paths, returning a map of configs keyed by their path
+func readFiles(paths []string, upgrade bool) (map[string]*api.Config, error) {
+	configs := make(map[string]*api.Config, len(paths))
+	for _, path := range paths {
+		config, err := readFile(path, upgrade)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file %s: %w", path, err)
+		}
+		if config.Contexts == nil {
+			config.Contexts = make(map[string]*api.ContextConfig)
+		}
+		if config.Auth == nil {
+			config.Auth = make(map[string]*api.AuthConfig)
+		}
+		configs[path] = config
+	}
+
+	return configs, nil
 }
 
-func readFile(path string) (*api.Config, error) {
+// mergeConfigs return a new config merging all configs in paths order, the first value found wins
+func mergeConfigs(paths []string, configs map[string]*api.Config) *api.Config {
+	merged := api.NewConfig()
+	for _, path := range paths {
+		config := configs[path]
+		if len(merged.CurrentContext) == 0 {
+			merged.CurrentContext = config.CurrentContext
+		}
+		if merged.Preferences == nil {
+			merged.Preferences = config.Preferences
+		}
+		if merged.CacheEncryption == nil {
+			merged.CacheEncryption = config.CacheEncryption
+		}
+		for name, context := range config.Contexts {
+			if _, found := merged.Contexts[name]; !found {
+				merged.Contexts[name] = context
+			}
+		}
+		for name, auth := range config.Auth {
+			if _, found := merged.Auth[name]; !found {
+				merged.Auth[name] = auth
+			}
+		}
+	}
+
+	return merged
+}
+
+// splitConfig apply the changes found in config to configs, and return the paths of the modified ones
+func splitConfig(paths []string, configs map[string]*api.Config, config *api.Config) []string {
+	modified := make(map[string]bool)
+	contextOwner := func(name string) string {
+		return ownerPath(paths, func(path string) bool {
+			_, found := configs[path].Contexts[name]
+			return found
+		})
+	}
+	authOwner := func(name string) string {
+		return ownerPath(paths, func(path string) bool {
+			_, found := configs[path].Auth[name]
+			return found
+		})
+	}
+
+	for name, context := range config.Contexts {
+		path := contextOwner(name)
+		if !reflect.DeepEqual(configs[path].Contexts[name], context) {
+			configs[path].Contexts[name] = context
+			modified[path] = true
+		}
+	}
+
+	for name, auth := range config.Auth {
+		path := authOwner(name)
+		if !reflect.DeepEqual(configs[path].Auth[name], auth) {
+			configs[path].Auth[name] = auth
+			modified[path] = true
+		}
+	}
+
+	for _, path := range paths {
+		fileConfig := configs[path]
+		for name := range fileConfig.Contexts {
+			if _, found := config.Contexts[name]; !found {
+				delete(fileConfig.Contexts, name)
+				modified[path] = true
+			}
+		}
+		for name := range fileConfig.Auth {
+			if _, found := config.Auth[name]; !found {
+				delete(fileConfig.Auth, name)
+				modified[path] = true
+			}
+		}
+	}
+
+	for _, path := range splitCurrentContext(paths, configs, config.CurrentContext) {
+		modified[path] = true
+	}
+
+	// the top level sections are saved in the file that has defined them, like the contexts
+	preferencesPath := ownerPath(paths, func(path string) bool { return configs[path].Preferences != nil })
+	if !reflect.DeepEqual(configs[preferencesPath].Preferences, config.Preferences) {
+		configs[preferencesPath].Preferences = config.Preferences
+		modified[preferencesPath] = true
+	}
+	encryptionPath := ownerPath(paths, func(path string) bool { return configs[path].CacheEncryption != nil })
+	if !reflect.DeepEqual(configs[encryptionPath].CacheEncryption, config.CacheEncryption) {
+		configs[encryptionPath].CacheEncryption = config.CacheEncryption
+		modified[encryptionPath] = true
+	}
+
+	modifiedPaths := make([]string, 0, len(modified))
+	for _, path := range paths {
+		if modified[path] {
+			modifiedPaths = append(modifiedPaths, path)
+		}
+	}
+	return modifiedPaths
+}
+
+// splitCurrentContext save currentContext in the file that has already defined one, and return the paths
+// of the modified configs
+func splitCurrentContext(paths []string, configs map[string]*api.Config, currentContext string) []string {
+	modifiedPaths := make([]string, 0)
+	if len(currentContext) == 0 {
+		// unset the value in every file, to avoid that one defined in a later file will surface
+		for _, path := range paths {
+			if len(configs[path].CurrentContext) > 0 {
+				configs[path].CurrentContext = ""
+				modifiedPaths = append(modifiedPaths, path)
+			}
+		}
+		return modifiedPaths
+	}
+
+	path := ownerPath(paths, func(path string) bool { return len(configs[path].CurrentContext) > 0 })
+	if configs[path].CurrentContext != currentContext {
+		configs[path].CurrentContext = currentContext
+		modifiedPaths = append(modifiedPaths, path)
+	}
+	return modifiedPaths
+}
+
+// ownerPath return the first path for which definedIn return true, or the first path if none is found
+func ownerPath(paths []string, definedIn func(string) bool) string {
+	for _, path := range paths {
+		if definedIn(path) {
+			return path
+		}
+	}
+
+	return paths[0]
+}
+
+// readFile read the config saved in path, if upgrade is true and the file has been written by an older
+// version of miactl it will be upgraded on disk; a file that cannot be upgraded, like a read only one shared
+// by more users, is used as upgraded in memory
+func readFile(path string, upgrade bool) (*api.Config, error) {
 	configData, err := os.ReadFile(path)
 	if err != nil {
 		switch {
@@ -80,12 +287,17 @@ func readFile(path string) (*api.Config, error) {
 		return api.NewConfig(), nil
 	}
 
-	config := new(api.Config)
-	decoder := yaml.NewDecoder(bytes.NewBuffer(configData))
-	if err := decoder.Decode(&config); err != nil {
+	config, fromVersion, err := decodeConfig(configData)
+	if err != nil {
 		return nil, err
 	}
 
+	if upgrade && fromVersion != api.ConfigAPIVersion {
+		if err := migrateFile(path, configData, config); err != nil {
+			logger.NewLogger(os.Stderr).Info(fmt.Sprintf("cannot upgrade the config file %s, run miactl context migrate to upgrade it: %s", path, err))
+		}
+	}
+
 	return config, nil
 }
 
@@ -99,13 +311,10 @@ func writeFile(path string, config *api.Config) error {
 		}
 	}
 
-	configBuffer := bytes.NewBuffer([]byte{})
-	encoder := yaml.NewEncoder(configBuffer)
-	encoder.SetIndent(2)
-
-	if err := encoder.Encode(config); err != nil {
+	configData, err := encodeConfig(config)
+	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, configBuffer.Bytes(), 0600)
+	return os.WriteFile(path, configData, 0600)
 }
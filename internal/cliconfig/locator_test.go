@@ -0,0 +1,157 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+const (
+	sharedConfig = `contexts:
+  shared:
+    endpoint: https://shared.example.com
+  overridden:
+    endpoint: https://shared.example.com
+credentials:
+  shared-credential:
+    client-id: shared
+current-context: shared
+cache-encryption:
+  key-env: SHARED_CACHE_KEY
+`
+	personalConfig = `contexts:
+  overridden:
+    endpoint: https://personal.example.com
+credentials:
+  personal-credential:
+    client-id: personal
+    client-secret: secret
+`
+)
+
+func writeTestConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLocatorPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "first")
+	second := filepath.Join(tempDir, "second")
+
+	t.Run("default path", func(t *testing.T) {
+		t.Setenv(ConfigPathEnvVarName, "")
+		locator := NewConfigPathLocator()
+		assert.Equal(t, []string{ConfigFilePath()}, locator.ConfigLocations())
+	})
+
+	t.Run("multiple paths from env", func(t *testing.T) {
+		t.Setenv(ConfigPathEnvVarName, first+string(filepath.ListSeparator)+second+string(filepath.ListSeparator)+first)
+		locator := NewConfigPathLocator()
+		assert.Equal(t, []string{first, second}, locator.ConfigLocations())
+		assert.Equal(t, first, locator.ConfigLocation())
+	})
+
+	t.Run("explicit path wins", func(t *testing.T) {
+		t.Setenv(ConfigPathEnvVarName, first+string(filepath.ListSeparator)+second)
+		locator := NewConfigPathLocator()
+		locator.ExplicitPath = second
+		assert.Equal(t, []string{second}, locator.ConfigLocations())
+	})
+}
+
+func TestReadMergedConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	personal := writeTestConfig(t, tempDir, "personal", personalConfig)
+	shared := writeTestConfig(t, tempDir, "shared", sharedConfig)
+	t.Setenv(ConfigPathEnvVarName, personal+string(filepath.ListSeparator)+shared)
+
+	config, err := NewConfigPathLocator().ReadConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, "shared", config.CurrentContext)
+	assert.Len(t, config.Contexts, 2)
+	assert.Equal(t, "https://personal.example.com", config.Contexts["overridden"].Endpoint)
+	assert.Equal(t, "https://shared.example.com", config.Contexts["shared"].Endpoint)
+	assert.Len(t, config.Auth, 2)
+	assert.Contains(t, config.Auth, "shared-credential")
+	assert.Contains(t, config.Auth, "personal-credential")
+	require.NotNil(t, config.CacheEncryption)
+	assert.Equal(t, "SHARED_CACHE_KEY", config.CacheEncryption.KeyEnv)
+}
+
+func TestWriteMergedConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	personal := writeTestConfig(t, tempDir, "personal", personalConfig)
+	shared := writeTestConfig(t, tempDir, "shared", sharedConfig)
+	t.Setenv(ConfigPathEnvVarName, personal+string(filepath.ListSeparator)+shared)
+
+	locator := NewConfigPathLocator()
+	config, err := locator.ReadConfig()
+	require.NoError(t, err)
+
+	config.Contexts["shared"].CompanyID = "company"
+	config.Contexts["new"] = &api.ContextConfig{Endpoint: "https://new.example.com"}
+	delete(config.Auth, "personal-credential")
+	config.CurrentContext = "new"
+	config.Preferences = &api.Preferences{OutputFormat: "yaml"}
+	config.CacheEncryption.KeyEnv = "NEW_CACHE_KEY"
+	require.NoError(t, locator.WriteConfig(config))
+
+	personalFile, err := readFile(personal, false)
+	require.NoError(t, err)
+	sharedFile, err := readFile(shared, false)
+	require.NoError(t, err)
+
+	assert.Contains(t, personalFile.Contexts, "new")
+	assert.NotContains(t, sharedFile.Contexts, "new")
+	assert.Equal(t, "company", sharedFile.Contexts["shared"].CompanyID)
+	assert.NotContains(t, personalFile.Contexts, "shared")
+	assert.Empty(t, personalFile.Auth)
+	assert.Contains(t, sharedFile.Auth, "shared-credential")
+	assert.Equal(t, "new", sharedFile.CurrentContext)
+	assert.Empty(t, personalFile.CurrentContext)
+	require.NotNil(t, personalFile.Preferences)
+	assert.Equal(t, "yaml", personalFile.Preferences.OutputFormat)
+	assert.Nil(t, sharedFile.Preferences)
+	require.NotNil(t, sharedFile.CacheEncryption)
+	assert.Equal(t, "NEW_CACHE_KEY", sharedFile.CacheEncryption.KeyEnv)
+	assert.Nil(t, personalFile.CacheEncryption)
+
+	t.Run("unmodified files are not written", func(t *testing.T) {
+		sharedData, err := os.ReadFile(shared)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(shared, append(sharedData, []byte("# not rewritten\n")...), 0600))
+
+		config, err := locator.ReadConfig()
+		require.NoError(t, err)
+		config.Contexts["overridden"].ProjectID = "project"
+		require.NoError(t, locator.WriteConfig(config))
+
+		newSharedData, err := os.ReadFile(shared)
+		require.NoError(t, err)
+		assert.Contains(t, string(newSharedData), "# not rewritten")
+	})
+}
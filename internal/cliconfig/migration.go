@@ -0,0 +1,211 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+// legacyConfigVersion is the version assigned to config files written before the introduction of apiVersion
+const legacyConfigVersion = "v0"
+
+// backupFileSuffix is appended to the path of a config file for saving its content before a migration
+const backupFileSuffix = ".bak"
+
+// ErrNewerConfigVersion is returned when a config file has been written by a newer version of miactl
+var ErrNewerConfigVersion = errors.New("config file has been written by a newer version of miactl")
+
+// configMigration upgrade the raw content of a config file from a version to the next one
+type configMigration struct {
+	from    string
+	to      string
+	migrate func(raw map[string]interface{}) error
+}
+
+// configMigrations contains all the migrations in order, every new format of the config file must
+// append a new entry here that upgrade the previous one
+var configMigrations = []configMigration{
+	{from: legacyConfigVersion, to: "v1", migrate: migrateLegacyConfig},
+}
+
+// ConfigMigration describe the upgrade of a config file to the current version
+type ConfigMigration struct {
+	Path        string
+	FromVersion string
+	ToVersion   string
+	BackupPath  string
+	Data        []byte
+}
+
+// MigrateConfig upgrade all the selected files to the current config version, saving the previous content in
+// a backup file; if dryRun is true the files are left untouched. Only the files that need an upgrade are returned
+func (cr *ConfigPathLocator) MigrateConfig(dryRun bool) ([]*ConfigMigration, error) {
+	migrations := make([]*ConfigMigration, 0)
+	for _, path := range cr.ConfigLocations() {
+		configData, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if len(configData) == 0 {
+			continue
+		}
+
+		config, fromVersion, err := decodeConfig(configData)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file %s: %w", path, err)
+		}
+
+		if fromVersion == api.ConfigAPIVersion {
+			continue
+		}
+
+		migratedData, err := encodeConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		migration := &ConfigMigration{
+			Path:        path,
+			FromVersion: fromVersion,
+			ToVersion:   api.ConfigAPIVersion,
+			BackupPath:  path + backupFileSuffix,
+			Data:        migratedData,
+		}
+
+		if !dryRun {
+			if err := migrateFile(path, configData, config); err != nil {
+				return nil, err
+			}
+		}
+
+		migrations = append(migrations, migration)
+	}
+
+	return migrations, nil
+}
+
+// decodeConfig decode configData upgrading it to the current version if needed, the version found in
+// configData is returned alongside the config
+func decodeConfig(configData []byte) (*api.Config, string, error) {
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(configData, &raw); err != nil {
+		return nil, "", err
+	}
+
+	if kind, found := raw["kind"]; found && kind != api.ConfigKind {
+		return nil, "", fmt.Errorf("unsupported config kind %q, expected %q", kind, api.ConfigKind)
+	}
+
+	fromVersion := legacyConfigVersion
+	if apiVersion, found := raw["apiVersion"]; found {
+		version, ok := apiVersion.(string)
+		if !ok || len(version) == 0 {
+			return nil, "", fmt.Errorf("invalid config apiVersion %v", apiVersion)
+		}
+		fromVersion = version
+	}
+
+	version := fromVersion
+	for _, migration := range configMigrations {
+		if migration.from != version {
+			continue
+		}
+		if err := migration.migrate(raw); err != nil {
+			return nil, "", fmt.Errorf("migrating config from %s to %s: %w", migration.from, migration.to, err)
+		}
+		raw["apiVersion"] = migration.to
+		version = migration.to
+	}
+
+	if version != api.ConfigAPIVersion {
+		if isNewerConfigVersion(version) {
+			return nil, "", fmt.Errorf("%w: apiVersion %s is not supported, update miactl to a version supporting it", ErrNewerConfigVersion, version)
+		}
+		return nil, "", fmt.Errorf("unsupported config apiVersion %q", version)
+	}
+
+	if fromVersion != version {
+		var err error
+		if configData, err = yaml.Marshal(raw); err != nil {
+			return nil, "", err
+		}
+	}
+
+	config := new(api.Config)
+	decoder := yaml.NewDecoder(bytes.NewBuffer(configData))
+	if err := decoder.Decode(&config); err != nil {
+		return nil, "", err
+	}
+
+	return config, fromVersion, nil
+}
+
+// encodeConfig return the yaml representation of config, always stamped with the current version
+func encodeConfig(config *api.Config) ([]byte, error) {
+	versionedConfig := *config
+	versionedConfig.APIVersion = api.ConfigAPIVersion
+	versionedConfig.Kind = api.ConfigKind
+
+	configBuffer := bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(configBuffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&versionedConfig); err != nil {
+		return nil, err
+	}
+
+	return configBuffer.Bytes(), nil
+}
+
+// migrateFile save the original configData in the backup file, and write the migrated config in path
+func migrateFile(path string, configData []byte, config *api.Config) error {
+	if err := os.WriteFile(path+backupFileSuffix, configData, 0600); err != nil {
+		return fmt.Errorf("saving backup of config file %s: %w", path, err)
+	}
+
+	return writeFile(path, config)
+}
+
+// isNewerConfigVersion return true if version has the vN format and N is greater than the current version
+func isNewerConfigVersion(version string) bool {
+	number, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if err != nil || !strings.HasPrefix(version, "v") {
+		return false
+	}
+
+	current, _ := strconv.Atoi(strings.TrimPrefix(api.ConfigAPIVersion, "v"))
+	return number > current
+}
+
+// migrateLegacyConfig upgrade a config file without version; the format of contexts and credentials is
+// unchanged, the file only gains apiVersion and kind
+func migrateLegacyConfig(raw map[string]interface{}) error {
+	raw["kind"] = api.ConfigKind
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+const legacyConfigData = `contexts:
+  context:
+    endpoint: https://example.com
+current-context: context
+credentials:
+  credential:
+    client-id: id
+`
+
+func TestDecodeConfig(t *testing.T) {
+	expectedConfig := &api.Config{
+		APIVersion:     api.ConfigAPIVersion,
+		Kind:           api.ConfigKind,
+		CurrentContext: "context",
+		Contexts: map[string]*api.ContextConfig{
+			"context": {Endpoint: "https://example.com"},
+		},
+		Auth: map[string]*api.AuthConfig{
+			"credential": {ClientID: "id"},
+		},
+	}
+
+	testCases := map[string]struct {
+		configData          string
+		expectedConfig      *api.Config
+		expectedFromVersion string
+		expectedErr         error
+		expectErr           bool
+	}{
+		"legacy config": {
+			configData:          legacyConfigData,
+			expectedConfig:      expectedConfig,
+			expectedFromVersion: "v0",
+		},
+		"current config": {
+			configData:          "apiVersion: v1\nkind: Config\n" + legacyConfigData,
+			expectedConfig:      expectedConfig,
+			expectedFromVersion: "v1",
+		},
+		"newer config": {
+			configData:  "apiVersion: v2\nkind: Config\n" + legacyConfigData,
+			expectedErr: ErrNewerConfigVersion,
+			expectErr:   true,
+		},
+		"unknown version": {
+			configData: "apiVersion: foo\nkind: Config\n" + legacyConfigData,
+			expectErr:  true,
+		},
+		"wrong kind": {
+			configData: "apiVersion: v1\nkind: Other\n" + legacyConfigData,
+			expectErr:  true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			config, fromVersion, err := decodeConfig([]byte(testCase.configData))
+			if testCase.expectErr {
+				assert.Error(t, err)
+				if testCase.expectedErr != nil {
+					assert.ErrorIs(t, err, testCase.expectedErr)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedConfig, config)
+			assert.Equal(t, testCase.expectedFromVersion, fromVersion)
+		})
+	}
+}
+
+func TestReadFileMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfigData), 0600))
+
+	// a read without upgrade must leave the file untouched
+	config, err := readFile(path, false)
+	require.NoError(t, err)
+	assert.Equal(t, api.ConfigAPIVersion, config.APIVersion)
+	assert.NoFileExists(t, path+backupFileSuffix)
+
+	config, err = readFile(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, api.ConfigAPIVersion, config.APIVersion)
+
+	backupData, err := os.ReadFile(path + backupFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfigData, string(backupData))
+
+	configData, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1\nkind: Config\n"+legacyConfigData, string(configData))
+
+	// a second read must not touch the backup again
+	require.NoError(t, os.Remove(path+backupFileSuffix))
+	_, err = readFile(path, true)
+	require.NoError(t, err)
+	assert.NoFileExists(t, path+backupFileSuffix)
+}
+
+func TestReadFileMigrationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfigData), 0600))
+	// a directory in place of the backup file makes the upgrade on disk fail
+	require.NoError(t, os.Mkdir(path+backupFileSuffix, 0700))
+
+	config, err := readFile(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, api.ConfigAPIVersion, config.APIVersion)
+
+	configData, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfigData, string(configData))
+}
@@ -24,4 +24,29 @@ type ConfigOverrides struct {
 	Context               string
 	AuthName              string
 	Environment           string
+	LoginMode             string
+	ClientCertificate     string
+	ClientCertificateData string
+	ClientKey             string
+	ClientKeyData         string
+	ProxyURL              string
+	NoProxy               string
+
+	ClientID          string
+	ClientSecret      string
+	JWTKeyID          string
+	JWTPrivateKeyData string
+	JWTPrivateKeyFile string
+
+	// JWTPrivateKeyPassphrase is the passphrase of an encrypted private key file, it is used with any credential
+	JWTPrivateKeyPassphrase string
+
+	// Token is a bearer token that will be used as is for authenticating the requests
+	Token string
+}
+
+// hasAuth return true if at least one of the credential fields is set
+func (o *ConfigOverrides) hasAuth() bool {
+	return len(o.ClientID) > 0 || len(o.ClientSecret) > 0 || len(o.JWTKeyID) > 0 ||
+		len(o.JWTPrivateKeyData) > 0 || len(o.JWTPrivateKeyFile) > 0
 }
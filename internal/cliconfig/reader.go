@@ -16,7 +16,9 @@
 package cliconfig
 
 import (
+	"encoding/base64"
 	"fmt"
+	"time"
 
 	"dario.cat/mergo"
 
@@ -24,92 +26,387 @@ import (
 	"github.com/mia-platform/miactl/internal/client"
 )
 
+// ValueSource describe where a configuration value has been read from
+type ValueSource string
+
+const (
+	SourceFlag       ValueSource = "flag"
+	SourceEnv        ValueSource = "env"
+	SourceContext    ValueSource = "context"
+	SourceCredential ValueSource = "credential"
+	SourceConfigFile ValueSource = "config file"
+)
+
 type ConfigReader struct {
-	config    *api.Config
-	overrides *ConfigOverrides
+	config       *api.Config
+	overrides    *ConfigOverrides
+	envOverrides *ConfigOverrides
+
+	sources map[string]ValueSource
 }
 
 func NewConfigReader(config *api.Config, overrides *ConfigOverrides) *ConfigReader {
 	return &ConfigReader{
 		config:    config,
 		overrides: overrides,
+		sources:   make(map[string]ValueSource),
 	}
 }
 
+// WithEnvOverrides set the overrides read from the environment, their values will be used only
+// if the same value is not overridden by overrides
+func (cr *ConfigReader) WithEnvOverrides(envOverrides *ConfigOverrides) *ConfigReader {
+	cr.envOverrides = envOverrides
+	return cr
+}
+
+// Sources return where each configuration value used in the last ClientConfig call has been read from,
+// keyed with the same names used in the config file
+func (cr *ConfigReader) Sources() map[string]ValueSource {
+	sources := make(map[string]ValueSource, len(cr.sources))
+	for key, source := range cr.sources {
+		sources[key] = source
+	}
+	return sources
+}
+
 func (cr *ConfigReader) ClientConfig(locator *ConfigPathLocator) (*client.Config, error) {
+	cr.sources = make(map[string]ValueSource)
 	context, err := cr.getContext()
 	if err != nil {
 		return nil, err
 	}
 
-	authConfig, found := cr.getAuthConfig()
+	if err := client.ValidateLoginMode(context.LoginMode); err != nil {
+		return nil, err
+	}
+
+	authConfig, found := cr.getAuthConfig(context.AuthName)
+	if !found {
+		authConfig = new(api.AuthConfig)
+	}
+
+	authCache := cr.authCache(locator, context, authConfig)
 
 	clientConfig := &client.Config{
 		Host: context.Endpoint,
 		TLSClientConfig: client.TLSClientConfig{
 			CAFile:   context.CertificateAuthority,
 			Insecure: context.InsecureSkipTLSVerify,
+			CertFile: context.ClientCertificate,
+			KeyFile:  context.ClientKey,
 		},
-		AuthCacheReadWriter: NewAuthReadWriter(locator, context, authConfig),
+		ProxyURL:            context.ProxyURL,
+		NoProxy:             context.NoProxy,
+		AuthCacheReadWriter: authCache,
 		CompanyID:           context.CompanyID,
 		ProjectID:           context.ProjectID,
 		Environment:         context.Environment,
+		BearerToken:         cr.getToken(),
+	}
+	clientConfig.ContextName, _ = cr.getCurrentContextName()
+	if clientConfig.CertData, err = decodePEMData("client-certificate-data", context.ClientCertificateData); err != nil {
+		return nil, err
+	}
+	if clientConfig.KeyData, err = decodePEMData("client-key-data", context.ClientKeyData); err != nil {
+		return nil, err
 	}
 
 	if found {
+		if !cr.useEnvAuth() {
+			clientConfig.AuthName = context.AuthName
+		}
 		clientConfig.AuthConfig = client.AuthConfig{
 			ClientID:          authConfig.ClientID,
 			ClientSecret:      authConfig.ClientSecret,
 			JWTKeyID:          authConfig.JWTKeyID,
 			JWTPrivateKeyData: authConfig.JWTPrivateKeyData,
+			JWTPrivateKeyFile: authConfig.JWTPrivateKeyFile,
+			Exec:              execConfig(authConfig.Exec),
 		}
 	}
+	if cr.envOverrides != nil {
+		clientConfig.JWTPrivateKeyPassphrase = cr.envOverrides.JWTPrivateKeyPassphrase
+	}
+	clientConfig.LoginMode = context.LoginMode
+
+	preferences, err := cr.Preferences()
+	if err != nil {
+		return nil, err
+	}
+	if clientConfig.TokenRefreshWindow, err = ParseTokenRefreshWindow(preferences.TokenRefreshWindow); err != nil {
+		return nil, err
+	}
+	if clientConfig.RetryMaxAttempts, err = ValidateRetryMaxAttempts(preferences.RetryMaxAttempts); err != nil {
+		return nil, err
+	}
+	if clientConfig.RetryMaxDelay, err = ParseRetryMaxDelay(preferences.RetryMaxDelay); err != nil {
+		return nil, err
+	}
+	clientConfig.AuditFile = preferences.AuditFile
+
 	return clientConfig, nil
 }
 
+// ParseTokenRefreshWindow return the duration saved in the token-refresh-window preference, an empty value
+// return zero
+func ParseTokenRefreshWindow(value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	window, err := time.ParseDuration(value)
+	if err != nil || window < 0 {
+		return 0, fmt.Errorf("invalid token-refresh-window %q: must be a positive duration like 5m", value)
+	}
+	return window, nil
+}
+
+// decodePEMData return the pem data saved base64 encoded in the field key
+func decodePEMData(key, value string) ([]byte, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be base64 encoded pem data: %w", key, err)
+	}
+	return data, nil
+}
+
+// ValidateRetryMaxAttempts return the value of the retry-max-attempts preference if it is not negative
+func ValidateRetryMaxAttempts(value int) (int, error) {
+	if value < 0 {
+		return 0, fmt.Errorf("invalid retry-max-attempts %d: must be a positive number", value)
+	}
+	return value, nil
+}
+
+// ParseRetryMaxDelay return the duration saved in the retry-max-delay preference, an empty value return zero
+func ParseRetryMaxDelay(value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < 0 {
+		return 0, fmt.Errorf("invalid retry-max-delay %q: must be a positive duration like 30s", value)
+	}
+	return delay, nil
+}
+
+// Preferences return the preferences for the selected context, the values set in the context
+// preferences win over the global ones
+func (cr *ConfigReader) Preferences() (*api.Preferences, error) {
+	preferences := cr.config.Preferences.DeepCopy()
+	if preferences == nil {
+		preferences = new(api.Preferences)
+	}
+
+	contextName, _ := cr.getCurrentContextName()
+	if context, found := cr.config.Contexts[contextName]; found && context != nil && context.Preferences != nil {
+		if err := mergo.Merge(preferences, context.Preferences.DeepCopy(), mergo.WithOverride); err != nil {
+			return nil, err
+		}
+	}
+
+	return preferences, nil
+}
+
+// authCache return the encrypted auth cache if the cache-encryption setting is set, or the plaintext one
+func (cr *ConfigReader) authCache(locator *ConfigPathLocator, context *api.ContextConfig, authConfig *api.AuthConfig) client.AuthCacheReadWriter {
+	if cr.config.CacheEncryption == nil {
+		return NewAuthReadWriter(locator, context, authConfig)
+	}
+
+	return NewEncryptedAuthReadWriter(locator, context, authConfig, cr.config.CacheEncryption)
+}
+
+func execConfig(config *api.ExecConfig) *client.ExecConfig {
+	if config == nil {
+		return nil
+	}
+
+	env := make([]string, 0, len(config.Env))
+	for _, envVar := range config.Env {
+		env = append(env, envVar.Name+"="+envVar.Value)
+	}
+
+	return &client.ExecConfig{
+		Command: config.Command,
+		Args:    append([]string{}, config.Args...),
+		Env:     env,
+	}
+}
+
 func (cr *ConfigReader) getContext() (*api.ContextConfig, error) {
 	currentContext, required := cr.getCurrentContextName()
-	mergedContext := new(api.ContextConfig)
-	if context, found := cr.config.Contexts[currentContext]; found {
-		_ = mergo.Merge(mergedContext, context, mergo.WithOverride)
-	} else if required {
+	context, found := cr.config.Contexts[currentContext]
+	switch {
+	case !found && required:
 		return nil, fmt.Errorf("context %s not found", currentContext)
+	case !found || context == nil:
+		context = new(api.ContextConfig)
 	}
 
-	if cr.overrides != nil {
-		overrides := &api.ContextConfig{
-			Endpoint:              cr.overrides.Endpoint,
-			CertificateAuthority:  cr.overrides.CertificateAuthority,
-			InsecureSkipTLSVerify: cr.overrides.InsecureSkipTLSVerify,
-			CompanyID:             cr.overrides.CompanyID,
-			ProjectID:             cr.overrides.ProjectID,
-			Environment:           cr.overrides.Environment,
-		}
-		_ = mergo.Merge(mergedContext, overrides, mergo.WithOverride)
+	flags := cr.overrides
+	if flags == nil {
+		flags = new(ConfigOverrides)
+	}
+	env := cr.envOverrides
+	if env == nil {
+		env = new(ConfigOverrides)
 	}
 
-	return mergedContext, nil
+	resolved := &api.ContextConfig{
+		Endpoint:              resolveValue(cr.sources, "endpoint", flags.Endpoint, env.Endpoint, context.Endpoint),
+		CertificateAuthority:  resolveValue(cr.sources, "certificate-authority", flags.CertificateAuthority, env.CertificateAuthority, context.CertificateAuthority),
+		InsecureSkipTLSVerify: resolveValue(cr.sources, "insecure-skip-tls-verify", flags.InsecureSkipTLSVerify, env.InsecureSkipTLSVerify, context.InsecureSkipTLSVerify),
+		CompanyID:             resolveValue(cr.sources, "company-id", flags.CompanyID, env.CompanyID, context.CompanyID),
+		ProjectID:             resolveValue(cr.sources, "project-id", flags.ProjectID, env.ProjectID, context.ProjectID),
+		AuthName:              resolveValue(cr.sources, "credential", flags.AuthName, env.AuthName, context.AuthName),
+		Environment:           resolveValue(cr.sources, "environment", flags.Environment, env.Environment, context.Environment),
+		LoginMode:             resolveValue(cr.sources, "login-mode", flags.LoginMode, env.LoginMode, context.LoginMode),
+		ProxyURL:              resolveValue(cr.sources, "proxy-url", flags.ProxyURL, env.ProxyURL, context.ProxyURL),
+		NoProxy:               resolveValue(cr.sources, "no-proxy", flags.NoProxy, env.NoProxy, context.NoProxy),
+	}
+
+	resolved.ClientCertificate, resolved.ClientCertificateData = resolveFileOrData(cr.sources, "client-certificate",
+		[2]string{flags.ClientCertificate, flags.ClientCertificateData},
+		[2]string{env.ClientCertificate, env.ClientCertificateData},
+		[2]string{context.ClientCertificate, context.ClientCertificateData},
+	)
+	resolved.ClientKey, resolved.ClientKeyData = resolveFileOrData(cr.sources, "client-key",
+		[2]string{flags.ClientKey, flags.ClientKeyData},
+		[2]string{env.ClientKey, env.ClientKeyData},
+		[2]string{context.ClientKey, context.ClientKeyData},
+	)
+
+	return resolved, nil
 }
 
 func (cr *ConfigReader) getCurrentContextName() (string, bool) {
 	if cr.overrides != nil && len(cr.overrides.Context) > 0 {
+		cr.sources["context"] = SourceFlag
 		return cr.overrides.Context, true
 	}
 
+	if cr.envOverrides != nil && len(cr.envOverrides.Context) > 0 {
+		cr.sources["context"] = SourceEnv
+		return cr.envOverrides.Context, true
+	}
+
+	if len(cr.config.CurrentContext) > 0 {
+		cr.sources["context"] = SourceConfigFile
+	}
 	return cr.config.CurrentContext, false
 }
 
-func (cr *ConfigReader) getAuthConfig() (*api.AuthConfig, bool) {
-	authConfigName := cr.getAuthConfigName()
+// useEnvAuth return true if the credential values of the environment replace the credential of the context;
+// a credential selected with the flags always wins over them
+func (cr *ConfigReader) useEnvAuth() bool {
+	if cr.overrides != nil && len(cr.overrides.AuthName) > 0 {
+		return false
+	}
+	return cr.envOverrides != nil && cr.envOverrides.hasAuth()
+}
+
+// getAuthConfig return the credential named authConfigName, if the environment contains
+// credential values they will replace the whole credential
+func (cr *ConfigReader) getAuthConfig(authConfigName string) (*api.AuthConfig, bool) {
+	if cr.useEnvAuth() {
+		env := cr.envOverrides
+		return &api.AuthConfig{
+			ClientID:          resolveValue(cr.sources, "client-id", "", env.ClientID, ""),
+			ClientSecret:      resolveValue(cr.sources, "client-secret", "", env.ClientSecret, ""),
+			JWTKeyID:          resolveValue(cr.sources, "key-id", "", env.JWTKeyID, ""),
+			JWTPrivateKeyData: resolveValue(cr.sources, "private-key-data", "", env.JWTPrivateKeyData, ""),
+			JWTPrivateKeyFile: resolveValue(cr.sources, "private-key-file", "", env.JWTPrivateKeyFile, ""),
+		}, true
+	}
+
 	if len(authConfigName) == 0 {
 		return new(api.AuthConfig), true
 	}
 
 	authConfig, found := cr.config.Auth[authConfigName]
-	return authConfig, found
+	if !found || authConfig == nil {
+		return nil, false
+	}
+
+	for key, value := range map[string]string{
+		"client-id":        authConfig.ClientID,
+		"client-secret":    authConfig.ClientSecret,
+		"key-id":           authConfig.JWTKeyID,
+		"private-key-data": authConfig.JWTPrivateKeyData,
+		"private-key-file": authConfig.JWTPrivateKeyFile,
+	} {
+		if len(value) > 0 {
+			cr.sources[key] = SourceCredential
+		}
+	}
+	if authConfig.Exec != nil {
+		cr.sources["exec"] = SourceCredential
+	}
+	return authConfig, true
 }
 
-func (cr *ConfigReader) getAuthConfigName() string {
-	context, _ := cr.getContext()
-	return context.AuthName
+func (cr *ConfigReader) getToken() string {
+	if cr.envOverrides == nil || len(cr.envOverrides.Token) == 0 {
+		return ""
+	}
+
+	cr.sources["token"] = SourceEnv
+	return cr.envOverrides.Token
+}
+
+// resolveFileOrData return the file path and the data set in the first of flag, env and context that contains
+// at least one of them, so that a data set in the environment is not hidden by a file set in the context;
+// the sources are saved with the key name and the key name with the -data suffix
+func resolveFileOrData(sources map[string]ValueSource, key string, flag, env, context [2]string) (string, string) {
+	layers := []struct {
+		source ValueSource
+		values [2]string
+	}{
+		{source: SourceFlag, values: flag},
+		{source: SourceEnv, values: env},
+		{source: SourceContext, values: context},
+	}
+
+	for _, layer := range layers {
+		file, data := layer.values[0], layer.values[1]
+		if len(file) == 0 && len(data) == 0 {
+			continue
+		}
+
+		if len(file) > 0 {
+			sources[key] = layer.source
+		}
+		if len(data) > 0 {
+			sources[key+"-data"] = layer.source
+		}
+		return file, data
+	}
+
+	return "", ""
+}
+
+// resolveValue return the first non zero value in order of precedence between flag, env and context and
+// save its source in sources
+func resolveValue[T comparable](sources map[string]ValueSource, key string, flag, env, context T) T {
+	var zero T
+	switch {
+	case flag != zero:
+		sources[key] = SourceFlag
+		return flag
+	case env != zero:
+		sources[key] = SourceEnv
+		return env
+	case context != zero:
+		sources[key] = SourceContext
+		return context
+	default:
+		return zero
+	}
 }
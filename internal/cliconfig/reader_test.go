@@ -0,0 +1,329 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cliconfig
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+func testReaderConfig() *api.Config {
+	return &api.Config{
+		CurrentContext: "context",
+		Contexts: map[string]*api.ContextConfig{
+			"context": {
+				Endpoint:  "https://context.example.com",
+				CompanyID: "context-company",
+				ProjectID: "context-project",
+				AuthName:  "credential",
+			},
+			"other": {
+				Endpoint: "https://other.example.com",
+			},
+		},
+		Auth: map[string]*api.AuthConfig{
+			"credential": {
+				ClientID:     "context-id",
+				ClientSecret: "context-secret",
+			},
+		},
+	}
+}
+
+func TestClientConfigPrecedence(t *testing.T) {
+	testCases := map[string]struct {
+		overrides        *ConfigOverrides
+		envOverrides     *ConfigOverrides
+		expectedHost     string
+		expectedCompany  string
+		expectedProject  string
+		expectedAuth     client.AuthConfig
+		expectedToken    string
+		expectedContext  string
+		expectedAuthName string
+		expectedSources  map[string]ValueSource
+		expectErr        bool
+	}{
+		"only context": {
+			expectedContext:  "context",
+			expectedAuthName: "credential",
+			expectedHost:     "https://context.example.com",
+			expectedCompany:  "context-company",
+			expectedProject:  "context-project",
+			expectedAuth:     client.AuthConfig{ClientID: "context-id", ClientSecret: "context-secret"},
+			expectedSources: map[string]ValueSource{
+				"context":       SourceConfigFile,
+				"endpoint":      SourceContext,
+				"company-id":    SourceContext,
+				"project-id":    SourceContext,
+				"credential":    SourceContext,
+				"client-id":     SourceCredential,
+				"client-secret": SourceCredential,
+			},
+		},
+		"env wins over context and flags win over env": {
+			expectedContext:  "context",
+			expectedAuthName: "credential",
+			overrides:        &ConfigOverrides{ProjectID: "flag-project"},
+			envOverrides:     &ConfigOverrides{CompanyID: "env-company", ProjectID: "env-project", Token: "token"},
+			expectedHost:     "https://context.example.com",
+			expectedCompany:  "env-company",
+			expectedProject:  "flag-project",
+			expectedAuth:     client.AuthConfig{ClientID: "context-id", ClientSecret: "context-secret"},
+			expectedToken:    "token",
+			expectedSources: map[string]ValueSource{
+				"context":       SourceConfigFile,
+				"endpoint":      SourceContext,
+				"company-id":    SourceEnv,
+				"project-id":    SourceFlag,
+				"credential":    SourceContext,
+				"client-id":     SourceCredential,
+				"client-secret": SourceCredential,
+				"token":         SourceEnv,
+			},
+		},
+		"env credential replace the context one": {
+			expectedContext: "context",
+			envOverrides:    &ConfigOverrides{JWTKeyID: "env-key", JWTPrivateKeyData: "env-data", ClientID: "env-id"},
+			expectedHost:    "https://context.example.com",
+			expectedCompany: "context-company",
+			expectedProject: "context-project",
+			expectedAuth:    client.AuthConfig{ClientID: "env-id", JWTKeyID: "env-key", JWTPrivateKeyData: "env-data"},
+			expectedSources: map[string]ValueSource{
+				"context":          SourceConfigFile,
+				"endpoint":         SourceContext,
+				"company-id":       SourceContext,
+				"project-id":       SourceContext,
+				"credential":       SourceContext,
+				"client-id":        SourceEnv,
+				"key-id":           SourceEnv,
+				"private-key-data": SourceEnv,
+			},
+		},
+		"credential from flag wins over the env credential": {
+			expectedContext:  "other",
+			expectedAuthName: "credential",
+			overrides:        &ConfigOverrides{Context: "other", AuthName: "credential"},
+			envOverrides:     &ConfigOverrides{JWTKeyID: "env-key", JWTPrivateKeyData: "env-data", ClientID: "env-id"},
+			expectedHost:     "https://other.example.com",
+			expectedAuth:     client.AuthConfig{ClientID: "context-id", ClientSecret: "context-secret"},
+			expectedSources: map[string]ValueSource{
+				"context":       SourceFlag,
+				"endpoint":      SourceContext,
+				"credential":    SourceFlag,
+				"client-id":     SourceCredential,
+				"client-secret": SourceCredential,
+			},
+		},
+		"env private key file and passphrase": {
+			expectedContext: "context",
+			envOverrides: &ConfigOverrides{
+				ClientID:                "env-id",
+				JWTKeyID:                "env-key",
+				JWTPrivateKeyFile:       "/keys/key.pem",
+				JWTPrivateKeyPassphrase: "passphrase",
+			},
+			expectedHost:    "https://context.example.com",
+			expectedCompany: "context-company",
+			expectedProject: "context-project",
+			expectedAuth: client.AuthConfig{
+				ClientID:                "env-id",
+				JWTKeyID:                "env-key",
+				JWTPrivateKeyFile:       "/keys/key.pem",
+				JWTPrivateKeyPassphrase: "passphrase",
+			},
+			expectedSources: map[string]ValueSource{
+				"context":          SourceConfigFile,
+				"endpoint":         SourceContext,
+				"company-id":       SourceContext,
+				"project-id":       SourceContext,
+				"credential":       SourceContext,
+				"client-id":        SourceEnv,
+				"key-id":           SourceEnv,
+				"private-key-file": SourceEnv,
+			},
+		},
+		"context from env": {
+			expectedContext: "other",
+			envOverrides:    &ConfigOverrides{Context: "other"},
+			expectedHost:    "https://other.example.com",
+			expectedSources: map[string]ValueSource{
+				"context":  SourceEnv,
+				"endpoint": SourceContext,
+			},
+		},
+		"login mode from env": {
+			expectedContext:  "context",
+			expectedAuthName: "credential",
+			envOverrides:     &ConfigOverrides{LoginMode: client.LoginModeDevice},
+			expectedHost:     "https://context.example.com",
+			expectedCompany:  "context-company",
+			expectedProject:  "context-project",
+			expectedAuth:     client.AuthConfig{ClientID: "context-id", ClientSecret: "context-secret", LoginMode: client.LoginModeDevice},
+			expectedSources: map[string]ValueSource{
+				"context":       SourceConfigFile,
+				"endpoint":      SourceContext,
+				"company-id":    SourceContext,
+				"project-id":    SourceContext,
+				"credential":    SourceContext,
+				"login-mode":    SourceEnv,
+				"client-id":     SourceCredential,
+				"client-secret": SourceCredential,
+			},
+		},
+		"invalid login mode": {
+			overrides: &ConfigOverrides{LoginMode: "carrier-pigeon"},
+			expectErr: true,
+		},
+		"missing context from flag": {
+			overrides:    &ConfigOverrides{Context: "missing"},
+			envOverrides: &ConfigOverrides{Context: "other"},
+			expectErr:    true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			reader := NewConfigReader(testReaderConfig(), testCase.overrides).WithEnvOverrides(testCase.envOverrides)
+			clientConfig, err := reader.ClientConfig(NewConfigPathLocator())
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedHost, clientConfig.Host)
+			assert.Equal(t, testCase.expectedCompany, clientConfig.CompanyID)
+			assert.Equal(t, testCase.expectedProject, clientConfig.ProjectID)
+			assert.Equal(t, testCase.expectedAuth, clientConfig.AuthConfig)
+			assert.Equal(t, testCase.expectedToken, clientConfig.BearerToken)
+			assert.Equal(t, testCase.expectedContext, clientConfig.ContextName)
+			assert.Equal(t, testCase.expectedAuthName, clientConfig.AuthName)
+			assert.Equal(t, testCase.expectedSources, reader.Sources())
+		})
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	t.Run("read all values", func(t *testing.T) {
+		t.Setenv(EndpointEnvVarName, "https://example.com")
+		t.Setenv(InsecureSkipTLSVerifyEnvVarName, "true")
+		t.Setenv(ClientIDEnvVarName, "id")
+		t.Setenv(TokenEnvVarName, "token")
+
+		overrides, err := EnvOverrides()
+		require.NoError(t, err)
+		assert.Equal(t, &ConfigOverrides{
+			Endpoint:              "https://example.com",
+			InsecureSkipTLSVerify: true,
+			ClientID:              "id",
+			Token:                 "token",
+		}, overrides)
+	})
+
+	t.Run("invalid boolean", func(t *testing.T) {
+		t.Setenv(InsecureSkipTLSVerifyEnvVarName, "maybe")
+
+		_, err := EnvOverrides()
+		assert.Error(t, err)
+	})
+}
+
+func TestClientConfigCacheEncryption(t *testing.T) {
+	t.Setenv("TEST_CACHE_KEY", "secret")
+
+	config := testReaderConfig()
+	clientConfig, err := NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	require.NoError(t, err)
+	assert.IsType(t, &AuthReadWriter{}, clientConfig.AuthCacheReadWriter)
+
+	config.CacheEncryption = &api.CacheEncryption{KeyEnv: "TEST_CACHE_KEY"}
+	clientConfig, err = NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	require.NoError(t, err)
+	assert.IsType(t, &EncryptedAuthReadWriter{}, clientConfig.AuthCacheReadWriter)
+
+	// the key is read only when the cache is used, so a missing key does not break the commands
+	config.CacheEncryption = &api.CacheEncryption{KeyEnv: "TEST_MISSING_CACHE_KEY"}
+	clientConfig, err = NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	require.NoError(t, err)
+	assert.IsType(t, &EncryptedAuthReadWriter{}, clientConfig.AuthCacheReadWriter)
+}
+
+func TestClientConfigRetryPreferences(t *testing.T) {
+	config := testReaderConfig()
+	config.Preferences = &api.Preferences{RetryMaxAttempts: 2, RetryMaxDelay: "10s"}
+	config.Contexts["context"].Preferences = &api.Preferences{RetryMaxAttempts: 6}
+
+	clientConfig, err := NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	require.NoError(t, err)
+	assert.Equal(t, 6, clientConfig.RetryMaxAttempts)
+	assert.Equal(t, 10*time.Second, clientConfig.RetryMaxDelay)
+
+	config.Preferences.RetryMaxDelay = "later"
+	_, err = NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	assert.ErrorContains(t, err, "invalid retry-max-delay")
+
+	config.Preferences.RetryMaxDelay = ""
+	config.Contexts["context"].Preferences.RetryMaxAttempts = -1
+	_, err = NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	assert.ErrorContains(t, err, "invalid retry-max-attempts")
+}
+
+func TestClientConfigConnectionSettings(t *testing.T) {
+	certificate := base64.StdEncoding.EncodeToString([]byte("certificate"))
+	key := base64.StdEncoding.EncodeToString([]byte("key"))
+
+	config := testReaderConfig()
+	config.Contexts["context"].ClientCertificate = "/context/client.crt"
+	config.Contexts["context"].ClientKey = "/context/client.key"
+	config.Contexts["context"].ProxyURL = "http://proxy.example.com:3128"
+	config.Contexts["context"].NoProxy = "internal.example.com"
+
+	reader := NewConfigReader(config, nil).WithEnvOverrides(&ConfigOverrides{
+		ClientCertificateData: certificate,
+		ClientKeyData:         key,
+	})
+	clientConfig, err := reader.ClientConfig(NewConfigPathLocator())
+	require.NoError(t, err)
+	assert.Empty(t, clientConfig.CertFile)
+	assert.Empty(t, clientConfig.KeyFile)
+	assert.Equal(t, []byte("certificate"), clientConfig.CertData)
+	assert.Equal(t, []byte("key"), clientConfig.KeyData)
+	assert.Equal(t, "http://proxy.example.com:3128", clientConfig.ProxyURL)
+	assert.Equal(t, "internal.example.com", clientConfig.NoProxy)
+	assert.Equal(t, SourceEnv, reader.Sources()["client-certificate-data"])
+	assert.Equal(t, SourceContext, reader.Sources()["proxy-url"])
+
+	reader = NewConfigReader(config, &ConfigOverrides{ClientCertificate: "/flag/client.crt", ProxyURL: "http://other.example.com"})
+	clientConfig, err = reader.ClientConfig(NewConfigPathLocator())
+	require.NoError(t, err)
+	assert.Equal(t, "/flag/client.crt", clientConfig.CertFile)
+	assert.Equal(t, "/context/client.key", clientConfig.KeyFile)
+	assert.Equal(t, "http://other.example.com", clientConfig.ProxyURL)
+	assert.Equal(t, SourceFlag, reader.Sources()["client-certificate"])
+
+	config.Contexts["context"].ClientKeyData = "not base64"
+	config.Contexts["context"].ClientKey = ""
+	_, err = NewConfigReader(config, nil).ClientConfig(NewConfigPathLocator())
+	assert.ErrorContains(t, err, "invalid client-key-data")
+}
@@ -16,11 +16,16 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/transport"
 )
 
 type AuthCacheReadWriter interface {
@@ -28,6 +33,17 @@ type AuthCacheReadWriter interface {
 	WriteJWTToken(*oauth2.Token)
 }
 
+// AuthCacheLocker is implemented by the AuthCacheReadWriter that can be shared between different processes,
+// the lock must be held while the cached token is read, refreshed and written back
+type AuthCacheLocker interface {
+	// LockJWTToken block until the lock is acquired, and return the function for releasing it
+	LockJWTToken() (func(), error)
+}
+
+// DefaultTokenRefreshWindow is the default time before the expiration of the cached access token when
+// a new token will be requested
+const DefaultTokenRefreshWindow = time.Minute
+
 type AuthProvider interface {
 	// Wrap allow the AuthProvider to add authorization functionality on
 	// a modified RoundTripper and to add the appropriate Authorization header to the request
@@ -51,3 +67,83 @@ func RegisterAuthProvider(ap AuthProviderCreator) error {
 	authProvider = ap
 	return nil
 }
+
+// tokenProbeKey mark the request used by AccessTokenForConfig for reading the authorization header
+type tokenProbeKey struct{}
+
+// tokenProbeRoundTripper intercept the request marked with tokenProbeKey saving its access token without
+// sending it, all the other requests made by the authentication flow are sent to next
+type tokenProbeRoundTripper struct {
+	next  http.RoundTripper
+	token string
+}
+
+func (rt *tokenProbeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Value(tokenProbeKey{}) == nil {
+		return rt.next.RoundTrip(req)
+	}
+
+	rt.token = strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+}
+
+// replayedAccessToken is the access token returned when the requests are replayed from a cassette
+const replayedAccessToken = "REDACTED"
+
+// AccessTokenForConfig run the authentication flow configured in config, reusing or refreshing the cached
+// token when possible, and return the access token that will be sent to the server
+func AccessTokenForConfig(ctx context.Context, config *Config) (string, error) {
+	token, err := TokenForConfig(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
+
+// TokenForConfig is like AccessTokenForConfig but return the complete token, with its expiration if the
+// authentication flow has saved it in the auth cache
+func TokenForConfig(ctx context.Context, config *Config) (*oauth2.Token, error) {
+	if len(config.BearerToken) > 0 {
+		return &oauth2.Token{AccessToken: config.BearerToken}, nil
+	}
+
+	// the token is masked in the cassettes, so no real token can be replayed
+	if len(config.ReplayFile) > 0 {
+		return &oauth2.Token{AccessToken: replayedAccessToken}, nil
+	}
+
+	if authProvider == nil {
+		return nil, errors.New("no auth provider is registered")
+	}
+
+	next, err := transport.NewTransport(baseTransportConfig(config))
+	if err != nil {
+		return nil, err
+	}
+
+	cache := cacheProviderForConfig(config)
+	probe := &tokenProbeRoundTripper{next: next}
+	roundTripper := authProvider(config, cache, config.AuthConfig).Wrap(probe)
+
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, tokenProbeKey{}, true), http.MethodGet, config.Host, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := roundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if len(probe.token) == 0 {
+		return nil, errors.New("the authentication flow has not returned an access token")
+	}
+
+	if cached := cache.ReadJWTToken(); cached != nil && cached.AccessToken == probe.token {
+		return cached, nil
+	}
+
+	return &oauth2.Token{AccessToken: probe.token}, nil
+}
@@ -16,10 +16,15 @@
 package client
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 )
 
 func testAuthProviderCreator(*Config, AuthCacheReadWriter, AuthConfig) AuthProvider {
@@ -36,3 +41,118 @@ func TestSetAuthorizationProvider(t *testing.T) {
 	assert.Error(t, RegisterAuthProvider(testAuthProviderCreator))
 	authProvider = nil
 }
+
+type testTokenAuthProvider struct {
+	token string
+	err   error
+}
+
+func (ap *testTokenAuthProvider) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if ap.err != nil {
+			return nil, ap.err
+		}
+		if len(ap.token) > 0 {
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", "Bearer "+ap.token)
+		}
+		return rt.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return fn(req) }
+
+func TestAccessTokenForConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		assert.Fail(t, "the probe request must not reach the server")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		config        *Config
+		provider      *testTokenAuthProvider
+		expectedToken string
+		expectErr     bool
+	}{
+		"bearer token in config": {
+			config:        &Config{Host: server.URL, BearerToken: "config-token"},
+			provider:      &testTokenAuthProvider{err: errors.New("must not be called")},
+			expectedToken: "config-token",
+		},
+		"token from auth provider": {
+			config:        &Config{Host: server.URL},
+			provider:      &testTokenAuthProvider{token: "token"},
+			expectedToken: "token",
+		},
+		"auth provider error": {
+			config:    &Config{Host: server.URL},
+			provider:  &testTokenAuthProvider{err: errors.New("auth error")},
+			expectErr: true,
+		},
+		"auth provider without token": {
+			config:    &Config{Host: server.URL},
+			provider:  &testTokenAuthProvider{},
+			expectErr: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			authProvider = func(*Config, AuthCacheReadWriter, AuthConfig) AuthProvider { return testCase.provider }
+			defer func() { authProvider = nil }()
+
+			token, err := AccessTokenForConfig(t.Context(), testCase.config)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedToken, token)
+		})
+	}
+}
+
+type testAuthCache struct {
+	token *oauth2.Token
+}
+
+func (c *testAuthCache) ReadJWTToken() *oauth2.Token   { return c.token }
+func (c *testAuthCache) WriteJWTToken(t *oauth2.Token) { c.token = t }
+
+func TestTokenForConfig(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	testCases := map[string]struct {
+		cache          *testAuthCache
+		expectedExpiry time.Time
+	}{
+		"token saved in cache": {
+			cache:          &testAuthCache{token: &oauth2.Token{AccessToken: "token", Expiry: expiry}},
+			expectedExpiry: expiry,
+		},
+		"cache with another token": {
+			cache: &testAuthCache{token: &oauth2.Token{AccessToken: "other", Expiry: expiry}},
+		},
+		"empty cache": {
+			cache: &testAuthCache{},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			authProvider = func(*Config, AuthCacheReadWriter, AuthConfig) AuthProvider {
+				return &testTokenAuthProvider{token: "token"}
+			}
+			defer func() { authProvider = nil }()
+
+			config := &Config{Host: "https://example.com", AuthCacheReadWriter: testCase.cache}
+			token, err := TokenForConfig(t.Context(), config)
+			require.NoError(t, err)
+			assert.Equal(t, "token", token.AccessToken)
+			assert.Equal(t, testCase.expectedExpiry, token.Expiry)
+		})
+	}
+}
@@ -16,6 +16,7 @@
 package client
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
@@ -51,8 +52,51 @@ type Config struct {
 	// Environment contains the environment scope that can be used for filtering requests
 	Environment string
 
+	// ContextName contains the name of the context the config has been read from, if any
+	ContextName string
+
+	// AuthName contains the name of the auth configuration used for the AuthConfig, if any
+	AuthName string
+
+	// BearerToken is a pre-obtained token that will be used for authenticating the requests,
+	// if set the AuthConfig will be ignored
+	BearerToken string
+
 	// The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.
 	Timeout time.Duration
+
+	// ProxyURL is the proxy used for all the requests, if empty the proxy set in the environment is used
+	ProxyURL string
+	// NoProxy is a comma separated list of hosts, domains and CIDRs that are reached without the proxy
+	NoProxy string
+
+	// RecordFile is the path of a cassette where all the requests and responses will be saved
+	RecordFile string
+	// ReplayFile is the path of a cassette used for answering the requests without contacting the server,
+	// when set no authentication is performed
+	ReplayFile string
+
+	// DryRun print the requests that can change the remote state in place of sending them
+	DryRun bool
+	// DryRunJSON print the requests intercepted by DryRun as json objects
+	DryRunJSON bool
+
+	// AuditFile is the path of the audit log where the requests that can change the remote state are saved
+	AuditFile string
+	// Command is the command line saved in the audit log
+	Command string
+
+	// RetryMaxAttempts is the maximum number of times a request that failed for a transient error is sent,
+	// a value of zero or one disable the retries
+	RetryMaxAttempts int
+
+	// RetryMaxDelay is the maximum wait between two attempts of the same request, a value of zero means
+	// transport.DefaultRetryMaxDelay
+	RetryMaxDelay time.Duration
+
+	// TokenRefreshWindow is the time before the expiration of the cached access token when a new token will be
+	// requested, a value of zero means DefaultTokenRefreshWindow
+	TokenRefreshWindow time.Duration
 }
 
 // TLSClientConfig contains settings to enable transport layer security
@@ -62,6 +106,13 @@ type TLSClientConfig struct {
 
 	// Trusted root certificates for server
 	CAFile string
+
+	// Client certificate and key for servers requiring mutual TLS, the pem encoded data fields are used
+	// only when the corresponding file is not set
+	CertFile string
+	KeyFile  string
+	CertData []byte
+	KeyData  []byte
 }
 
 // AuthConfig contains settings for settign up authentication for the http requests
@@ -70,6 +121,51 @@ type AuthConfig struct {
 	ClientSecret      string
 	JWTKeyID          string
 	JWTPrivateKeyData string
+
+	// JWTPrivateKeyFile is the path of a pem file containing the private key, it is read only when a new
+	// access token is needed and can be encrypted with JWTPrivateKeyPassphrase
+	JWTPrivateKeyFile string
+	// JWTPrivateKeyPassphrase is the passphrase of an encrypted JWTPrivateKeyFile, if empty it will be asked
+	// to the user when needed
+	JWTPrivateKeyPassphrase string
+
+	// Exec contains the settings for retrieving the access token from an external command
+	Exec *ExecConfig
+
+	// LoginMode select how the user login flow is performed when no service account is configured,
+	// one of LoginModeBrowser, LoginModeDevice or LoginModePaste; if empty LoginModeBrowser is used
+	LoginMode string
+}
+
+const (
+	// LoginModeBrowser open the browser and receive the authorization code on a local server
+	LoginModeBrowser = "browser"
+	// LoginModeDevice print a url and a code to use on another device, and wait for the user authorization
+	LoginModeDevice = "device"
+	// LoginModePaste print a url to open on another device, and read the address of the page the browser
+	// has been redirected to after the login
+	LoginModePaste = "paste"
+)
+
+// ValidateLoginMode return an error if loginMode is not one of the supported login modes
+func ValidateLoginMode(loginMode string) error {
+	switch loginMode {
+	case "", LoginModeBrowser, LoginModeDevice, LoginModePaste:
+		return nil
+	default:
+		return fmt.Errorf("unsupported login mode %q, allowed values are %s, %s and %s", loginMode, LoginModeBrowser, LoginModeDevice, LoginModePaste)
+	}
+}
+
+// ExecConfig contains settings for running an external command that will return an access token
+type ExecConfig struct {
+	// Command is the executable to run
+	Command string
+	// Args are the arguments to pass to the command
+	Args []string
+	// Env contains additional environment variables in the form key=value that are added to
+	// the current process environment
+	Env []string
 }
 
 // contentConfig contains settings that affect how objects are transformed when sent to the server.
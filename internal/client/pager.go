@@ -0,0 +1,246 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPageSize is the number of items requested for every page when all the pages are fetched
+	// without setting a page size
+	DefaultPageSize = 100
+
+	defaultPageParam     = "page"
+	defaultPageSizeParam = "per_page"
+)
+
+// PageOptions select the pages fetched from a list api
+type PageOptions struct {
+	// Page is the first page to fetch, starting from 1
+	Page int
+	// PageSize is the number of items requested for every page, 0 keep the default of the server
+	PageSize int
+	// All fetch every page starting from Page
+	All bool
+}
+
+// Validate return an error if the options cannot be used for fetching a page
+func (o PageOptions) Validate() error {
+	if o.Page < 0 {
+		return fmt.Errorf("invalid page %d: must be a positive number", o.Page)
+	}
+	if o.PageSize < 0 {
+		return fmt.Errorf("invalid page size %d: must be a positive number", o.PageSize)
+	}
+	return nil
+}
+
+// Pager iterate over the pages of a list api, following the next link of the Link header when the server
+// return it, or incrementing the page query param until an empty or incomplete page is received
+type Pager struct {
+	request *Request
+	options PageOptions
+
+	pageParam     string
+	pageSizeParam string
+}
+
+// NewPager return a Pager that fetch the pages selected by options calling the list api of request
+func NewPager(request *Request, options PageOptions) *Pager {
+	return &Pager{
+		request:       request,
+		options:       options,
+		pageParam:     defaultPageParam,
+		pageSizeParam: defaultPageSizeParam,
+	}
+}
+
+// PageParams change the names of the query params used for the page number and the page size
+func (p *Pager) PageParams(pageParam, pageSizeParam string) *Pager {
+	p.pageParam = pageParam
+	p.pageSizeParam = pageSizeParam
+	return p
+}
+
+// EachPage call fn with the response of every page selected by the options, the iteration stops at the
+// first error returned by fn or by the server
+func (p *Pager) EachPage(ctx context.Context, fn func(*Response) error) error {
+	if err := p.options.Validate(); err != nil {
+		return err
+	}
+
+	page := max(p.options.Page, 1)
+	pageSize := p.options.PageSize
+	if p.options.All && pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+	// without any option the request is sent as is, leaving the pagination to the server defaults
+	paginated := p.options.All || pageSize > 0 || page > 1
+
+	var previousBody []byte
+	// a page shorter than the size requested by the caller is the last one, while the default size can be
+	// higher than the server limit, so without it a page is the last one only if it is shorter than the
+	// ones already received
+	largestPage := p.options.PageSize
+	for {
+		if paginated && p.request.absoluteURL == nil {
+			p.setParam(p.pageParam, strconv.Itoa(page))
+			if pageSize > 0 {
+				p.setParam(p.pageSizeParam, strconv.Itoa(pageSize))
+			}
+		}
+
+		resp, err := p.request.Do(ctx)
+		if err != nil {
+			return fmt.Errorf("error executing request: %w", err)
+		}
+		if err := resp.Error(); err != nil {
+			return err
+		}
+
+		// a server that ignores the page param keeps returning the same items
+		if previousBody != nil && bytes.Equal(previousBody, resp.body) {
+			return nil
+		}
+
+		if err := fn(resp); err != nil {
+			return err
+		}
+
+		if !p.options.All {
+			return nil
+		}
+
+		next, err := p.nextLink(resp)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			p.request.absoluteURL = next
+			continue
+		}
+		if p.request.absoluteURL != nil {
+			// the last page of a server using links has no next link
+			return nil
+		}
+
+		items, ok := countItems(resp.body)
+		if !ok || items == 0 || items < largestPage {
+			return nil
+		}
+
+		largestPage = max(largestPage, items)
+		previousBody = resp.body
+		page++
+	}
+}
+
+// ListPages return the items of all the pages fetched by pager
+func ListPages[T any](ctx context.Context, pager *Pager) ([]T, error) {
+	items := make([]T, 0)
+	err := pager.EachPage(ctx, func(resp *Response) error {
+		pageItems := make([]T, 0)
+		if err := resp.ParseResponse(&pageItems); err != nil {
+			return fmt.Errorf("error parsing response body: %w", err)
+		}
+
+		items = append(items, pageItems...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (p *Pager) setParam(key, value string) {
+	if p.request.params == nil {
+		p.request.params = make(url.Values)
+	}
+	p.request.params.Set(key, value)
+}
+
+// nextLink return the url with rel="next" in the Link header of resp if present, the link must point to
+// the same server of the request for not sending the credentials elsewhere
+func (p *Pager) nextLink(resp *Response) (*url.URL, error) {
+	if resp.rawResponse == nil || resp.rawRequest == nil {
+		return nil, nil
+	}
+
+	link := nextLinkFromHeader(resp.rawResponse.Header)
+	if len(link) == 0 {
+		return nil, nil
+	}
+
+	next, err := resp.rawRequest.URL.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid next page link %q: %w", link, err)
+	}
+
+	baseURL := p.request.restClient.baseURL
+	if next.Scheme != baseURL.Scheme || next.Host != baseURL.Host {
+		return nil, errors.New("the next page link points to a different server from the one in use")
+	}
+
+	return next, nil
+}
+
+// nextLinkFromHeader parse the Link headers looking for the entry with rel="next"
+func nextLinkFromHeader(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for entry := range strings.SplitSeq(value, ",") {
+			parts := strings.Split(entry, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+				if !found || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+
+				for rel := range strings.FieldsSeq(strings.Trim(strings.TrimSpace(value), `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// countItems return the number of items of a page, false is returned if the body is not a json array
+func countItems(body []byte) (int, bool) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return 0, false
+	}
+	return len(items), true
+}
@@ -0,0 +1,216 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedServer return a server that paginate total items with the page and per_page params, and with a
+// page size limit of maxPageSize
+func pagedServer(t *testing.T, total, maxPageSize int, requests *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if pageSize == 0 {
+			pageSize = 10
+		}
+		pageSize = min(pageSize, maxPageSize)
+
+		items := make([]int, 0)
+		for item := (page - 1) * pageSize; item < min(page*pageSize, total); item++ {
+			items = append(items, item)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(items))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPagerWithPageParams(t *testing.T) {
+	testCases := map[string]struct {
+		options          PageOptions
+		total            int
+		maxPageSize      int
+		expectedItems    int
+		expectedRequests []string
+	}{
+		"no options keep the server defaults": {
+			total:            25,
+			maxPageSize:      100,
+			expectedItems:    10,
+			expectedRequests: []string{""},
+		},
+		"single page": {
+			options:          PageOptions{Page: 2, PageSize: 5},
+			total:            25,
+			maxPageSize:      100,
+			expectedItems:    5,
+			expectedRequests: []string{"page=2&per_page=5"},
+		},
+		"all pages": {
+			options:          PageOptions{All: true, PageSize: 10},
+			total:            25,
+			maxPageSize:      100,
+			expectedItems:    25,
+			expectedRequests: []string{"page=1&per_page=10", "page=2&per_page=10", "page=3&per_page=10"},
+		},
+		"all pages from the requested one": {
+			options:          PageOptions{All: true, Page: 2, PageSize: 10},
+			total:            25,
+			maxPageSize:      100,
+			expectedItems:    15,
+			expectedRequests: []string{"page=2&per_page=10", "page=3&per_page=10"},
+		},
+		"all pages with exact size": {
+			options:          PageOptions{All: true, PageSize: 10},
+			total:            20,
+			maxPageSize:      100,
+			expectedItems:    20,
+			expectedRequests: []string{"page=1&per_page=10", "page=2&per_page=10", "page=3&per_page=10"},
+		},
+		"all pages with a short first page": {
+			options:          PageOptions{All: true, PageSize: 10},
+			total:            5,
+			maxPageSize:      100,
+			expectedItems:    5,
+			expectedRequests: []string{"page=1&per_page=10"},
+		},
+		"all pages with a server limit": {
+			options:          PageOptions{All: true},
+			total:            25,
+			maxPageSize:      10,
+			expectedItems:    25,
+			expectedRequests: []string{"page=1&per_page=100", "page=2&per_page=100", "page=3&per_page=100"},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			requests := make([]string, 0)
+			server := pagedServer(t, testCase.total, testCase.maxPageSize, &requests)
+			client := testAPIServer(t, server)
+
+			items, err := ListPages[int](t.Context(), NewPager(client.Get().APIPath("/items"), testCase.options))
+			require.NoError(t, err)
+			assert.Len(t, items, testCase.expectedItems)
+			assert.Equal(t, testCase.expectedRequests, requests)
+		})
+	}
+}
+
+func TestPagerServerIgnoringPagination(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Write([]byte(`[1,2,3]`))
+	}))
+	defer server.Close()
+
+	client := testAPIServer(t, server)
+	items, err := ListPages[int](t.Context(), NewPager(client.Get().APIPath("/items"), PageOptions{All: true, PageSize: 3}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+	assert.Equal(t, 2, requests)
+}
+
+func TestPagerWithLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `</items?cursor=second>; rel="next", </items?cursor=last>; rel="last"`)
+			w.Write([]byte(`[1,2]`))
+		case "second":
+			w.Header().Add("Link", `<`+server.URL+`/items?cursor=last>; rel="next"`)
+			w.Write([]byte(`[3,4]`))
+		case "last":
+			w.Write([]byte(`[5]`))
+		case "other":
+			w.Header().Set("Link", `<http://example.com/items?cursor=last>; rel="next"`)
+			w.Write([]byte(`[1]`))
+		}
+	}))
+	defer server.Close()
+
+	client := testAPIServer(t, server)
+	items, err := ListPages[int](t.Context(), NewPager(client.Get().APIPath("/items"), PageOptions{All: true}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+
+	request := client.Get().APIPath("/items").SetParam("cursor", "other")
+	_, err = ListPages[int](t.Context(), NewPager(request, PageOptions{All: true}))
+	assert.ErrorContains(t, err, "different server")
+}
+
+func TestPagerErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"forbidden"}`))
+	}))
+	defer server.Close()
+
+	client := testAPIServer(t, server)
+	_, err := ListPages[int](t.Context(), NewPager(client.Get().APIPath("/items"), PageOptions{All: true}))
+	assert.EqualError(t, err, "forbidden")
+
+	_, err = ListPages[int](t.Context(), NewPager(client.Get().APIPath("/items"), PageOptions{PageSize: -1}))
+	assert.EqualError(t, err, "invalid page size -1: must be a positive number")
+}
+
+func TestNextLinkFromHeader(t *testing.T) {
+	testCases := map[string]struct {
+		values   []string
+		expected string
+	}{
+		"no header": {},
+		"next link": {
+			values:   []string{`<https://example.com/a?page=2>; rel="next"`},
+			expected: "https://example.com/a?page=2",
+		},
+		"multiple relations": {
+			values:   []string{`<https://example.com/a?page=1>; rel="first", <https://example.com/a?page=3>; rel="last next"`},
+			expected: "https://example.com/a?page=3",
+		},
+		"multiple headers": {
+			values:   []string{`</a?page=1>; rel=prev`, `</a?page=3>; rel=next`},
+			expected: "/a?page=3",
+		},
+		"no next link": {
+			values: []string{`<https://example.com/a?page=1>; rel="prev"`},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			header := http.Header{}
+			for _, value := range testCase.values {
+				header.Add("Link", value)
+			}
+			assert.Equal(t, testCase.expected, nextLinkFromHeader(header))
+		})
+	}
+}
@@ -37,6 +37,10 @@ type Request struct {
 
 	err  error
 	body []byte
+
+	// absoluteURL replace the url built from apiPath and params, it is used for following the next page
+	// links returned by the server
+	absoluteURL *url.URL
 }
 
 // NewRequest creates a new request helper object for calling Mia-Platform Console API
@@ -133,6 +137,11 @@ func (r *Request) Error() error {
 
 // URL return the url that will be used by the http.Request in this moment
 func (r *Request) URL() *url.URL {
+	if r.absoluteURL != nil {
+		finalURL := *r.absoluteURL
+		return &finalURL
+	}
+
 	finalURL := *r.restClient.baseURL
 
 	finalURL.Path = strings.TrimSuffix(finalURL.Path, "/") + r.apiPath
@@ -23,31 +23,87 @@ import (
 	"net/http"
 
 	"github.com/mia-platform/miactl/internal/resources"
+	"github.com/mia-platform/miactl/internal/transport"
 )
 
-// ResponseError represent an error from an api call
+var (
+	// ErrBadRequest match the ResponseError with status code 400
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized match the ResponseError with status code 401
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden match the ResponseError with status code 403
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound match the ResponseError with status code 404
+	ErrNotFound = errors.New("not found")
+	// ErrConflict match the ResponseError with status code 409
+	ErrConflict = errors.New("conflict")
+	// ErrServerError match the ResponseError with a 5xx status code
+	ErrServerError = errors.New("server error")
+)
+
+// ResponseError represent an error from an api call, it can be compared with errors.Is to the sentinel
+// errors of this package for checking its status code
 type ResponseError struct {
 	body       []byte
 	statusCode int
 }
 
+// StatusCode return the status code of the response
+func (r *ResponseError) StatusCode() int {
+	return r.statusCode
+}
+
+// APIError return the error returned by the server in the response body, or nil if the body does not
+// contain one
+func (r *ResponseError) APIError() *resources.APIError {
+	var out *resources.APIError
+	if err := parseBody(r.body, &out); err != nil || out == nil || (len(out.Message) == 0 && out.StatusCode == 0) {
+		return nil
+	}
+	return out
+}
+
+// Is return true if target is the sentinel error matching the status code of the response
+func (r *ResponseError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return r.statusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return r.statusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return r.statusCode == http.StatusForbidden
+	case ErrNotFound:
+		return r.statusCode == http.StatusNotFound
+	case ErrConflict:
+		return r.statusCode == http.StatusConflict
+	case ErrServerError:
+		return r.statusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
 // Error return the message of the api call error
 func (r *ResponseError) Error() string {
 	var out *resources.APIError
 	err := parseBody(r.body, &out)
-	if err != nil {
+	if err != nil && len(r.body) > 0 && json.Valid(r.body) {
 		return fmt.Sprintf("cannot parse server response: %s", err)
 	}
 
-	if len(out.Message) > 0 {
+	if out != nil && len(out.Message) > 0 {
 		return out.Message
 	}
 
 	switch r.statusCode {
 	case http.StatusBadRequest:
 		return "something went wrong"
+	case http.StatusUnauthorized:
+		return "the credentials are not valid or have expired, please login again"
 	case http.StatusForbidden:
 		return "you are not allowed to make this call, contact your admin"
+	case http.StatusNotFound:
+		return "the requested resource has not been found"
 	default:
 		return fmt.Sprintf("error received from remote: %d", r.statusCode)
 	}
@@ -73,6 +129,18 @@ func (r *Response) StatusCode() int {
 	return r.statusCode
 }
 
+// Body return the raw body of the response, the body of a response with an error status code can be read
+// only through the error returned by Error
+func (r *Response) Body() []byte {
+	return r.body
+}
+
+// DryRun return true if the request has been intercepted by the dry run, and the response has not been sent
+// by the server
+func (r *Response) DryRun() bool {
+	return r.rawResponse != nil && r.rawResponse.Header.Get(transport.DryRunHeader) == "true"
+}
+
 // Error return the error found in the response
 func (r *Response) Error() error {
 	switch {
@@ -17,10 +17,15 @@ package client
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/resources"
+	"github.com/mia-platform/miactl/internal/transport"
 )
 
 func TestParseResponse(t *testing.T) {
@@ -59,3 +64,67 @@ func TestParseResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseDryRun(t *testing.T) {
+	dryRunHeader := make(http.Header)
+	dryRunHeader.Set(transport.DryRunHeader, "true")
+
+	assert.True(t, (&Response{rawResponse: &http.Response{Header: dryRunHeader}}).DryRun())
+	assert.False(t, (&Response{rawResponse: &http.Response{Header: make(http.Header)}}).DryRun())
+	assert.False(t, (&Response{err: errors.New("network error")}).DryRun())
+}
+
+func TestResponseError(t *testing.T) {
+	testCases := map[string]struct {
+		err              *ResponseError
+		expectedMessage  string
+		expectedAPIError *resources.APIError
+		matches          []error
+		notMatches       []error
+	}{
+		"not found with message": {
+			err:              &ResponseError{body: []byte(`{"statusCode":404,"message":"project not found"}`), statusCode: http.StatusNotFound},
+			expectedMessage:  "project not found",
+			expectedAPIError: &resources.APIError{StatusCode: http.StatusNotFound, Message: "project not found"},
+			matches:          []error{ErrNotFound},
+			notMatches:       []error{ErrForbidden, ErrServerError},
+		},
+		"forbidden without body": {
+			err:             &ResponseError{statusCode: http.StatusForbidden},
+			expectedMessage: "you are not allowed to make this call, contact your admin",
+			matches:         []error{ErrForbidden},
+			notMatches:      []error{ErrUnauthorized, ErrNotFound},
+		},
+		"server error with html body": {
+			err:             &ResponseError{body: []byte(`<html>Bad Gateway</html>`), statusCode: http.StatusBadGateway},
+			expectedMessage: "error received from remote: 502",
+			matches:         []error{ErrServerError},
+			notMatches:      []error{ErrBadRequest, ErrConflict},
+		},
+		"conflict": {
+			err:              &ResponseError{body: []byte(`{"message":"already exists"}`), statusCode: http.StatusConflict},
+			expectedMessage:  "already exists",
+			expectedAPIError: &resources.APIError{Message: "already exists"},
+			matches:          []error{ErrConflict},
+			notMatches:       []error{ErrServerError},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var err error = fmt.Errorf("wrapped: %w", testCase.err)
+			assert.Equal(t, testCase.expectedMessage, testCase.err.Error())
+			assert.Equal(t, testCase.expectedAPIError, testCase.err.APIError())
+
+			var responseErr *ResponseError
+			require.ErrorAs(t, err, &responseErr)
+			assert.Equal(t, testCase.err.statusCode, responseErr.StatusCode())
+			for _, sentinel := range testCase.matches {
+				assert.ErrorIs(t, err, sentinel)
+			}
+			for _, sentinel := range testCase.notMatches {
+				assert.NotErrorIs(t, err, sentinel)
+			}
+		})
+	}
+}
@@ -55,23 +55,61 @@ func transportForConfig(config *Config) (http.RoundTripper, error) {
 		return config.Transport, nil
 	}
 
-	transportConfig := &transport.Config{
+	transportConfig := baseTransportConfig(config)
+	transportConfig.BearerToken = config.BearerToken
+	// the dry run is not part of the base config for letting the login requests reach the server
+	transportConfig.DryRun = transport.DryRunConfig{
+		Enabled: config.DryRun,
+		JSON:    config.DryRunJSON,
+	}
+	transportConfig.Audit = transport.AuditConfig{
+		File:        config.AuditFile,
+		Context:     config.ContextName,
+		CompanyID:   config.CompanyID,
+		ProjectID:   config.ProjectID,
+		Environment: config.Environment,
+		Command:     config.Command,
+	}
+
+	// the replayed responses do not need the authorization, and the credentials are masked in the cassette
+	if authProvider != nil && len(config.BearerToken) == 0 && len(config.ReplayFile) == 0 {
+		provider := authProvider(config, cacheProviderForConfig(config), config.AuthConfig)
+		transportConfig.AuthorizeWrapper = provider.Wrap
+	}
+
+	return transport.NewTransport(transportConfig)
+}
+
+// baseTransportConfig return the transport configuration for config without any authorization
+func baseTransportConfig(config *Config) *transport.Config {
+	return &transport.Config{
 		UserAgent: config.UserAgent,
 		TLSConfig: transport.TLSConfig{
 			Insecure: config.Insecure,
 			CAFile:   config.CAFile,
+			CertFile: config.CertFile,
+			KeyFile:  config.KeyFile,
+			CertData: config.CertData,
+			KeyData:  config.KeyData,
+		},
+		Proxy: transport.ProxyConfig{
+			URL:     config.ProxyURL,
+			NoProxy: config.NoProxy,
+		},
+		RecordFile: config.RecordFile,
+		ReplayFile: config.ReplayFile,
+		Verbose:    logger.LogLevel >= 5,
+		Retry: transport.RetryConfig{
+			MaxAttempts: config.RetryMaxAttempts,
+			MaxDelay:    config.RetryMaxDelay,
 		},
-		Verbose: logger.LogLevel >= 5,
 	}
+}
 
-	if authProvider != nil {
-		var cacheProvider AuthCacheReadWriter = &noopProvider{}
-		if config.AuthCacheReadWriter != nil {
-			cacheProvider = config.AuthCacheReadWriter
-		}
-		provider := authProvider(config, cacheProvider, config.AuthConfig)
-		transportConfig.AuthorizeWrapper = provider.Wrap
+// cacheProviderForConfig return the AuthCacheReadWriter set in config or a noop one
+func cacheProviderForConfig(config *Config) AuthCacheReadWriter {
+	if config.AuthCacheReadWriter != nil {
+		return config.AuthCacheReadWriter
 	}
-
-	return transport.NewTransport(transportConfig)
+	return &noopProvider{}
 }
@@ -16,31 +16,47 @@
 package clioptions
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"time"
 
+	"github.com/mia-platform/miactl/internal/audit"
 	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
 	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/logger"
+	"github.com/mia-platform/miactl/internal/transport"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
+// sharedFlagNames are the flags registered by more commands, with different defaults, on the same value
+var sharedFlagNames = []string{"output", "revision", "branch", "deploy-type"}
+
 type CLIOptions struct {
 	MiactlConfig string
 
-	Endpoint string
-	Insecure bool
-	CAFile   string
+	Endpoint       string
+	Insecure       bool
+	CAFile         string
+	ClientCertFile string
+	ClientKeyFile  string
+	ProxyURL       string
+	NoProxy        string
 
 	Context     string
 	Auth        string
 	ProjectID   string
 	CompanyID   string
 	Environment string
+	LoginMode   string
 
 	Revision   string
 	Version    string
@@ -61,13 +77,21 @@ type CLIOptions struct {
 	UserEmails []string
 	UserIDs    []string
 
-	Page int
+	Page     int
+	PageSize int
+	AllPages bool
 
-	ServiceAccountID string
+	ServiceAccountID      string
+	ServiceAccountKeyType string
+	UpdateAuthName        string
 
 	BasicClientID     string
 	BasicClientSecret string
 	JWTJsonPath       string
+	JWTPrivateKeyFile string
+	ExecCommand       string
+	ExecArgs          []string
+	ExecEnv           []string
 	OutputPath        string
 
 	InputFilePath string
@@ -90,6 +114,15 @@ type CLIOptions struct {
 
 	FollowLogs bool
 
+	APIMethod   string
+	APIFields   []string
+	APIHeaders  []string
+	APIPaginate bool
+	APIFilter   string
+
+	AuditSince string
+	AuditUntil string
+
 	// OutputFormat describes the output format of some commands. Can be json or yaml.
 	OutputFormat string
 
@@ -99,8 +132,43 @@ type CLIOptions struct {
 
 	ResolveExtensionsDetails bool
 
+	Minify       bool
+	Force        bool
+	DryRun       bool
+	DryRunOutput string
+
+	LogoutAll            bool
+	ExecCredentialOutput bool
+
 	Message            string
 	ReleaseDescription string
+
+	// Preferences contains the defaults read from the config file by ApplyPreferences
+	Preferences *api.Preferences
+
+	PreferredOutputFormat string
+	PreferredRevision     string
+	PreferredBranch       string
+	PreferredDeployType   string
+
+	PreferredTokenRefreshWindow string
+	PreferredRetryMaxAttempts   int
+	PreferredRetryMaxDelay      string
+	PreferredAuditFile          string
+
+	RetryMaxAttempts int
+	RetryMaxDelay    time.Duration
+	RequestTimeout   time.Duration
+	RecordFile       string
+	ReplayFile       string
+	AuditFile        string
+
+	// WaitTimeout is the maximum duration of the commands waiting for a remote operation to end
+	WaitTimeout time.Duration
+
+	// in and errOut are used for interacting with the user, if nil os.Stdin and os.Stderr are used
+	in     io.Reader
+	errOut io.Writer
 }
 
 // NewCLIOptions return a new CLIOptions instance
@@ -113,6 +181,14 @@ func (o *CLIOptions) AddGlobalFlags(flags *pflag.FlagSet) {
 	configFilePathDescription := "path to the config file default to " + locator.DefaultConfigPath()
 	flags.StringVarP(&o.MiactlConfig, "config", "c", "", configFilePathDescription)
 	flags.IntVarP(&logger.LogLevel, "verbose", "v", 0, "increase the verbosity of the cli output")
+	flags.IntVar(&o.RetryMaxAttempts, "retry-max-attempts", 0, fmt.Sprintf("the number of attempts for the requests failed with a transient error, 1 disables the retries (default %d)", transport.DefaultRetryMaxAttempts))
+	flags.DurationVar(&o.RetryMaxDelay, "retry-max-delay", 0, fmt.Sprintf("the maximum wait between two attempts of a request (default %s)", transport.DefaultRetryMaxDelay))
+	flags.DurationVar(&o.RequestTimeout, "request-timeout", 0, "the maximum duration of a single request to the server, like 30s; zero means no timeout")
+	flags.StringVar(&o.RecordFile, "record", "", "save all the requests and responses in a cassette file with masked credentials, in HAR format if the file has the .har extension")
+	flags.StringVar(&o.ReplayFile, "replay", "", "answer the requests with the responses saved in a cassette file, without contacting the server")
+	flags.BoolVar(&o.DryRun, "dry-run", false, "print the requests that would change the remote state on the standard error in place of sending them")
+	flags.StringVar(&o.DryRunOutput, "dry-run-output", "text", "format of the requests printed by --dry-run. Allowed values: text, json")
+	flags.StringVar(&o.AuditFile, "audit-file", "", "append the requests that change the remote state to this audit log, in place of the one set in the preferences")
 }
 
 func (o *CLIOptions) AddConnectionFlags(flags *pflag.FlagSet) {
@@ -126,6 +202,10 @@ func (o *CLIOptions) AddConnectionFlags(flags *pflag.FlagSet) {
 		"insecure-skip-tls-verify",
 		false,
 		"if true, the server's certificate will not be checked for validity. This will make your HTTPS connections insecure")
+	flags.StringVar(&o.ClientCertFile, "client-certificate", "", "path to a client certificate file for servers requiring mutual TLS")
+	flags.StringVar(&o.ClientKeyFile, "client-key", "", "path to the key file of the client certificate")
+	flags.StringVar(&o.ProxyURL, "proxy-url", "", "the url of the proxy used for reaching the Console, in place of the one set in the environment")
+	flags.StringVar(&o.NoProxy, "no-proxy", "", "comma separated list of hosts, domains and CIDRs reached without using the proxy")
 }
 
 func (o *CLIOptions) AddContextFlags(flags *pflag.FlagSet) {
@@ -135,6 +215,7 @@ func (o *CLIOptions) AddContextFlags(flags *pflag.FlagSet) {
 
 func (o *CLIOptions) AddAuthFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.Auth, "auth-name", "", "the name of the miactl auth to use")
+	flags.StringVar(&o.LoginMode, "login-mode", "", "how to perform the user login when no service account is used, one of browser, device or paste")
 }
 
 func (o *CLIOptions) AddProjectFlags(flags *pflag.FlagSet) {
@@ -179,6 +260,47 @@ func (o *CLIOptions) AddContextAuthFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.BasicClientID, "client-id", "", "the client ID of the service account")
 	flags.StringVar(&o.BasicClientSecret, "client-secret", "", "the client secret of the service account")
 	flags.StringVar(&o.JWTJsonPath, "jwt-json", "", "path of the json containing the json config of a jwt service account")
+	flags.StringVar(&o.JWTPrivateKeyFile, "jwt-private-key-file", "", "path of the pem file with the private key of the jwt service account, the key will not be copied in the config")
+	flags.StringVar(&o.ExecCommand, "exec-command", "", "command to run for retrieving the access token from an exec credential plugin")
+	flags.StringArrayVar(&o.ExecArgs, "exec-arg", []string{}, "argument to pass to the exec credential plugin, can be repeated")
+	flags.StringArrayVar(&o.ExecEnv, "exec-env", []string{}, "environment variable in the form key=value to set for the exec credential plugin, can be repeated")
+}
+
+func (o *CLIOptions) AddContextViewFlags(flags *pflag.FlagSet) {
+	o.AddOutputFormatFlag(flags, "json")
+	flags.BoolVar(&o.Minify, "minify", false, "print only the current context and its credential")
+}
+
+func (o *CLIOptions) AddDeleteAuthFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.Force, "force", false, "delete the credential even if one or more contexts are still using it")
+}
+
+func (o *CLIOptions) AddContextCheckFlags(flags *pflag.FlagSet) {
+	o.AddConnectionFlags(flags)
+	o.AddContextFlags(flags)
+	o.AddCompanyFlags(flags)
+	o.AddProjectFlags(flags)
+	o.AddEnvironmentFlags(flags)
+	flags.StringVarP(&o.OutputFormat, "output", "o", "table", "Output format. Allowed values: table, json")
+}
+
+// AddMigrateFlags add the local dry run flag of the migrate command, it hides the global one because the command
+// does not send any request and only prints the upgraded config files
+func (o *CLIOptions) AddMigrateFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.DryRun, "dry-run", false, "print the upgraded config files without saving them")
+}
+
+func (o *CLIOptions) AddLogoutFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.LogoutAll, "all", false, "remove the cached credentials of every context")
+}
+
+func (o *CLIOptions) AddAuthTokenFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.ExecCredentialOutput, "exec-credential", false, "print the token in the kubectl ExecCredential json format")
+}
+
+func (o *CLIOptions) AddAuthWhoamiFlags(flags *pflag.FlagSet) {
+	o.AddCompanyFlags(flags)
+	flags.StringVarP(&o.OutputFormat, "output", "o", "table", "Output format. Allowed values: table, json, yaml")
 }
 
 func (o *CLIOptions) AddServiceAccountFlags(flags *pflag.FlagSet) {
@@ -188,6 +310,7 @@ func (o *CLIOptions) AddServiceAccountFlags(flags *pflag.FlagSet) {
 func (o *CLIOptions) AddJWTServiceAccountFlags(flags *pflag.FlagSet) {
 	o.AddServiceAccountFlags(flags)
 	flags.StringVarP(&o.OutputPath, "output", "o", "", "write the service account configuration as json to a file")
+	flags.StringVar(&o.ServiceAccountKeyType, "key-type", "rsa", "the type of key to generate for the service account, one of rsa, ecdsa or ed25519")
 }
 
 func (o *CLIOptions) AddEditServiceAccountFlags(flags *pflag.FlagSet) {
@@ -199,6 +322,13 @@ func (o *CLIOptions) AddRemoveServiceAccountFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.ServiceAccountID, "service-account-id", "", "the service account id to remove")
 }
 
+func (o *CLIOptions) AddRotateServiceAccountFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.ServiceAccountID, "service-account-id", "", "the service account id to rotate")
+	flags.StringVarP(&o.OutputPath, "output", "o", "", "write the new jwt service account configuration as json to a file")
+	flags.StringVar(&o.ServiceAccountKeyType, "key-type", "rsa", "the type of key to generate for a jwt service account, one of rsa, ecdsa or ed25519")
+	flags.StringVar(&o.UpdateAuthName, "update-auth", "", "the name of the miactl auth configuration to update with the new credentials")
+}
+
 func (o *CLIOptions) AddNewUserFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&o.IAMRole, "role", "r", "", "the company role of the user")
 	flags.StringVar(&o.UserEmail, "email", "", "the email of the user to add")
@@ -264,6 +394,22 @@ func (o *CLIOptions) AddPageFlag(flags *pflag.FlagSet) (flagName string) {
 	return
 }
 
+// AddPaginationFlags add the flags for selecting the pages fetched by a list command
+func (o *CLIOptions) AddPaginationFlags(flags *pflag.FlagSet) {
+	o.AddPageFlag(flags)
+	flags.IntVar(&o.PageSize, "page-size", 0, "the number of items requested for every page, 0 use the server default")
+	flags.BoolVar(&o.AllPages, "all", false, "fetch all the pages starting from the one selected with --page")
+}
+
+// PageOptions return the pages selected with the pagination flags
+func (o *CLIOptions) PageOptions() client.PageOptions {
+	return client.PageOptions{
+		Page:     o.Page,
+		PageSize: o.PageSize,
+		All:      o.AllPages,
+	}
+}
+
 func (o *CLIOptions) AddMarketplaceItemObjectIDFlag(flags *pflag.FlagSet) (flagName string) {
 	flagName = "object-id"
 	flags.StringVar(&o.MarketplaceItemObjectID, flagName, "", "The _id of the Marketplace item")
@@ -295,16 +441,74 @@ func (o *CLIOptions) AddCreateJobFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.FromCronJob, "from", "", "name of the cronjob to create a Job from")
 	flags.BoolVar(&o.WaitJobCompletion, "waitJobCompletion", false, "wait for the job to complete before exiting the command")
 	flags.IntVar(&o.WaitJobTimeoutSeconds, "waitJobTimeoutSeconds", 600, "max wait for the job to complete before exiting with error")
+	o.AddWaitTimeoutFlags(flags)
+	if err := flags.MarkDeprecated("waitJobTimeoutSeconds", "use --timeout instead"); err != nil {
+		// programming error, panic and broke everything
+		panic(err)
+	}
+}
+
+func (o *CLIOptions) AddWaitTimeoutFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.WaitTimeout, "timeout", 0, "the maximum time to wait for the command to complete, like 10m; zero means no limit")
+}
+
+// WaitContext return a context that expires after the duration set with the timeout flag, or that is
+// only cancellable if the flag is not set
+func (o *CLIOptions) WaitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.WaitTimeout > 0 {
+		return context.WithTimeout(ctx, o.WaitTimeout)
+	}
+	return context.WithCancel(ctx)
 }
 
 func (o *CLIOptions) AddLogsFlags(flags *pflag.FlagSet) {
 	flags.BoolVarP(&o.FollowLogs, "follow", "f", false, "specify if the logs should be streamed")
 }
 
+// AddAPIFlags add the flags for building the request sent by the api command
+func (o *CLIOptions) AddAPIFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.APIMethod, "method", "X", "", "the HTTP method of the request, GET by default or POST when a body is set")
+	flags.StringArrayVarP(&o.APIFields, "raw-field", "f", nil, "add a key=value string to the json body, or to the query params of a GET request")
+	flags.StringVar(&o.InputFilePath, "input", "", "the file to use as request body, use - for reading it from the standard input")
+	flags.StringArrayVarP(&o.APIHeaders, "header", "H", nil, "add a header to the request in the \"Key: value\" format")
+	flags.BoolVar(&o.APIPaginate, "paginate", false, "fetch all the pages of a list api and merge them in a single array")
+	flags.StringVarP(&o.APIFilter, "jq", "q", "", "select values from the response with a jq expression")
+}
+
+// AddAuditListFlags add the flags for filtering the entries of the audit log
+func (o *CLIOptions) AddAuditListFlags(flags *pflag.FlagSet) {
+	o.AddProjectFlags(flags)
+	flags.StringVar(&o.AuditSince, "since", "", "show only the requests sent after this time")
+	flags.StringVar(&o.AuditUntil, "until", "", "show only the requests sent before this time")
+	flags.StringVarP(&o.APIMethod, "method", "X", "", "show only the requests with this HTTP method")
+	o.AddListOutputFlag(flags)
+}
+
+// AddListOutputFlag add the output flag of the commands printing a list with the Printer
+func (o *CLIOptions) AddListOutputFlag(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.OutputFormat, "output", "o", tableOutput, "Output format. Allowed values: "+listOutputFormats)
+}
+
 func (o *CLIOptions) AddOutputFormatFlag(flags *pflag.FlagSet, defaultVal string) {
 	flags.StringVarP(&o.OutputFormat, "output", "o", defaultVal, "Output format. Allowed values: json, yaml")
 }
 
+// ResetFlagDefaults set the shared flags not set by the user to the default of the command owning flags; every
+// command registering a shared flag overwrite the bound value with its default, leaving the one registered last
+func (o *CLIOptions) ResetFlagDefaults(flags *pflag.FlagSet) error {
+	for _, name := range sharedFlagNames {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if err := flag.Value.Set(flag.DefValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (o *CLIOptions) AddIAMListFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&o.ShowUsers, "users", false, "Filter IAM entities to show only users. Mutally exclusive with groups and serviceAccounts")
 	flags.BoolVar(&o.ShowGroups, "groups", false, "Filter IAM entities to show only groups. Mutally exclusive with users and serviceAccounts")
@@ -330,6 +534,13 @@ func (o *CLIOptions) AddImportFlags(flags *pflag.FlagSet) {
 }
 
 func (o *CLIOptions) ToRESTConfig() (*client.Config, error) {
+	if len(o.RecordFile) > 0 && len(o.ReplayFile) > 0 {
+		return nil, errors.New("the --record and --replay flags cannot be used together")
+	}
+	if o.DryRunOutput != "" && o.DryRunOutput != "text" && o.DryRunOutput != "json" {
+		return nil, fmt.Errorf("invalid dry run output %q, allowed values are text and json", o.DryRunOutput)
+	}
+
 	locator := cliconfig.NewConfigPathLocator()
 	locator.ExplicitPath = o.MiactlConfig
 
@@ -345,17 +556,90 @@ func (o *CLIOptions) ToRESTConfig() (*client.Config, error) {
 	overrides.Environment = o.Environment
 	overrides.Context = o.Context
 	overrides.AuthName = o.Auth
+	overrides.LoginMode = o.LoginMode
 	overrides.CertificateAuthority = o.CAFile
 	overrides.InsecureSkipTLSVerify = o.Insecure
+	overrides.ClientCertificate = o.ClientCertFile
+	overrides.ClientKey = o.ClientKeyFile
+	overrides.ProxyURL = o.ProxyURL
+	overrides.NoProxy = o.NoProxy
 
-	clientConfig, err := cliconfig.NewConfigReader(config, overrides).ClientConfig(locator)
+	envOverrides, err := cliconfig.EnvOverrides()
 	if err != nil {
 		return nil, err
 	}
+
+	reader := cliconfig.NewConfigReader(config, overrides).WithEnvOverrides(envOverrides)
+	clientConfig, err := reader.ClientConfig(locator)
+	if err != nil {
+		return nil, err
+	}
+	logConfigSources(reader.Sources())
 	clientConfig.UserAgent = defaultUserAgent()
+	clientConfig.Timeout = o.RequestTimeout
+	clientConfig.RecordFile = o.RecordFile
+	clientConfig.ReplayFile = o.ReplayFile
+	clientConfig.DryRun = o.DryRun
+	clientConfig.DryRunJSON = o.DryRunOutput == "json"
+	if auditFile := o.auditFileOverride(); len(auditFile) > 0 {
+		clientConfig.AuditFile = auditFile
+	}
+	clientConfig.Command = audit.CommandLine(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...))
+	o.applyRetryConfig(clientConfig)
 	return clientConfig, nil
 }
 
+// AuditFilePath return the path of the audit log set with the flag, the environment variable or the
+// preferences, in this order; an empty string means that the audit log is disabled
+func (o *CLIOptions) AuditFilePath() string {
+	if auditFile := o.auditFileOverride(); len(auditFile) > 0 {
+		return auditFile
+	}
+	if o.Preferences != nil {
+		return o.Preferences.AuditFile
+	}
+	return ""
+}
+
+func (o *CLIOptions) auditFileOverride() string {
+	if len(o.AuditFile) > 0 {
+		return o.AuditFile
+	}
+	return os.Getenv(audit.FileEnvVarName)
+}
+
+// applyRetryConfig set the retry flags, if set, in place of the preferences, and enable the retries when
+// neither of them set the attempts
+func (o *CLIOptions) applyRetryConfig(clientConfig *client.Config) {
+	if o.RetryMaxAttempts > 0 {
+		clientConfig.RetryMaxAttempts = o.RetryMaxAttempts
+	}
+	if o.RetryMaxDelay > 0 {
+		clientConfig.RetryMaxDelay = o.RetryMaxDelay
+	}
+	if clientConfig.RetryMaxAttempts == 0 {
+		clientConfig.RetryMaxAttempts = transport.DefaultRetryMaxAttempts
+	}
+}
+
+// logConfigSources print where every configuration value has been read from when the verbosity is high enough
+func logConfigSources(sources map[string]cliconfig.ValueSource) {
+	log := logger.NewLogger(os.Stderr).V(4)
+	if !log.Enabled() {
+		return
+	}
+
+	keys := make([]string, 0, len(sources))
+	for key := range sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		log.Info(fmt.Sprintf("%s read from %s", key, sources[key]))
+	}
+}
+
 func defaultUserAgent() string {
 	osCommand := os.Args[0]
 	command := "unknown"
@@ -0,0 +1,190 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clioptions
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/pflag"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+// ErrActionNotConfirmed is returned when the user does not confirm a destructive action
+var ErrActionNotConfirmed = errors.New("action not confirmed")
+
+// ApplyPreferences read the preferences of the selected context from the config file, and use them as
+// the value of the flags that have not been set explicitly; the preferences are optional, so a config that
+// cannot be read is only logged and left to the commands that need it
+func (o *CLIOptions) ApplyPreferences(ctx context.Context, flags *pflag.FlagSet) error {
+	preferences, err := o.readPreferences()
+	if err != nil {
+		logr.FromContextOrDiscard(ctx).V(1).Info(fmt.Sprintf("the preferences have not been applied: %s", err))
+		return nil
+	}
+	o.Preferences = preferences
+
+	preferredValues := []struct {
+		name   string
+		target *string
+		value  string
+	}{
+		{name: "output", target: &o.OutputFormat, value: o.Preferences.OutputFormat},
+		{name: "revision", target: &o.Revision, value: o.Preferences.Revision},
+		{name: "branch", target: &o.Branch, value: o.Preferences.Branch},
+		{name: "deploy-type", target: &o.DeployType, value: o.Preferences.DeployType},
+	}
+
+	for _, preferred := range preferredValues {
+		flag := flags.Lookup(preferred.name)
+		if flag == nil || flag.Changed || len(preferred.value) == 0 || !isBoundTo(flag, preferred.target) {
+			continue
+		}
+
+		// set the value like the user would, so it also satisfies the required flags
+		if err := flags.Set(preferred.name, preferred.value); err != nil {
+			return fmt.Errorf("invalid preference value for %s: %w", preferred.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (o *CLIOptions) readPreferences() (*api.Preferences, error) {
+	locator := cliconfig.NewConfigPathLocator()
+	locator.ExplicitPath = o.MiactlConfig
+	locator.ReadOnly = true
+
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	envOverrides, err := cliconfig.EnvOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := cliconfig.NewConfigReader(config, &cliconfig.ConfigOverrides{Context: o.Context}).WithEnvOverrides(envOverrides)
+	return reader.Preferences()
+}
+
+// isBoundTo return true if the value of flag is saved in target; flags with the same name can hold different
+// values in different commands, like the output flag used for the path of a file
+func isBoundTo(flag *pflag.Flag, target *string) bool {
+	value := reflect.ValueOf(flag.Value)
+	return value.Kind() == reflect.Pointer && value.Pointer() == reflect.ValueOf(target).Pointer()
+}
+
+// ConfirmDestructiveAction ask the user to confirm the action described by message if the preferences
+// require it, ErrActionNotConfirmed is returned if the user does not confirm it
+func (o *CLIOptions) ConfirmDestructiveAction(message string) error {
+	if o.Preferences == nil || o.Preferences.ConfirmDestructiveActions == nil || !*o.Preferences.ConfirmDestructiveActions {
+		return nil
+	}
+
+	var in io.Reader = os.Stdin
+	if o.in != nil {
+		in = o.in
+	}
+	var out io.Writer = os.Stderr
+	if o.errOut != nil {
+		out = o.errOut
+	}
+
+	fmt.Fprintf(out, "%s, do you want to continue? [y/N]: ", message)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return ErrActionNotConfirmed
+	}
+}
+
+func (o *CLIOptions) AddContextPreferencesFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.PreferredOutputFormat, "default-output", "", "the output format used by default by the commands that support it")
+	flags.StringVar(&o.PreferredRevision, "default-revision", "", "the revision used by default by the commands that support it")
+	flags.StringVar(&o.PreferredBranch, "default-branch", "", "the branch used by default by the commands that support it")
+	flags.StringVar(&o.PreferredDeployType, "default-deploy-type", "", "the deploy type used by default by the deploy commands")
+	flags.Bool("wrap-lines", true, "wrap the long lines in the tables printed by the commands")
+	flags.Bool("confirm-destructive-actions", false, "ask for a confirmation before running a destructive action")
+	flags.StringVar(&o.PreferredTokenRefreshWindow, "token-refresh-window", "", "refresh the cached access token when it expires within this duration, like 5m")
+	flags.IntVar(&o.PreferredRetryMaxAttempts, "default-retry-max-attempts", 0, "the number of attempts used by default for the requests failed with a transient error")
+	flags.StringVar(&o.PreferredRetryMaxDelay, "default-retry-max-delay", "", "the maximum wait used by default between two attempts of a request, like 30s")
+	flags.StringVar(&o.PreferredAuditFile, "default-audit-file", "", "the path of the audit log where the requests changing the remote state are saved")
+}
+
+// ContextPreferencesFromFlags return the preferences set with the flags added by AddContextPreferencesFlags,
+// the values of the flags not explicitly set are left empty
+func (o *CLIOptions) ContextPreferencesFromFlags(flags *pflag.FlagSet) (*api.Preferences, error) {
+	preferences := &api.Preferences{
+		OutputFormat: o.PreferredOutputFormat,
+		Revision:     o.PreferredRevision,
+		Branch:       o.PreferredBranch,
+		DeployType:   o.PreferredDeployType,
+	}
+
+	if _, err := cliconfig.ParseTokenRefreshWindow(o.PreferredTokenRefreshWindow); err != nil {
+		return nil, err
+	}
+	preferences.TokenRefreshWindow = o.PreferredTokenRefreshWindow
+
+	var err error
+	if preferences.RetryMaxAttempts, err = cliconfig.ValidateRetryMaxAttempts(o.PreferredRetryMaxAttempts); err != nil {
+		return nil, err
+	}
+	if _, err := cliconfig.ParseRetryMaxDelay(o.PreferredRetryMaxDelay); err != nil {
+		return nil, err
+	}
+	preferences.RetryMaxDelay = o.PreferredRetryMaxDelay
+	preferences.AuditFile = o.PreferredAuditFile
+
+	if preferences.WrapLines, err = changedBoolFlag(flags, "wrap-lines"); err != nil {
+		return nil, err
+	}
+	if preferences.ConfirmDestructiveActions, err = changedBoolFlag(flags, "confirm-destructive-actions"); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// changedBoolFlag return the value of the boolean flag name, or nil if it has not been set
+func changedBoolFlag(flags *pflag.FlagSet, name string) (*bool, error) {
+	if !flags.Changed(name) {
+		return nil, nil
+	}
+
+	value, err := flags.GetBool(name)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
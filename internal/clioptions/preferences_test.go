@@ -0,0 +1,240 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clioptions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+const preferencesConfig = `apiVersion: v1
+kind: Config
+contexts:
+  context1:
+    endpoint: https://example.com
+    preferences:
+      output-format: yaml
+  context2:
+    endpoint: https://example.com
+current-context: context1
+preferences:
+  output-format: json
+  revision: main
+`
+
+func TestApplyPreferences(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(preferencesConfig), 0600))
+
+	testCases := map[string]struct {
+		context          string
+		args             []string
+		expectedOutput   string
+		expectedRevision string
+		expectedBranch   string
+	}{
+		"context preferences win over global ones": {
+			expectedOutput:   "yaml",
+			expectedRevision: "main",
+		},
+		"global preferences": {
+			context:          "context2",
+			expectedOutput:   "json",
+			expectedRevision: "main",
+		},
+		"flags win over preferences": {
+			args:             []string{"--output=table", "--revision=develop", "--branch=feature"},
+			expectedOutput:   "table",
+			expectedRevision: "develop",
+			expectedBranch:   "feature",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			o := NewCLIOptions()
+			o.MiactlConfig = configPath
+			o.Context = testCase.context
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			o.AddOutputFormatFlag(flags, "json")
+			o.AddRevisionFlags(flags)
+			o.AddBranchFlags(flags)
+			// simulate another command registering the shared value with a different default
+			o.OutputFormat = "other"
+			require.NoError(t, flags.Parse(testCase.args))
+
+			require.NoError(t, o.ResetFlagDefaults(flags))
+			require.NoError(t, o.ApplyPreferences(t.Context(), flags))
+			assert.Equal(t, testCase.expectedOutput, o.OutputFormat)
+			assert.Equal(t, testCase.expectedRevision, o.Revision)
+			assert.Equal(t, testCase.expectedBranch, o.Branch)
+		})
+	}
+}
+
+func TestConfirmDestructiveAction(t *testing.T) {
+	trueValue := true
+	falseValue := false
+
+	testCases := map[string]struct {
+		confirm        *bool
+		answer         string
+		expectedPrompt bool
+		expectErr      bool
+	}{
+		"confirmation not required": {
+			answer: "n\n",
+		},
+		"confirmation disabled": {
+			confirm: &falseValue,
+			answer:  "n\n",
+		},
+		"action confirmed": {
+			confirm:        &trueValue,
+			answer:         "yes\n",
+			expectedPrompt: true,
+		},
+		"action not confirmed": {
+			confirm:        &trueValue,
+			answer:         "\n",
+			expectedPrompt: true,
+			expectErr:      true,
+		},
+		"empty input": {
+			confirm:        &trueValue,
+			expectedPrompt: true,
+			expectErr:      true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			o := NewCLIOptions()
+			o.Preferences = &api.Preferences{ConfirmDestructiveActions: testCase.confirm}
+			errOut := bytes.NewBuffer(nil)
+			o.in = strings.NewReader(testCase.answer)
+			o.errOut = errOut
+
+			err := o.ConfirmDestructiveAction("The resource will be deleted")
+			if testCase.expectErr {
+				assert.ErrorIs(t, err, ErrActionNotConfirmed)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if testCase.expectedPrompt {
+				assert.Equal(t, "The resource will be deleted, do you want to continue? [y/N]: ", errOut.String())
+			} else {
+				assert.Empty(t, errOut.String())
+			}
+		})
+	}
+}
+
+func TestContextPreferencesFromFlags(t *testing.T) {
+	o := NewCLIOptions()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	o.AddContextPreferencesFlags(flags)
+	require.NoError(t, flags.Parse([]string{
+		"--default-output=yaml",
+		"--wrap-lines=false",
+		"--token-refresh-window=5m",
+		"--default-retry-max-attempts=6",
+		"--default-retry-max-delay=1m",
+	}))
+
+	preferences, err := o.ContextPreferencesFromFlags(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "yaml", preferences.OutputFormat)
+	require.NotNil(t, preferences.WrapLines)
+	assert.False(t, *preferences.WrapLines)
+	assert.Nil(t, preferences.ConfirmDestructiveActions)
+	assert.Equal(t, "5m", preferences.TokenRefreshWindow)
+	assert.Equal(t, 6, preferences.RetryMaxAttempts)
+	assert.Equal(t, "1m", preferences.RetryMaxDelay)
+
+	require.NoError(t, flags.Parse([]string{"--default-retry-max-delay=later"}))
+	_, err = o.ContextPreferencesFromFlags(flags)
+	assert.ErrorContains(t, err, "invalid retry-max-delay")
+
+	require.NoError(t, flags.Parse([]string{"--default-retry-max-delay=1m", "--default-retry-max-attempts=-1"}))
+	_, err = o.ContextPreferencesFromFlags(flags)
+	assert.ErrorContains(t, err, "invalid retry-max-attempts")
+
+	require.NoError(t, flags.Parse([]string{"--default-retry-max-attempts=0", "--token-refresh-window=soon"}))
+	_, err = o.ContextPreferencesFromFlags(flags)
+	assert.ErrorContains(t, err, "invalid token-refresh-window")
+}
+
+func TestApplyPreferencesSkipOtherValues(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(preferencesConfig), 0600))
+
+	o := NewCLIOptions()
+	o.MiactlConfig = configPath
+
+	// the output flag of the commands saving a file is bound to its path, not to the output format
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	o.AddJWTServiceAccountFlags(flags)
+	require.NoError(t, flags.Parse(nil))
+
+	require.NoError(t, o.ApplyPreferences(t.Context(), flags))
+	assert.Empty(t, o.OutputPath)
+}
+
+func TestApplyPreferencesRequiredFlag(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(preferencesConfig), 0600))
+
+	o := NewCLIOptions()
+	o.MiactlConfig = configPath
+
+	cmd := &cobra.Command{Use: "trigger"}
+	o.AddDeployFlags(cmd.Flags())
+	require.NoError(t, cmd.MarkFlagRequired("revision"))
+	require.NoError(t, cmd.ParseFlags(nil))
+
+	require.NoError(t, o.ApplyPreferences(t.Context(), cmd.Flags()))
+	assert.Equal(t, "main", o.Revision)
+	assert.NoError(t, cmd.ValidateRequiredFlags())
+}
+
+func TestApplyPreferencesInvalidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte("contexts: [invalid"), 0600))
+
+	o := NewCLIOptions()
+	o.MiactlConfig = configPath
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	o.AddOutputFormatFlag(flags, "json")
+	require.NoError(t, flags.Parse(nil))
+
+	require.NoError(t, o.ApplyPreferences(t.Context(), flags))
+	assert.Equal(t, "json", o.OutputFormat)
+	assert.Nil(t, o.Preferences)
+}
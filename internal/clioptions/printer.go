@@ -16,11 +16,24 @@
 package clioptions
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
+	"github.com/mia-platform/miactl/internal/encoding"
 	"github.com/mia-platform/miactl/internal/printer"
 )
 
+const (
+	tableOutput            = "table"
+	wideOutput             = "wide"
+	nameOutput             = "name"
+	jsonPathOutputPrefix   = "jsonpath="
+	goTemplateOutputPrefix = "go-template="
+
+	listOutputFormats = "table, wide, json, yaml, name, jsonpath=TEMPLATE, go-template=TEMPLATE"
+)
+
 type printerOptions struct {
 	noWrapLines bool
 }
@@ -32,13 +45,45 @@ func DisableWrapLines(noWrap bool) PrinterOption {
 	}
 }
 
-func (o *CLIOptions) Printer(options ...PrinterOption) printer.IPrinter {
+// Printer return the ListPrinter for the output format set with the output flag: table, wide, json, yaml,
+// name, jsonpath=TEMPLATE or go-template=TEMPLATE
+func (o *CLIOptions) Printer(options ...PrinterOption) (printer.ListPrinter, error) {
+	switch format := o.OutputFormat; {
+	case format == "" || format == tableOutput:
+		return o.tablePrinter(false, options...), nil
+	case format == wideOutput:
+		return o.tablePrinter(true, options...), nil
+	case format == encoding.JSON:
+		return printer.NewJSONPrinter(os.Stdout), nil
+	case format == encoding.YAML:
+		return printer.NewYAMLPrinter(os.Stdout), nil
+	case format == nameOutput:
+		return printer.NewNamePrinter(os.Stdout), nil
+	case strings.HasPrefix(format, jsonPathOutputPrefix):
+		return printer.NewJSONPathPrinter(strings.TrimPrefix(format, jsonPathOutputPrefix), os.Stdout)
+	case strings.HasPrefix(format, goTemplateOutputPrefix):
+		return printer.NewTemplatePrinter(strings.TrimPrefix(format, goTemplateOutputPrefix), os.Stdout)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, allowed values: %s", format, listOutputFormats)
+	}
+}
+
+// TablePrinter return a printer for the commands that print their data only as a table
+func (o *CLIOptions) TablePrinter(options ...PrinterOption) printer.IPrinter {
+	return o.tablePrinter(false, options...)
+}
+
+func (o *CLIOptions) tablePrinter(wide bool, options ...PrinterOption) *printer.TablePrinter {
 	opts := &printerOptions{}
+	if o.Preferences != nil && o.Preferences.WrapLines != nil {
+		opts.noWrapLines = !*o.Preferences.WrapLines
+	}
 	for _, option := range options {
 		option(opts)
 	}
 
 	return printer.NewTablePrinter(printer.TablePrinterOptions{
 		WrapLinesDisabled: opts.noWrapLines,
+		Wide:              wide,
 	}, os.Stdout)
 }
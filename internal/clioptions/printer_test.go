@@ -0,0 +1,100 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clioptions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/printer"
+)
+
+func TestPrinter(t *testing.T) {
+	testCases := map[string]struct {
+		outputFormat string
+		expected     printer.ListPrinter
+		expectedErr  string
+	}{
+		"default is table": {
+			expected: &printer.TablePrinter{},
+		},
+		"table": {
+			outputFormat: "table",
+			expected:     &printer.TablePrinter{},
+		},
+		"wide": {
+			outputFormat: "wide",
+			expected:     &printer.TablePrinter{},
+		},
+		"json": {
+			outputFormat: "json",
+			expected:     printer.NewJSONPrinter(nil),
+		},
+		"yaml": {
+			outputFormat: "yaml",
+			expected:     printer.NewYAMLPrinter(nil),
+		},
+		"name": {
+			outputFormat: "name",
+			expected:     printer.NewNamePrinter(nil),
+		},
+		"jsonpath": {
+			outputFormat: "jsonpath={.name}",
+			expected:     mustPrinter(printer.NewJSONPathPrinter("", nil)),
+		},
+		"go-template": {
+			outputFormat: "go-template={{ .name }}",
+			expected:     mustPrinter(printer.NewTemplatePrinter("", nil)),
+		},
+		"invalid jsonpath": {
+			outputFormat: "jsonpath={range .items}",
+			expectedErr:  "invalid jsonpath template",
+		},
+		"invalid go-template": {
+			outputFormat: "go-template={{ .name",
+			expectedErr:  "invalid go-template",
+		},
+		"unsupported format": {
+			outputFormat: "csv",
+			expectedErr:  `unsupported output format "csv"`,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			o := NewCLIOptions()
+			o.OutputFormat = testCase.outputFormat
+
+			p, err := o.Printer()
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, testCase.expected, p)
+		})
+	}
+}
+
+func mustPrinter(p printer.ListPrinter, err error) printer.ListPrinter {
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
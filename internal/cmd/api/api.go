@@ -0,0 +1,299 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/jsonfilter"
+)
+
+// NewCommand return the command for sending authenticated requests to any api of the Console
+func NewCommand(o *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api PATH",
+		Short: "Make an authenticated request to the Console APIs",
+		Long: `Make an authenticated request to the Console APIs and print the response.
+
+PATH is the path of the api relative to the Console endpoint, and it can contain
+query params. The request is sent with the credentials and the connection settings
+of the current context.
+
+The method is GET by default, or POST when a body is set with the --raw-field or
+--input flags. The --raw-field values become query params for GET requests.
+
+The json responses are pretty printed, the --jq flag can be used for selecting
+values from them with a subset of the jq language: field and index access like
+.items[0].name, the iteration with .[], the pipe operator, the length and keys
+functions and select with the == and != comparisons.`,
+		Example: `# list the projects of the company
+miactl api "/api/backend/projects/?tenantIds=my-company"
+
+# print the names of all the projects
+miactl api /api/backend/projects/ --paginate --jq '.[].name'
+
+# send a custom body
+miactl api /api/tenants/my-company/groups -X POST --input group.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := o.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+			apiClient, err := client.APIClientForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+
+			return runAPI(cmd.Context(), apiClient, args[0], o, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	o.AddConnectionFlags(flags)
+	o.AddContextFlags(flags)
+	o.AddCompanyFlags(flags)
+	o.AddProjectFlags(flags)
+	o.AddAPIFlags(flags)
+	return cmd
+}
+
+// runAPI send the request described by path and the api flags and write the response to out
+func runAPI(ctx context.Context, apiClient *client.APIClient, path string, o *clioptions.CLIOptions, in io.Reader, out io.Writer) error {
+	var filter *jsonfilter.Filter
+	if len(o.APIFilter) > 0 {
+		var err error
+		if filter, err = jsonfilter.Parse(o.APIFilter); err != nil {
+			return err
+		}
+	}
+
+	body, err := readInput(o.InputFilePath, in)
+	if err != nil {
+		return err
+	}
+
+	request, err := buildRequest(apiClient, path, o, body)
+	if err != nil {
+		return err
+	}
+
+	pages, err := fetchPages(ctx, request, o.APIPaginate)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		if err := printBody(out, page, filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readInput return the content of path, reading it from in if path is -
+func readInput(path string, in io.Reader) ([]byte, error) {
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the body from the standard input: %w", err)
+		}
+		return data, nil
+	default:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the body: %w", err)
+		}
+		return data, nil
+	}
+}
+
+func buildRequest(apiClient *client.APIClient, path string, o *clioptions.CLIOptions, body []byte) (*client.Request, error) {
+	parsedPath, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	if len(parsedPath.Scheme) > 0 || len(parsedPath.Host) > 0 {
+		return nil, fmt.Errorf("invalid path %q: it must be relative to the Console endpoint", path)
+	}
+	if !strings.HasPrefix(parsedPath.Path, "/") {
+		parsedPath.Path = "/" + parsedPath.Path
+	}
+
+	fields, err := parseFields(o.APIFields)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(o.APIMethod)
+	if len(method) == 0 {
+		method = http.MethodGet
+		if len(fields) > 0 || body != nil {
+			method = http.MethodPost
+		}
+	}
+
+	if o.APIPaginate && method != http.MethodGet {
+		return nil, fmt.Errorf("--paginate can be used only for GET requests, not %s", method)
+	}
+
+	request := apiClient.Get().SetVerb(method).APIPath(parsedPath.EscapedPath())
+	for key, values := range parsedPath.Query() {
+		request.SetParam(key, values...)
+	}
+
+	// the fields are sent as query params when they cannot be the body of the request
+	if method == http.MethodGet || body != nil {
+		for _, field := range fields {
+			request.SetParam(field.key, field.value)
+		}
+	} else if len(fields) > 0 {
+		object := make(map[string]string, len(fields))
+		for _, field := range fields {
+			object[field.key] = field.value
+		}
+		if body, err = json.Marshal(object); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(body) > 0 {
+		request.Body(body)
+	}
+
+	for _, header := range o.APIHeaders {
+		key, value, found := strings.Cut(header, ":")
+		if !found || len(strings.TrimSpace(key)) == 0 {
+			return nil, fmt.Errorf("invalid header %q: it must be in the \"Key: value\" format", header)
+		}
+		request.SetHeader(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	return request, request.Error()
+}
+
+type field struct {
+	key   string
+	value string
+}
+
+// parseFields split the key=value pairs keeping the order in which they have been set
+func parseFields(rawFields []string) ([]field, error) {
+	fields := make([]field, 0, len(rawFields))
+	for _, rawField := range rawFields {
+		key, value, found := strings.Cut(rawField, "=")
+		if !found || len(key) == 0 {
+			return nil, fmt.Errorf("invalid field %q: it must be in the key=value format", rawField)
+		}
+		fields = append(fields, field{key: key, value: value})
+	}
+	return fields, nil
+}
+
+// fetchPages return the body of the response, or of every page if paginate is true; the pages containing
+// json arrays are merged in a single one
+func fetchPages(ctx context.Context, request *client.Request, paginate bool) ([][]byte, error) {
+	if !paginate {
+		resp, err := request.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error executing request: %w", err)
+		}
+		if err := resp.Error(); err != nil {
+			return nil, err
+		}
+		return [][]byte{resp.Body()}, nil
+	}
+
+	pages := make([][]byte, 0)
+	pager := client.NewPager(request, client.PageOptions{All: true})
+	err := pager.EachPage(ctx, func(resp *client.Response) error {
+		pages = append(pages, resp.Body())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]json.RawMessage, 0)
+	for _, page := range pages {
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(page, &pageItems); err != nil {
+			return pages, nil
+		}
+		items = append(items, pageItems...)
+	}
+
+	merged, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{merged}, nil
+}
+
+// printBody write body to out pretty printing it if is json, when filter is set only the values
+// selected by it are written, printing the strings without quotes
+func printBody(out io.Writer, body []byte, filter *jsonfilter.Filter) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if filter == nil {
+		buffer := new(bytes.Buffer)
+		if err := json.Indent(buffer, body, "", "  "); err != nil {
+			_, err := out.Write(body)
+			return err
+		}
+		buffer.WriteByte('\n')
+		_, err := buffer.WriteTo(out)
+		return err
+	}
+
+	values, err := filter.ApplyJSON(body)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	for _, value := range values {
+		if text, ok := value.(string); ok {
+			if _, err := fmt.Fprintln(out, text); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("cannot print the filtered value: %w", err)
+		}
+	}
+	return nil
+}
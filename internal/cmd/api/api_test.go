@@ -0,0 +1,248 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func TestNewCommandFlags(t *testing.T) {
+	cmd := NewCommand(clioptions.NewCLIOptions())
+	for _, name := range []string{"context", "auth-name", "endpoint", "certificate-authority", "company-id", "project-id", "method", "header"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), "missing flag %s", name)
+	}
+}
+
+func TestRunAPI(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"name":"from-file"}`), 0600))
+
+	testCases := map[string]struct {
+		path           string
+		options        *clioptions.CLIOptions
+		stdin          string
+		expectedMethod string
+		expectedQuery  string
+		expectedBody   string
+		expectedHeader http.Header
+		expectedOutput string
+		expectedErr    string
+	}{
+		"get with query params": {
+			path:           "/api/projects/?search=name",
+			options:        &clioptions.CLIOptions{APIMethod: "get", APIFields: []string{"tenantIds=company"}},
+			expectedMethod: http.MethodGet,
+			expectedQuery:  "search=name&tenantIds=company",
+			expectedOutput: "{\n  \"name\": \"project\",\n  \"tags\": [\n    \"a\"\n  ]\n}\n",
+		},
+		"path without leading slash": {
+			path:           "api/projects/",
+			options:        &clioptions.CLIOptions{},
+			expectedMethod: http.MethodGet,
+			expectedOutput: "{\n  \"name\": \"project\",\n  \"tags\": [\n    \"a\"\n  ]\n}\n",
+		},
+		"post fields": {
+			path:           "/api/projects/",
+			options:        &clioptions.CLIOptions{APIFields: []string{"name=new", "description=a=b"}},
+			expectedMethod: http.MethodPost,
+			expectedBody:   `{"description":"a=b","name":"new"}`,
+			expectedOutput: "{\n  \"name\": \"project\",\n  \"tags\": [\n    \"a\"\n  ]\n}\n",
+		},
+		"patch from file with fields as query": {
+			path: "/api/projects/",
+			options: &clioptions.CLIOptions{
+				APIMethod:     "patch",
+				InputFilePath: inputFile,
+				APIFields:     []string{"dryRun=true"},
+				APIHeaders:    []string{"X-Custom: value: with colon"},
+			},
+			expectedMethod: http.MethodPatch,
+			expectedQuery:  "dryRun=true",
+			expectedBody:   `{"name":"from-file"}`,
+			expectedHeader: http.Header{"X-Custom": []string{"value: with colon"}},
+			expectedOutput: "{\n  \"name\": \"project\",\n  \"tags\": [\n    \"a\"\n  ]\n}\n",
+		},
+		"body from stdin": {
+			path:           "/api/projects/",
+			options:        &clioptions.CLIOptions{InputFilePath: "-"},
+			stdin:          `{"name":"from-stdin"}`,
+			expectedMethod: http.MethodPost,
+			expectedBody:   `{"name":"from-stdin"}`,
+			expectedOutput: "{\n  \"name\": \"project\",\n  \"tags\": [\n    \"a\"\n  ]\n}\n",
+		},
+		"unsupported filter": {
+			path:        "/api/projects/",
+			options:     &clioptions.CLIOptions{APIFilter: ".name, .tags"},
+			expectedErr: `invalid filter ".name, .tags": unexpected character ',' at position 5`,
+		},
+		"filter output": {
+			path:           "/api/projects/",
+			options:        &clioptions.CLIOptions{APIFilter: ".tags"},
+			expectedMethod: http.MethodGet,
+			expectedOutput: "[\n  \"a\"\n]\n",
+		},
+		"filter raw string": {
+			path:           "/api/projects/",
+			options:        &clioptions.CLIOptions{APIFilter: ".name"},
+			expectedMethod: http.MethodGet,
+			expectedOutput: "project\n",
+		},
+		"not json response": {
+			path:           "/api/plain",
+			options:        &clioptions.CLIOptions{},
+			expectedMethod: http.MethodGet,
+			expectedOutput: "plain text",
+		},
+		"empty response": {
+			path:           "/api/empty",
+			options:        &clioptions.CLIOptions{APIMethod: http.MethodDelete},
+			expectedMethod: http.MethodDelete,
+		},
+		"error response": {
+			path:           "/api/missing",
+			options:        &clioptions.CLIOptions{},
+			expectedMethod: http.MethodGet,
+			expectedErr:    "project not found",
+		},
+		"absolute url": {
+			path:        "https://example.com/api/projects/",
+			options:     &clioptions.CLIOptions{},
+			expectedErr: `invalid path "https://example.com/api/projects/": it must be relative to the Console endpoint`,
+		},
+		"invalid field": {
+			path:        "/api/projects/",
+			options:     &clioptions.CLIOptions{APIFields: []string{"name"}},
+			expectedErr: `invalid field "name": it must be in the key=value format`,
+		},
+		"invalid header": {
+			path:        "/api/projects/",
+			options:     &clioptions.CLIOptions{APIHeaders: []string{"X-Custom"}},
+			expectedErr: `invalid header "X-Custom": it must be in the "Key: value" format`,
+		},
+		"paginate non get request": {
+			path:        "/api/projects/",
+			options:     &clioptions.CLIOptions{APIFields: []string{"name=new"}, APIPaginate: true},
+			expectedErr: "--paginate can be used only for GET requests, not POST",
+		},
+		"missing input file": {
+			path:        "/api/projects/",
+			options:     &clioptions.CLIOptions{InputFilePath: filepath.Join(t.TempDir(), "missing.json")},
+			expectedErr: "cannot read the body",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				assert.Equal(t, testCase.expectedMethod, r.Method)
+				assert.Equal(t, testCase.expectedQuery, r.URL.RawQuery)
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedBody, string(body))
+				for key, values := range testCase.expectedHeader {
+					assert.Equal(t, values, r.Header.Values(key))
+				}
+
+				switch r.URL.Path {
+				case "/api/projects/":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"name":"project","tags":["a"]}`))
+				case "/api/plain":
+					w.Header().Set("Content-Type", "text/plain")
+					w.Write([]byte("plain text"))
+				case "/api/empty":
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"statusCode":404,"error":"Not Found","message":"project not found"}`))
+				}
+			}))
+			defer server.Close()
+
+			apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL})
+			require.NoError(t, err)
+
+			output := &strings.Builder{}
+			err = runAPI(t.Context(), apiClient, testCase.path, testCase.options, strings.NewReader(testCase.stdin), output)
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, requests)
+			assert.Equal(t, testCase.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestRunAPIPaginate(t *testing.T) {
+	testCases := map[string]struct {
+		pages          []string
+		filter         string
+		expectedOutput string
+	}{
+		"merge array pages": {
+			pages:          []string{`[{"id":1},{"id":2}]`, `[{"id":3}]`},
+			filter:         ".[].id",
+			expectedOutput: "1\n2\n3\n",
+		},
+		"single object page": {
+			pages:          []string{`{"id":1}`},
+			expectedOutput: "{\n  \"id\": 1\n}\n",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			requestedPages := make([]string, 0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestedPages = append(requestedPages, r.URL.Query().Get("page"))
+				assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+
+				index := len(requestedPages) - 1
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(testCase.pages[index]))
+			}))
+			defer server.Close()
+
+			apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL})
+			require.NoError(t, err)
+
+			options := &clioptions.CLIOptions{APIPaginate: true, APIFilter: testCase.filter}
+			output := &strings.Builder{}
+			err = runAPI(t.Context(), apiClient, "/api/items", options, strings.NewReader(""), output)
+			require.NoError(t, err)
+
+			assert.Len(t, requestedPages, len(testCase.pages))
+			assert.Equal(t, testCase.expectedOutput, output.String())
+		})
+	}
+}
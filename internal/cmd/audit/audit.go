@@ -0,0 +1,149 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	auditlog "github.com/mia-platform/miactl/internal/audit"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/printer"
+)
+
+const dateLayout = "2006-01-02"
+
+// NewCommand return the command for reading the local audit log
+func NewCommand(o *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Read the local audit log",
+		Long: `Read the local audit log.
+
+When an audit log is configured with the audit-file preference, the --audit-file flag or
+the MIACTL_AUDIT_FILE environment variable, every request that can change the state of
+the Console is appended to it, with the command, the context and the result.`,
+	}
+
+	cmd.AddCommand(ListCmd(o))
+	return cmd
+}
+
+// ListCmd return the command for listing the entries of the audit log
+func ListCmd(o *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the requests saved in the audit log",
+		Long: `List the requests saved in the audit log, from the oldest to the most recent.
+
+The --since and --until flags accept a duration before the current time, like 24h,
+a date, like 2026-01-31, or a timestamp in RFC 3339 format.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filter, err := filterFromOptions(o, time.Now())
+			if err != nil {
+				return err
+			}
+			printer, err := o.Printer()
+			if err != nil {
+				return err
+			}
+			return listEntries(o.AuditFilePath(), filter, printer)
+		},
+	}
+
+	o.AddAuditListFlags(cmd.Flags())
+	return cmd
+}
+
+func filterFromOptions(o *clioptions.CLIOptions, now time.Time) (auditlog.Filter, error) {
+	filter := auditlog.Filter{
+		ProjectID: o.ProjectID,
+		Method:    o.APIMethod,
+	}
+
+	var err error
+	if filter.Since, err = parseTime(o.AuditSince, now); err != nil {
+		return filter, fmt.Errorf("invalid --since value: %w", err)
+	}
+	if filter.Until, err = parseTime(o.AuditUntil, now); err != nil {
+		return filter, fmt.Errorf("invalid --until value: %w", err)
+	}
+	return filter, nil
+}
+
+// parseTime return the time described by value, that can be a duration before now, a date or a timestamp
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	if date, err := time.ParseInLocation(dateLayout, value, now.Location()); err == nil {
+		return date, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+
+	return time.Time{}, errors.New("it must be a duration, a date or an RFC 3339 timestamp")
+}
+
+func listEntries(path string, filter auditlog.Filter, p printer.ListPrinter) error {
+	if len(path) == 0 {
+		return errors.New("no audit log configured, set one with the audit-file preference, the --audit-file flag or the MIACTL_AUDIT_FILE environment variable")
+	}
+
+	entries, err := auditlog.Read(path, filter)
+	if err != nil {
+		return err
+	}
+
+	list := printer.NewList(entries, entryName, printer.Table[*auditlog.Entry]{
+		Headers:     []string{"Time", "User", "Context", "Project", "Method", "Path", "Status"},
+		WideHeaders: []string{"Host", "Command"},
+		Row:         rowForEntry,
+	})
+	return p.PrintList(list)
+}
+
+func entryName(entry *auditlog.Entry) string {
+	return entry.Timestamp.Format(time.RFC3339Nano)
+}
+
+func rowForEntry(entry *auditlog.Entry) []string {
+	status := entry.Error
+	if entry.StatusCode > 0 {
+		status = strconv.Itoa(entry.StatusCode)
+	}
+	return []string{
+		entry.Timestamp.Local().Format(time.DateTime),
+		entry.User,
+		entry.Context,
+		entry.ProjectID,
+		entry.Method,
+		entry.Path,
+		status,
+		entry.Host,
+		entry.Command,
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	auditlog "github.com/mia-platform/miactl/internal/audit"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/printer"
+)
+
+func TestFilterFromOptions(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		options     *clioptions.CLIOptions
+		expected    auditlog.Filter
+		expectedErr string
+	}{
+		"empty options": {
+			options: &clioptions.CLIOptions{},
+		},
+		"duration and date": {
+			options:  &clioptions.CLIOptions{AuditSince: "24h", AuditUntil: "2026-01-10", ProjectID: "project", APIMethod: "post"},
+			expected: auditlog.Filter{Since: now.Add(-24 * time.Hour), Until: time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC), ProjectID: "project", Method: "post"},
+		},
+		"timestamp": {
+			options:  &clioptions.CLIOptions{AuditSince: "2026-01-09T08:30:00Z"},
+			expected: auditlog.Filter{Since: time.Date(2026, time.January, 9, 8, 30, 0, 0, time.UTC)},
+		},
+		"invalid since": {
+			options:     &clioptions.CLIOptions{AuditSince: "yesterday"},
+			expectedErr: "invalid --since value: it must be a duration, a date or an RFC 3339 timestamp",
+		},
+		"invalid until": {
+			options:     &clioptions.CLIOptions{AuditUntil: "10/01/2026"},
+			expectedErr: "invalid --until value",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			filter, err := filterFromOptions(testCase.options, now)
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, filter)
+		})
+	}
+}
+
+func TestListEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	timestamp := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, auditlog.Append(path, &auditlog.Entry{
+		Timestamp:  timestamp,
+		User:       "user",
+		Context:    "context",
+		ProjectID:  "project",
+		Method:     "POST",
+		Path:       "/api/deploy/project/trigger",
+		StatusCode: 200,
+	}))
+	require.NoError(t, auditlog.Append(path, &auditlog.Entry{
+		Timestamp: timestamp.Add(time.Minute),
+		User:      "user",
+		Context:   "context",
+		ProjectID: "other",
+		Method:    "DELETE",
+		Path:      "/api/projects/other",
+		Error:     "connection refused",
+	}))
+
+	testCases := map[string]struct {
+		path        string
+		filter      auditlog.Filter
+		newPrinter  func(io.Writer) printer.ListPrinter
+		expected    []string
+		expectedErr string
+	}{
+		"table": {
+			path:       path,
+			newPrinter: newTablePrinter,
+			expected:   []string{"POST", "/api/deploy/project/trigger", "200", "DELETE", "connection refused"},
+		},
+		"filtered json": {
+			path:       path,
+			filter:     auditlog.Filter{ProjectID: "project"},
+			newPrinter: printer.NewJSONPrinter,
+			expected:   []string{`"projectId": "project"`, `"statusCode": 200`},
+		},
+		"yaml": {
+			path:       path,
+			filter:     auditlog.Filter{Method: "delete"},
+			newPrinter: printer.NewYAMLPrinter,
+			expected:   []string{"method: DELETE", "error: connection refused"},
+		},
+		"missing audit file": {
+			newPrinter:  newTablePrinter,
+			expectedErr: "no audit log configured",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			output := &strings.Builder{}
+			err := listEntries(testCase.path, testCase.filter, testCase.newPrinter(output))
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			for _, expected := range testCase.expected {
+				assert.Contains(t, output.String(), expected)
+			}
+			if testCase.filter.ProjectID != "" {
+				assert.NotContains(t, output.String(), "other")
+			}
+		})
+	}
+}
+
+func newTablePrinter(w io.Writer) printer.ListPrinter {
+	return printer.NewTablePrinter(printer.TablePrinterOptions{}, w)
+}
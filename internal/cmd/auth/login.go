@@ -0,0 +1,102 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+// LoginCmd return a new cobra command for logging in the Console of the current context
+func LoginCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Login to the Console of the current context",
+		Long: `Login to the Console of the current context.
+
+The authentication flow configured for the context is always performed, even if a valid
+access token is already cached, and the new token will replace the cached one.
+When no service account is configured the user login is performed with the mode selected
+with the --login-mode flag.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			restConfig, err := options.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			return login(cmd.Context(), cmd.OutOrStdout(), restConfig)
+		},
+	}
+
+	// add cmd flags
+	flags := cmd.Flags()
+	options.AddConnectionFlags(flags)
+	options.AddContextFlags(flags)
+
+	return cmd
+}
+
+// login run the authentication flow of config ignoring the cached token
+func login(ctx context.Context, out io.Writer, config *client.Config) error {
+	if len(config.BearerToken) > 0 {
+		return errors.New("an access token is already set in the environment, no login is needed")
+	}
+
+	config.AuthCacheReadWriter = &forceLoginCache{AuthCacheReadWriter: config.AuthCacheReadWriter}
+	token, err := client.TokenForConfig(ctx, config)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	if token.Expiry.IsZero() {
+		fmt.Fprintf(out, "Logged in to %s.\n", config.Host)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Logged in to %s, the access token will expire at %s.\n", config.Host, token.Expiry.Local().Format(timeFormat))
+	return nil
+}
+
+// forceLoginCache hide the cached token to the authentication flow, forcing it to retrieve a new one
+type forceLoginCache struct {
+	client.AuthCacheReadWriter
+
+	written bool
+}
+
+func (c *forceLoginCache) ReadJWTToken() *oauth2.Token {
+	if !c.written || c.AuthCacheReadWriter == nil {
+		return &oauth2.Token{}
+	}
+	return c.AuthCacheReadWriter.ReadJWTToken()
+}
+
+func (c *forceLoginCache) WriteJWTToken(token *oauth2.Token) {
+	if c.AuthCacheReadWriter == nil {
+		return
+	}
+	c.written = true
+	c.AuthCacheReadWriter.WriteJWTToken(token)
+}
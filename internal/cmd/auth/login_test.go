@@ -0,0 +1,88 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+type testAuthCache struct {
+	token *oauth2.Token
+}
+
+func (c *testAuthCache) ReadJWTToken() *oauth2.Token       { return c.token }
+func (c *testAuthCache) WriteJWTToken(token *oauth2.Token) { c.token = token }
+
+func TestForceLoginCache(t *testing.T) {
+	cache := &forceLoginCache{AuthCacheReadWriter: &testAuthCache{token: &oauth2.Token{AccessToken: "cached"}}}
+	assert.Empty(t, cache.ReadJWTToken().AccessToken)
+
+	cache.WriteJWTToken(&oauth2.Token{AccessToken: "new"})
+	assert.Equal(t, "new", cache.ReadJWTToken().AccessToken)
+
+	emptyCache := &forceLoginCache{}
+	emptyCache.WriteJWTToken(&oauth2.Token{AccessToken: "new"})
+	assert.Empty(t, emptyCache.ReadJWTToken().AccessToken)
+}
+
+func TestLoginWithBearerToken(t *testing.T) {
+	err := login(t.Context(), new(bytes.Buffer), &client.Config{Host: "https://example.com", BearerToken: "token"})
+	assert.ErrorContains(t, err, "no login is needed")
+}
+
+// testAuthProvider return the cached token if valid, or a new one saving it in the cache
+type testAuthProvider struct {
+	cache client.AuthCacheReadWriter
+}
+
+func (ap *testAuthProvider) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		token := ap.cache.ReadJWTToken()
+		if !token.Valid() {
+			token = &oauth2.Token{AccessToken: "new", Expiry: time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)}
+			ap.cache.WriteJWTToken(token)
+		}
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+		return rt.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return fn(req) }
+
+func TestLogin(t *testing.T) {
+	require.NoError(t, client.RegisterAuthProvider(func(_ *client.Config, cache client.AuthCacheReadWriter, _ client.AuthConfig) client.AuthProvider {
+		return &testAuthProvider{cache: cache}
+	}))
+
+	cache := &testAuthCache{token: &oauth2.Token{AccessToken: "cached", Expiry: time.Now().Add(time.Hour)}}
+	out := new(bytes.Buffer)
+	err := login(t.Context(), out, &client.Config{Host: "https://example.com", AuthCacheReadWriter: cache})
+	require.NoError(t, err)
+	assert.Equal(t, "new", cache.token.AccessToken)
+	assert.Contains(t, out.String(), "Logged in to https://example.com")
+}
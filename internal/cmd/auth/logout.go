@@ -0,0 +1,91 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+// tokenDeleter is implemented by the auth caches that can remove their token
+type tokenDeleter interface {
+	DeleteJWTToken() (bool, error)
+}
+
+// LogoutCmd return a new cobra command for removing the cached credentials
+func LogoutCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the cached credentials of the current context",
+		Long: `Remove the cached credentials of the current context.
+
+The next command that needs to access the Console will perform again the authentication flow.
+Use the --all flag for removing the cached credentials of every context.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if options.LogoutAll {
+				if err := cliconfig.DeleteAllJWTTokens(); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "Removed the cached credentials of every context.")
+				return nil
+			}
+
+			restConfig, err := options.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			return logout(cmd.OutOrStdout(), restConfig)
+		},
+	}
+
+	// add cmd flags
+	flags := cmd.Flags()
+	options.AddConnectionFlags(flags)
+	options.AddContextFlags(flags)
+	options.AddLogoutFlags(flags)
+
+	return cmd
+}
+
+// logout remove the cached token used by config
+func logout(out io.Writer, config *client.Config) error {
+	cache, ok := config.AuthCacheReadWriter.(tokenDeleter)
+	if !ok {
+		return errors.New("the credentials cache of the current context cannot be removed")
+	}
+
+	deleted, err := cache.DeleteJWTToken()
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		fmt.Fprintf(out, "No cached credentials found for %s.\n", config.Host)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Logged out from %s.\n", config.Host)
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+func TestLogout(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	cache := cliconfig.NewAuthReadWriter(nil, &api.ContextConfig{Endpoint: "https://example.com"}, &api.AuthConfig{})
+	cache.WriteJWTToken(&oauth2.Token{AccessToken: "token"})
+	config := &client.Config{Host: "https://example.com", AuthCacheReadWriter: cache}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, logout(out, config))
+	assert.Equal(t, "Logged out from https://example.com.\n", out.String())
+	assert.Empty(t, cache.ReadJWTToken().AccessToken)
+
+	out.Reset()
+	require.NoError(t, logout(out, config))
+	assert.Equal(t, "No cached credentials found for https://example.com.\n", out.String())
+
+	assert.Error(t, logout(out, &client.Config{Host: "https://example.com"}))
+}
@@ -0,0 +1,107 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/encoding"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+const (
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1"
+	execCredentialKind       = "ExecCredential"
+	timeFormat               = time.RFC3339
+)
+
+// TokenCmd return a new cobra command for printing a valid access token
+func TokenCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print a valid access token for the current context",
+		Long: `Print a valid access token for the current context.
+
+The cached token is refreshed, or a new login is performed, if it is expired. The token can
+be used for calling the Console APIs with other tools, for example:
+
+  curl -H "Authorization: Bearer $(miactl auth token)" https://console.cloud.mia-platform.eu/api/userinfo
+
+With the --exec-credential flag the token is printed in the ExecCredential format used by
+the kubectl exec credential plugins.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			restConfig, err := options.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			return printToken(cmd.Context(), cmd.OutOrStdout(), restConfig, options.ExecCredentialOutput)
+		},
+	}
+
+	// add cmd flags
+	options.AddAuthTokenFlags(cmd.Flags())
+
+	return cmd
+}
+
+// printToken print on out a valid access token for config
+func printToken(ctx context.Context, out io.Writer, config *client.Config, execCredential bool) error {
+	token, err := client.TokenForConfig(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	if !execCredential {
+		fmt.Fprintln(out, token.AccessToken)
+		return nil
+	}
+
+	data, err := encoding.MarshalData(execCredentialForToken(token), encoding.JSON, encoding.MarshalOptions{Indent: true})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+// execCredentialForToken return the ExecCredential document containing token
+func execCredentialForToken(token *oauth2.Token) *resources.ExecCredential {
+	credential := &resources.ExecCredential{
+		APIVersion: execCredentialAPIVersion,
+		Kind:       execCredentialKind,
+		Status: &resources.ExecCredentialStatus{
+			Token: token.AccessToken,
+		},
+	}
+
+	if !token.Expiry.IsZero() {
+		expiry := token.Expiry.UTC()
+		credential.Status.ExpirationTimestamp = &expiry
+	}
+
+	return credential
+}
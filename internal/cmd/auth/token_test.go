@@ -0,0 +1,69 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+func TestPrintToken(t *testing.T) {
+	testCases := map[string]struct {
+		execCredential bool
+		expectedOutput string
+	}{
+		"raw token": {
+			expectedOutput: "token\n",
+		},
+		"exec credential": {
+			execCredential: true,
+			expectedOutput: `{
+  "apiVersion": "client.authentication.k8s.io/v1",
+  "kind": "ExecCredential",
+  "status": {
+    "token": "token"
+  }
+}
+`,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			config := &client.Config{Host: "https://example.com", BearerToken: "token"}
+			require.NoError(t, printToken(t.Context(), out, config, testCase.execCredential))
+			assert.Equal(t, testCase.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestExecCredentialForToken(t *testing.T) {
+	expiry := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	credential := execCredentialForToken(&oauth2.Token{AccessToken: "token", Expiry: expiry})
+	assert.Equal(t, execCredentialAPIVersion, credential.APIVersion)
+	assert.Equal(t, execCredentialKind, credential.Kind)
+	assert.Equal(t, "token", credential.Status.Token)
+	require.NotNil(t, credential.Status.ExpirationTimestamp)
+	assert.Equal(t, time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC), *credential.Status.ExpirationTimestamp)
+}
@@ -0,0 +1,198 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/encoding"
+	"github.com/mia-platform/miactl/internal/iam"
+	"github.com/mia-platform/miactl/internal/jws"
+	"github.com/mia-platform/miactl/internal/printer"
+	"github.com/mia-platform/miactl/internal/resources"
+	"github.com/mia-platform/miactl/internal/util"
+)
+
+const (
+	whoamiOutputTable = "table"
+	unknownValue      = "unknown"
+)
+
+// identity contains the information about who is using the access token of a context
+type identity struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	CompanyID    string     `json:"companyId,omitempty"`
+	CompanyRoles []string   `json:"companyRoles"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Context      string     `json:"context"`
+	Credential   string     `json:"credential"`
+}
+
+// WhoamiCmd return a new cobra command for showing the identity used by the current context
+func WhoamiCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the identity used by the current context",
+		Long: `Show the identity used by the current context.
+
+The access token of the context is decoded and its subject is searched between the identities
+of the company, for showing the name of the user or service account, its company roles, when
+the token will expire and which context and credential have supplied it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			restConfig, err := options.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			identity, err := whoami(cmd.Context(), restConfig)
+			if err != nil {
+				return err
+			}
+
+			return printIdentity(cmd.OutOrStdout(), options.TablePrinter(), identity, options.OutputFormat)
+		},
+	}
+
+	// add cmd flags
+	options.AddAuthWhoamiFlags(cmd.Flags())
+
+	return cmd
+}
+
+// whoami return the identity of the access token used by config
+func whoami(ctx context.Context, config *client.Config) (*identity, error) {
+	token, err := client.TokenForConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := jws.Decode(token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode the access token: %w", err)
+	}
+
+	result := &identity{
+		ID:           claims.Sub,
+		Name:         unknownValue,
+		Type:         unknownValue,
+		CompanyID:    config.CompanyID,
+		CompanyRoles: []string{},
+		Context:      config.ContextName,
+		Credential:   credentialDescription(config),
+	}
+
+	switch {
+	case !token.Expiry.IsZero():
+		expiry := token.Expiry
+		result.ExpiresAt = &expiry
+	case claims.Exp > 0:
+		expiry := time.Unix(claims.Exp, 0)
+		result.ExpiresAt = &expiry
+	}
+
+	if len(config.CompanyID) == 0 || len(claims.Sub) == 0 {
+		return result, nil
+	}
+
+	// reuse the obtained token for resolving the identity
+	identityConfig := *config
+	identityConfig.BearerToken = token.AccessToken
+	apiClient, err := client.APIClientForConfig(&identityConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := iam.ListAllIAMEntities(ctx, apiClient, config.CompanyID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+
+	if err := response.Error(); err != nil {
+		return nil, err
+	}
+
+	identities := make([]resources.IAMIdentity, 0)
+	if err := response.ParseResponse(&identities); err != nil {
+		return nil, fmt.Errorf("error parsing response body: %w", err)
+	}
+
+	for _, companyIdentity := range identities {
+		if companyIdentity.ID != claims.Sub {
+			continue
+		}
+
+		result.Name = companyIdentity.Name
+		result.Type = companyIdentity.Type
+		if companyIdentity.Roles != nil {
+			result.CompanyRoles = companyIdentity.Roles
+		}
+		break
+	}
+
+	return result, nil
+}
+
+// credentialDescription return a description of where the credential used by config has been read from
+func credentialDescription(config *client.Config) string {
+	switch {
+	case len(config.BearerToken) > 0:
+		return "access token from environment"
+	case len(config.AuthName) > 0:
+		return config.AuthName
+	case len(config.ClientID) > 0:
+		return "service account from environment"
+	default:
+		return "user login"
+	}
+}
+
+func printIdentity(out io.Writer, p printer.IPrinter, identity *identity, outputFormat string) error {
+	if outputFormat != whoamiOutputTable {
+		data, err := encoding.MarshalData(identity, outputFormat, encoding.MarshalOptions{Indent: true})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	expiresAt := unknownValue
+	if identity.ExpiresAt != nil {
+		remaining := time.Until(*identity.ExpiresAt)
+		switch {
+		case remaining <= 0:
+			expiresAt = identity.ExpiresAt.Local().Format(timeFormat) + " (expired)"
+		default:
+			expiresAt = fmt.Sprintf("%s (in %s)", identity.ExpiresAt.Local().Format(timeFormat), util.HumanDuration(remaining))
+		}
+	}
+
+	p.Keys("Name", "Type", "ID", "Company Roles", "Expires At", "Context", "Credential")
+	p.Record(identity.Name, identity.Type, identity.ID, strings.Join(identity.CompanyRoles, ", "), expiresAt, identity.Context, identity.Credential)
+	p.Print()
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/printer"
+)
+
+func testAccessToken(claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+}
+
+func TestWhoami(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/companies/company/identities":
+			assert.Equal(t, "Bearer "+testAccessToken(`{"sub":"sa-id","exp":1767258000}`), r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[
+				{"identityId":"user-id","name":"User","identityType":"user","companyRoles":["company-owner"]},
+				{"identityId":"sa-id","name":"Deployer","identityType":"serviceAccount","companyRoles":["developer","reporter"]}
+			]`))
+		default:
+			assert.Failf(t, "unexpected request", "%s request %s", r.Method, r.RequestURI)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	expiry := time.Unix(1767258000, 0)
+	testCases := map[string]struct {
+		config           *client.Config
+		expectedIdentity *identity
+		expectErr        bool
+	}{
+		"identity found in company": {
+			config: &client.Config{
+				Host:        server.URL,
+				CompanyID:   "company",
+				ContextName: "context",
+				BearerToken: testAccessToken(`{"sub":"sa-id","exp":1767258000}`),
+			},
+			expectedIdentity: &identity{
+				ID:           "sa-id",
+				Name:         "Deployer",
+				Type:         "serviceAccount",
+				CompanyID:    "company",
+				CompanyRoles: []string{"developer", "reporter"},
+				ExpiresAt:    &expiry,
+				Context:      "context",
+				Credential:   "access token from environment",
+			},
+		},
+		"without company": {
+			config: &client.Config{
+				Host:        server.URL,
+				ContextName: "context",
+				BearerToken: testAccessToken(`{"sub":"sa-id"}`),
+			},
+			expectedIdentity: &identity{
+				ID:           "sa-id",
+				Name:         unknownValue,
+				Type:         unknownValue,
+				CompanyRoles: []string{},
+				Context:      "context",
+				Credential:   "access token from environment",
+			},
+		},
+		"token is not a jwt": {
+			config:    &client.Config{Host: server.URL, BearerToken: "opaque"},
+			expectErr: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			identity, err := whoami(t.Context(), testCase.config)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestCredentialDescription(t *testing.T) {
+	assert.Equal(t, "access token from environment", credentialDescription(&client.Config{BearerToken: "token"}))
+	assert.Equal(t, "credential", credentialDescription(&client.Config{AuthName: "credential"}))
+	assert.Equal(t, "service account from environment", credentialDescription(&client.Config{AuthConfig: client.AuthConfig{ClientID: "id"}}))
+	assert.Equal(t, "user login", credentialDescription(&client.Config{}))
+}
+
+func TestPrintIdentity(t *testing.T) {
+	expiry := time.Now().Add(-time.Hour)
+	testIdentity := &identity{
+		ID:           "sa-id",
+		Name:         "Deployer",
+		Type:         "serviceAccount",
+		CompanyRoles: []string{"developer", "reporter"},
+		ExpiresAt:    &expiry,
+		Context:      "context",
+		Credential:   "credential",
+	}
+
+	out := new(bytes.Buffer)
+	p := printer.NewTablePrinter(printer.TablePrinterOptions{}, out)
+	require.NoError(t, printIdentity(out, p, testIdentity, whoamiOutputTable))
+	assert.Contains(t, out.String(), "Deployer")
+	assert.Contains(t, out.String(), "developer, reporter")
+	assert.Contains(t, out.String(), "(expired)")
+
+	out.Reset()
+	require.NoError(t, printIdentity(out, p, testIdentity, "json"))
+	assert.Contains(t, out.String(), `"name": "Deployer"`)
+	assert.Contains(t, out.String(), `"credential": "credential"`)
+}
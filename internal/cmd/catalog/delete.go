@@ -52,9 +52,13 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 		SuggestFor: []string{"rm"},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), client, 14, 0)
 			if versionError != nil {
@@ -70,6 +74,11 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 			}
 
 			if options.MarketplaceItemVersion != "" && options.MarketplaceItemID != "" {
+				confirmMessage := fmt.Sprintf("The Catalog item %s version %s will be deleted", options.MarketplaceItemID, options.MarketplaceItemVersion)
+				if err := options.ConfirmDestructiveAction(confirmMessage); err != nil {
+					return err
+				}
+
 				err = deleteItemByItemIDAndVersion(
 					cmd.Context(),
 					client,
@@ -77,8 +86,7 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 					options.MarketplaceItemID,
 					options.MarketplaceItemVersion,
 				)
-				cobra.CheckErr(err)
-				return nil
+				return err
 			}
 
 			return errors.New("invalid input parameters")
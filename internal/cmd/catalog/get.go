@@ -50,9 +50,13 @@ func GetCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  cmdGetLongDescription,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), client, 14, 0)
 			if versionError != nil {
@@ -70,7 +74,9 @@ func GetCmd(options *clioptions.CLIOptions) *cobra.Command {
 				options.MarketplaceItemVersion,
 				options.OutputFormat,
 			)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			fmt.Println(serializedItem)
 			return nil
@@ -31,11 +31,13 @@ const (
 
     This command lists the Catalog items of a company. It works with Mia-Platform Console v14.0.0 or later.
 
-		Results are paginated. By default, only the first page is shown.
+    Results are paginated. By default, only the first page is shown.
 
     you can also specify the following flags:
     - --public - if this flag is set, the command fetches not only the items from the requested company, but also the public Catalog items from other companies.
-		- --page - specify the page to fetch, default is 1
+    - --page - specify the page to fetch, default is 1
+    - --page-size - specify the number of items of every page
+    - --all - fetch all the pages starting from the selected one
     `
 	listCmdUse = "list --company-id company-id"
 )
@@ -50,7 +52,8 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 	}
 
 	options.AddPublicFlag(cmd.Flags())
-	options.AddPageFlag(cmd.Flags())
+	options.AddPaginationFlags(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
 
 	return cmd
 }
@@ -58,9 +61,17 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 func runListCmd(options *clioptions.CLIOptions) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		restConfig, err := options.ToRESTConfig()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 		apiClient, err := client.APIClientForConfig(restConfig)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+		printer, err := options.Printer()
+		if err != nil {
+			return err
+		}
 
 		canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), apiClient, 14, 0)
 		if versionError != nil {
@@ -74,10 +85,14 @@ func runListCmd(options *clioptions.CLIOptions) func(cmd *cobra.Command, args []
 			CompanyID: restConfig.CompanyID,
 			Public:    options.MarketplaceFetchPublicItems,
 			Page:      options.Page,
+			PageSize:  options.PageSize,
+			All:       options.AllPages,
 		}
 
-		err = commonMarketplace.PrintMarketplaceItems(cmd.Context(), apiClient, marketplaceItemsOptions, options.Printer(), listMarketplaceEndpoint)
-		cobra.CheckErr(err)
+		err = commonMarketplace.PrintMarketplaceItems(cmd.Context(), apiClient, marketplaceItemsOptions, printer, listMarketplaceEndpoint)
+		if err != nil {
+			return err
+		}
 
 		return nil
 	}
@@ -16,8 +16,10 @@
 package catalog
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -105,6 +107,22 @@ func TestBuildMarketplaceItemsList(t *testing.T) {
 				"43774c07d09ac6996ecfb3eg", "a-public-service", "A public service", "plugin", "another-company",
 			},
 		},
+		{
+			name: "all pages",
+			options: commonMarketplace.GetMarketplaceItemsOptions{
+				CompanyID: "my-company",
+				Page:      1,
+				PageSize:  1,
+				All:       true,
+			},
+			responseHandler: pagedMarketplaceHandler(t),
+			clientConfig:    &client.Config{Transport: http.DefaultTransport},
+			expectError:     false,
+			expectedContains: []string{
+				"43774c07d09ac6996ecfb3ef", "space-travel-service",
+				"43774c07d09ac6996ecfb3eg", "a-public-service",
+			},
+		},
 	}
 
 	runTestCase := func(t *testing.T, tc testCase) {
@@ -184,6 +202,27 @@ func privateCompanyMarketplaceHandler(t *testing.T) http.HandlerFunc {
 	}
 }
 
+func pagedMarketplaceHandler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+	var items []json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(marketplaceItemsBodyContent(t)), &items))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if !strings.EqualFold(r.URL.Path, "/api/marketplace/") || err != nil || r.URL.Query().Get("per_page") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			assert.Fail(t, "unexpected request: "+r.URL.String())
+			return
+		}
+
+		pageItems := make([]json.RawMessage, 0)
+		if page <= len(items) {
+			pageItems = append(pageItems, items[page-1])
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(pageItems))
+	}
+}
+
 func wrongPayloadHandler(t *testing.T) http.HandlerFunc {
 	t.Helper()
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -36,9 +36,17 @@ func ListVersionCmd(options *clioptions.CLIOptions) *cobra.Command {
 The command will output a table with each version of the item. It works with Mia-Platform Console v14.0.0 or later.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
+			printer, err := options.Printer(clioptions.DisableWrapLines(true))
+			if err != nil {
+				return err
+			}
 
 			canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), client, 14, 0)
 			if versionError != nil {
@@ -55,16 +63,15 @@ The command will output a table with each version of the item. It works with Mia
 				restConfig.CompanyID,
 				options.MarketplaceItemID,
 			)
-			cobra.CheckErr(err)
-
-			commonMarketplace.PrintItemVersionList(releases, options.Printer(
-				clioptions.DisableWrapLines(true),
-			))
+			if err != nil {
+				return err
+			}
 
-			return nil
+			return commonMarketplace.PrintItemVersionList(releases, printer)
 		},
 	}
 
+	options.AddListOutputFlag(cmd.Flags())
 	flagName := options.AddMarketplaceItemIDFlag(cmd.Flags())
 	err := cmd.MarkFlagRequired(flagName)
 	if err != nil {
@@ -218,7 +218,8 @@ func TestBuildMarketplaceItemVersionList(t *testing.T) {
 	for testName, testCase := range testCases {
 		t.Run(testName, func(t *testing.T) {
 			strBuilder := &strings.Builder{}
-			commonMarketplace.PrintItemVersionList(&testCase.releases, printer.NewTablePrinter(printer.TablePrinterOptions{WrapLinesDisabled: true}, strBuilder))
+			err := commonMarketplace.PrintItemVersionList(&testCase.releases, printer.NewTablePrinter(printer.TablePrinterOptions{WrapLinesDisabled: true}, strBuilder))
+			require.NoError(t, err)
 			found := strBuilder.String()
 			assert.NotEmpty(t, found)
 			for _, expected := range testCase.expectedContains {
@@ -17,7 +17,6 @@ package marketplace
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
 	"github.com/mia-platform/miactl/internal/client"
@@ -30,41 +29,46 @@ type GetMarketplaceItemsOptions struct {
 	CompanyID string
 	Public    bool
 	Page      int
+	PageSize  int
+	All       bool
 }
 
-func PrintMarketplaceItems(context context.Context, client *client.APIClient, options GetMarketplaceItemsOptions, p printer.IPrinter, endpoint string) error {
+func PrintMarketplaceItems(context context.Context, client *client.APIClient, options GetMarketplaceItemsOptions, p printer.ListPrinter, endpoint string) error {
 	marketplaceItems, err := fetchMarketplaceItems(context, client, options, endpoint)
 	if err != nil {
 		return err
 	}
 
-	p.Keys("Object ID", "Item ID", "Name", "Type", "Company ID")
-	for _, marketplaceItem := range marketplaceItems {
-		p.Record(
-			marketplaceItem.ID,
-			marketplaceItem.ItemID,
-			marketplaceItem.Name,
-			marketplaceItem.Type,
-			marketplaceItem.TenantID,
-		)
-	}
-	p.Print()
-	return nil
+	return p.PrintList(printer.NewList(marketplaceItems, marketplaceItemName, printer.Table[*resources.MarketplaceItem]{
+		Headers:     []string{"Object ID", "Item ID", "Name", "Type", "Company ID"},
+		WideHeaders: []string{"Supported By"},
+		Row:         rowForMarketplaceItem,
+	}))
 }
 
-func fetchMarketplaceItems(ctx context.Context, client *client.APIClient, options GetMarketplaceItemsOptions, endpoint string) ([]*resources.MarketplaceItem, error) {
-	err := validateOptions(options)
-	if err != nil {
-		return nil, err
+func marketplaceItemName(item *resources.MarketplaceItem) string {
+	return item.ItemID
+}
+
+func rowForMarketplaceItem(item *resources.MarketplaceItem) []string {
+	return []string{
+		item.ID,
+		item.ItemID,
+		item.Name,
+		item.Type,
+		item.TenantID,
+		item.SupportedBy,
 	}
+}
 
-	request := buildRequest(client, options, endpoint)
-	resp, err := executeRequest(ctx, request)
+func fetchMarketplaceItems(ctx context.Context, apiClient *client.APIClient, options GetMarketplaceItemsOptions, endpoint string) ([]*resources.MarketplaceItem, error) {
+	err := validateOptions(options)
 	if err != nil {
 		return nil, err
 	}
 
-	marketplaceItems, err := parseResponse(resp)
+	request := buildRequest(apiClient, options, endpoint)
+	marketplaceItems, err := client.ListPages[*resources.MarketplaceItem](ctx, client.NewPager(request, pageOptions(options, endpoint)))
 	if err != nil {
 		return nil, err
 	}
@@ -103,23 +107,15 @@ func buildRequest(client *client.APIClient, options GetMarketplaceItemsOptions,
 	return request
 }
 
-func executeRequest(ctx context.Context, request *client.Request) (*client.Response, error) {
-	resp, err := request.Do(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("error executing request: %w", err)
-	}
-	if err := resp.Error(); err != nil {
-		return nil, err
+// pageOptions return the pages to fetch from endpoint, the marketplace command api does not support pagination
+func pageOptions(options GetMarketplaceItemsOptions, endpoint string) client.PageOptions {
+	if endpoint != "/api/marketplace/" {
+		return client.PageOptions{}
 	}
 
-	return resp, nil
-}
-
-func parseResponse(resp *client.Response) ([]*resources.MarketplaceItem, error) {
-	marketplaceItems := make([]*resources.MarketplaceItem, 0)
-	if err := resp.ParseResponse(&marketplaceItems); err != nil {
-		return nil, fmt.Errorf("error parsing response body: %w", err)
+	return client.PageOptions{
+		Page:     options.Page,
+		PageSize: options.PageSize,
+		All:      options.All,
 	}
-
-	return marketplaceItems, nil
 }
@@ -59,19 +59,25 @@ func GetItemVersions(ctx context.Context, client *client.APIClient, endpoint str
 	return nil, ErrGenericServerError
 }
 
-func PrintItemVersionList(releases *[]marketplace.Release, p printer.IPrinter) {
-	p.Keys("Version", "Name", "Description")
+func PrintItemVersionList(releases *[]marketplace.Release, p printer.ListPrinter) error {
+	return p.PrintList(printer.NewList(*releases, releaseName, printer.Table[marketplace.Release]{
+		Headers: []string{"Version", "Name", "Description"},
+		Row:     rowForRelease,
+	}))
+}
 
-	for _, release := range *releases {
-		description := "-"
-		if release.Description != "" {
-			description = release.Description
-		}
-		p.Record(
-			release.Version,
-			release.Name,
-			description,
-		)
+func releaseName(release marketplace.Release) string {
+	return release.Version
+}
+
+func rowForRelease(release marketplace.Release) []string {
+	description := "-"
+	if release.Description != "" {
+		description = release.Description
+	}
+	return []string{
+		release.Version,
+		release.Name,
+		description,
 	}
-	p.Print()
 }
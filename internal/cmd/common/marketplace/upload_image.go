@@ -35,6 +35,9 @@ const (
 	UploadImageEndpointTemplate = "/api/backend/marketplace/tenants/%s/files"
 	MultipartFieldName          = "marketplace_image"
 
+	// DryRunImageURL replace the url of the images not uploaded because of the dry run
+	DryRunImageURL = "<image not uploaded in dry run>"
+
 	localPathKey = "localPath"
 
 	jpegMimeType = "image/jpeg"
@@ -262,6 +265,10 @@ func uploadSingleFileWithMultipart(
 	if err := resp.Error(); err != nil {
 		return "", err
 	}
+	if resp.DryRun() {
+		// the image has not been uploaded, so the server has not returned its url
+		return DryRunImageURL, nil
+	}
 
 	uploadResp := &marketplace.UploadImageResponse{}
 	err = resp.ParseResponse(uploadResp)
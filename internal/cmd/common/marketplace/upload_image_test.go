@@ -17,16 +17,19 @@ package marketplace
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 
 	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/resources/marketplace"
+	"github.com/mia-platform/miactl/internal/transport"
 )
 
 func TestApplyGetAndValidateImageLocalPath(t *testing.T) {
@@ -157,6 +160,38 @@ func TestApplyUploadImage(t *testing.T) {
 		require.Equal(t, "https://example.org/image.png", found)
 	})
 
+	t.Run("should not upload the image in dry run", func(t *testing.T) {
+		imageFile, err := os.Open(MockImagePath)
+		require.NoError(t, err)
+		defer imageFile.Close()
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			assert.Fail(t, "the image must not be uploaded in dry run")
+		}))
+		defer mockServer.Close()
+		clientConfig := &client.Config{
+			Transport: transport.NewDryRunRoundTripper(transport.DryRunConfig{Enabled: true, Output: io.Discard}, http.DefaultTransport),
+		}
+		clientConfig.Host = mockServer.URL
+		client, err := client.APIClientForConfig(clientConfig)
+		require.NoError(t, err)
+
+		found, err := uploadSingleFileWithMultipart(
+			t.Context(),
+			client,
+			MockTenantID,
+			"image/png",
+			imageFile.Name(),
+			imageFile,
+			"someItemId",
+			"someAssetType",
+			"",
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, DryRunImageURL, found)
+	})
+
 	t.Run("should return error if companyID is not defined", func(t *testing.T) {
 		imageFile, err := os.Open(MockImagePath)
 		require.NoError(t, err)
@@ -41,6 +41,7 @@ via a group or through service accounts.`,
 		iam.AddCmd(o),
 		iam.EditCmd(o),
 		iam.RemoveCmd(o),
+		iam.ServiceAccountCmd(o),
 	)
 
 	return cmd
@@ -38,14 +38,18 @@ func AddMemberCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Add one or more users to a company group. The users can be added via their emails",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			err = addMemberToGroup(cmd.Context(), client, restConfig.CompanyID, options.EntityID, options.UserEmails)
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
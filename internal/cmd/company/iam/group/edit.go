@@ -35,14 +35,18 @@ func EditCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Edit a group in a company",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			err = editCompanyGroup(cmd.Context(), client, restConfig.CompanyID, options.EntityID, resources.IAMRole(options.IAMRole))
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
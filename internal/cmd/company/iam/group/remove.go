@@ -37,14 +37,21 @@ func RemoveCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Remove a group from a company",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
+			if err := options.ConfirmDestructiveAction(fmt.Sprintf("The group %s will be removed from the company", options.EntityID)); err != nil {
+				return err
+			}
 			err = removeCompanyGroup(cmd.Context(), client, restConfig.CompanyID, options.EntityID)
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
@@ -38,14 +38,21 @@ func RemoveMemberCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Remove one or more users from a company group. The users can be removed via their ids",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
+			if err := options.ConfirmDestructiveAction(fmt.Sprintf("The selected users will be removed from the group %s", options.EntityID)); err != nil {
+				return err
+			}
 			err = removeMemberFromGroup(cmd.Context(), client, restConfig.CompanyID, options.EntityID, options.UserIDs)
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
@@ -18,7 +18,6 @@ package iam
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -26,7 +25,7 @@ import (
 	"github.com/mia-platform/miactl/internal/clioptions"
 	"github.com/mia-platform/miactl/internal/iam"
 	"github.com/mia-platform/miactl/internal/printer"
-	"github.com/mia-platform/miactl/internal/util"
+	"github.com/mia-platform/miactl/internal/resources"
 )
 
 func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
@@ -39,9 +38,13 @@ all of them noting the type and the current role associated with them`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			entityTypes := map[string]bool{
 				iam.GroupsEntityName:          options.ShowGroups,
@@ -49,11 +52,17 @@ all of them noting the type and the current role associated with them`,
 				iam.ServiceAccountsEntityName: options.ShowServiceAccounts,
 			}
 
-			return listAllIAMEntities(cmd.Context(), client, restConfig.CompanyID, entityTypes, options.Printer())
+			printer, err := options.Printer()
+			if err != nil {
+				return err
+			}
+			return listAllIAMEntities(cmd.Context(), client, restConfig.CompanyID, entityTypes, options.PageOptions(), printer)
 		},
 	}
 
 	options.AddIAMListFlags(cmd.Flags())
+	options.AddPaginationFlags(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
 	cmd.MarkFlagsMutuallyExclusive("users", "groups", "serviceAccounts")
 
 	cmd.AddCommand(
@@ -91,74 +100,86 @@ func listEntity(options *clioptions.CLIOptions, commandName, shortHelp, longHelp
 
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
-			return listSpecificEntities(cmd.Context(), client, restConfig.CompanyID, entityName, options.Printer())
+			printer, err := options.Printer()
+			if err != nil {
+				return err
+			}
+			return listSpecificEntities(cmd.Context(), client, restConfig.CompanyID, entityName, options.PageOptions(), printer)
 		},
 	}
 
+	options.AddPaginationFlags(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
 	return cmd
 }
 
-func listAllIAMEntities(ctx context.Context, client *client.APIClient, companyID string, entityTypes map[string]bool, p printer.IPrinter) error {
+func listAllIAMEntities(ctx context.Context, apiClient *client.APIClient, companyID string, entityTypes map[string]bool, pageOptions client.PageOptions, p printer.ListPrinter) error {
 	if len(companyID) == 0 {
 		return errors.New("missing company id, please set one with the flag or context")
 	}
-	resp, err := iam.ListAllIAMEntities(ctx, client, companyID, nil, entityTypes)
-
-	if err != nil {
-		return fmt.Errorf("error executing request: %w", err)
-	}
 
-	if err := resp.Error(); err != nil {
-		return err
-	}
-
-	rows, err := util.RowsForResources(resp, iam.RowForIAMIdentity)
+	request := iam.AllIAMEntitiesRequest(apiClient, companyID, nil, entityTypes)
+	identities, err := client.ListPages[resources.IAMIdentity](ctx, client.NewPager(request, pageOptions))
 	if err != nil {
 		return err
 	}
 
-	p.Keys("ID", "Type", "Name", "Roles").
-		BulkRecords(rows...).
-		Print()
-	return nil
+	return p.PrintList(printer.NewList(identities, iam.IAMIdentityName, printer.Table[resources.IAMIdentity]{
+		Headers: []string{"ID", "Type", "Name", "Roles"},
+		Row:     iam.RowForIAMIdentity,
+	}))
 }
 
-func listSpecificEntities(ctx context.Context, client *client.APIClient, companyID string, entityType string, p printer.IPrinter) error {
+func listSpecificEntities(ctx context.Context, apiClient *client.APIClient, companyID string, entityType string, pageOptions client.PageOptions, p printer.ListPrinter) error {
 	if len(companyID) == 0 {
 		return errors.New("missing company id, please set one with the flag or context")
 	}
-	response, err := iam.ListSpecificEntities(ctx, client, companyID, entityType)
 
+	request, err := iam.SpecificEntitiesRequest(apiClient, companyID, entityType)
 	if err != nil {
-		return fmt.Errorf("error executing request: %w", err)
-	}
-
-	if err := response.Error(); err != nil {
 		return err
 	}
+	pager := client.NewPager(request, pageOptions)
 
-	var tableHeaders []string
-	var rows [][]string
+	var list *printer.List
 	switch entityType {
 	case iam.UsersEntityName:
-		tableHeaders = []string{"ID", "Name", "Email", "Roles", "Groups", "Last Login"}
-		rows, err = util.RowsForResources(response, iam.RowForUserIdentity)
+		var users []resources.UserIdentity
+		if users, err = client.ListPages[resources.UserIdentity](ctx, pager); err == nil {
+			list = printer.NewList(users, iam.UserIdentityName, printer.Table[resources.UserIdentity]{
+				Headers: []string{"ID", "Name", "Email", "Roles", "Groups", "Last Login"},
+				Row:     iam.RowForUserIdentity,
+			})
+		}
 	case iam.GroupsEntityName:
-		tableHeaders = []string{"ID", "Name", "Roles", "Members"}
-		rows, err = util.RowsForResources(response, iam.RowForGroupIdentity)
+		var groups []resources.GroupIdentity
+		if groups, err = client.ListPages[resources.GroupIdentity](ctx, pager); err == nil {
+			list = printer.NewList(groups, iam.GroupIdentityName, printer.Table[resources.GroupIdentity]{
+				Headers: []string{"ID", "Name", "Roles", "Members"},
+				Row:     iam.RowForGroupIdentity,
+			})
+		}
 	case iam.ServiceAccountsEntityName:
-		tableHeaders = []string{"ID", "Name", "Roles", "Last Login"}
-		rows, err = util.RowsForResources(response, iam.RowForServiceAccountIdentity)
+		var serviceAccounts []resources.ServiceAccountIdentity
+		if serviceAccounts, err = client.ListPages[resources.ServiceAccountIdentity](ctx, pager); err == nil {
+			list = printer.NewList(serviceAccounts, iam.ServiceAccountIdentityName, printer.Table[resources.ServiceAccountIdentity]{
+				Headers: []string{"ID", "Name", "Roles", "Last Login"},
+				Row:     iam.RowForServiceAccountIdentity,
+			})
+		}
 	}
 
 	if err != nil {
 		return err
 	}
 
-	p.Keys(tableHeaders...).BulkRecords(rows...).Print()
-	return nil
+	return p.PrintList(list)
 }
@@ -64,9 +64,9 @@ func TestListAllIAMIdentities(t *testing.T) {
 				Host:      testCase.server.URL,
 			}
 
-			client, err := client.APIClientForConfig(clientConfig)
+			apiClient, err := client.APIClientForConfig(clientConfig)
 			require.NoError(t, err)
-			err = listAllIAMEntities(t.Context(), client, testCase.companyID, testCase.searchParams, &printer.NopPrinter{})
+			err = listAllIAMEntities(t.Context(), apiClient, testCase.companyID, testCase.searchParams, client.PageOptions{}, &printer.NopPrinter{})
 			if testCase.err {
 				assert.Error(t, err)
 			} else {
@@ -102,10 +102,10 @@ func TestListUsersIdentities(t *testing.T) {
 				Host:      testCase.server.URL,
 			}
 
-			client, err := client.APIClientForConfig(clientConfig)
+			apiClient, err := client.APIClientForConfig(clientConfig)
 			require.NoError(t, err)
 
-			err = listSpecificEntities(t.Context(), client, testCase.companyID, iam.UsersEntityName, &printer.NopPrinter{})
+			err = listSpecificEntities(t.Context(), apiClient, testCase.companyID, iam.UsersEntityName, client.PageOptions{}, &printer.NopPrinter{})
 			if testCase.err {
 				assert.Error(t, err)
 			} else {
@@ -144,10 +144,10 @@ func TestListGroupsIdentities(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			defer testCase.server.Close()
 			testCase.clientConfig.Host = testCase.server.URL
-			client, err := client.APIClientForConfig(testCase.clientConfig)
+			apiClient, err := client.APIClientForConfig(testCase.clientConfig)
 			require.NoError(t, err)
 
-			err = listSpecificEntities(t.Context(), client, testCase.companyID, iam.GroupsEntityName, &printer.NopPrinter{})
+			err = listSpecificEntities(t.Context(), apiClient, testCase.companyID, iam.GroupsEntityName, client.PageOptions{}, &printer.NopPrinter{})
 			if testCase.err {
 				assert.Error(t, err)
 			} else {
@@ -186,10 +186,10 @@ func TestServiceAccountGroupsIdentities(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			defer testCase.server.Close()
 			testCase.clientConfig.Host = testCase.server.URL
-			client, err := client.APIClientForConfig(testCase.clientConfig)
+			apiClient, err := client.APIClientForConfig(testCase.clientConfig)
 			require.NoError(t, err)
 
-			err = listSpecificEntities(t.Context(), client, testCase.companyID, iam.ServiceAccountsEntityName, &printer.NopPrinter{})
+			err = listSpecificEntities(t.Context(), apiClient, testCase.companyID, iam.ServiceAccountsEntityName, client.PageOptions{}, &printer.NopPrinter{})
 			if testCase.err {
 				assert.Error(t, err)
 			} else {
@@ -13,19 +13,25 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package util
+package iam
 
-import "github.com/mia-platform/miactl/internal/client"
+import (
+	"github.com/spf13/cobra"
 
-func RowsForResources[T any](response *client.Response, rowParser func(T) []string) ([][]string, error) {
-	resources := make([]T, 0)
-	if err := response.ParseResponse(&resources); err != nil {
-		return nil, err
-	}
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/cmd/company/iam/serviceaccount"
+)
 
-	rows := make([][]string, 0)
-	for _, resource := range resources {
-		rows = append(rows, rowParser(resource))
+func ServiceAccountCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serviceaccount",
+		Short: "Manage the service accounts of a company",
+		Long:  "Manage the service accounts of a company",
 	}
-	return rows, nil
+
+	cmd.AddCommand(
+		serviceaccount.RotateCmd(options),
+	)
+
+	return cmd
 }
@@ -44,13 +44,22 @@ service account is created on the company.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			serviceAccountName := args[0]
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			credentials, err := createBasicServiceAccount(cmd.Context(), client, serviceAccountName, restConfig.CompanyID, resources.IAMRole(options.IAMRole))
 			if err != nil {
 				return err
 			}
+			credentials, err := CreateBasicServiceAccount(cmd.Context(), client, serviceAccountName, restConfig.CompanyID, resources.IAMRole(options.IAMRole))
+			if err != nil {
+				return err
+			}
+
+			if options.DryRun {
+				cmd.Println("Dry run: the service account has not been created, so it has no credentials.")
+				return nil
+			}
 
 			cmd.Println("Service account created, please save the following parameters:")
 			cmd.Println("")
@@ -71,7 +80,9 @@ service account is created on the company.`,
 	return cmd
 }
 
-func createBasicServiceAccount(ctx context.Context, client *client.APIClient, name, companyID string, role resources.IAMRole) ([]string, error) {
+// CreateBasicServiceAccount create a new basic service account with name and role in companyID, and return
+// its client id and client secret
+func CreateBasicServiceAccount(ctx context.Context, client *client.APIClient, name, companyID string, role resources.IAMRole) ([]string, error) {
 	if !resources.IsValidIAMRole(role, false) {
 		return nil, fmt.Errorf("invalid service account role %s", role)
 	}
@@ -76,7 +76,7 @@ func TestCreateBasicServiceAccount(t *testing.T) {
 				Host: server.URL,
 			})
 			require.NoError(t, err)
-			credentials, err := createBasicServiceAccount(
+			credentials, err := CreateBasicServiceAccount(
 				t.Context(),
 				client,
 				testCase.serviceAccountName,
@@ -35,14 +35,18 @@ func EditCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Edit a service account in a company",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			err = editCompanyServiceAccount(cmd.Context(), client, restConfig.CompanyID, options.ServiceAccountID, resources.IAMRole(options.IAMRole))
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
@@ -17,6 +17,10 @@ package jwt
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -41,8 +45,15 @@ const (
 	companyServiceAccountsEndpointTemplate = "/api/companies/%s/service-accounts"
 	defaultJSONType                        = "service_account"
 	defaultKeyID                           = "miactl"
+
+	keyTypeRSA     = "rsa"
+	keyTypeECDSA   = "ecdsa"
+	keyTypeEd25519 = "ed25519"
 )
 
+// KeyTypes are the types of key that can be generated for a jwt service account
+var KeyTypes = []string{keyTypeRSA, keyTypeECDSA, keyTypeEd25519}
+
 func ServiceAccountCmd(options *clioptions.CLIOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "jwt SERVICEACCOUNT [flags]",
@@ -51,20 +62,33 @@ func ServiceAccountCmd(options *clioptions.CLIOptions) *cobra.Command {
 
 You can create a service account with the same or lower role than the role that
 the current authentication has. The role company-owner can be used only when the
-service account is created on the company.`,
+service account is created on the company.
+
+The key pair is generated locally and by default is a 4096 bit RSA key, use the
+key-type flag for generating a smaller ECDSA P-256 (ES256) or Ed25519 (EdDSA) key.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			serviceAccountName := args[0]
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			credentials, err := createJWTServiceAccount(cmd.Context(), client, serviceAccountName, restConfig.CompanyID, resources.IAMRole(options.IAMRole))
+			if err != nil {
+				return err
+			}
+			credentials, err := CreateJWTServiceAccount(cmd.Context(), client, serviceAccountName, restConfig.CompanyID, resources.IAMRole(options.IAMRole), options.ServiceAccountKeyType)
 			if err != nil {
 				return err
 			}
 
-			return saveCredentialsIfNeeded(credentials, options.OutputPath, cmd.OutOrStdout())
+			if options.DryRun {
+				// the public key has not been registered, so the generated credentials cannot be used
+				fmt.Fprintln(cmd.OutOrStdout(), "Dry run: the service account has not been created, so it has no credentials.")
+				return nil
+			}
+
+			return SaveCredentialsIfNeeded(credentials, options.OutputPath, "created", cmd.OutOrStdout())
 		},
 	}
 
@@ -77,6 +101,14 @@ service account is created on the company.`,
 		panic(err)
 	}
 
+	err = cmd.RegisterFlagCompletionFunc("key-type", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return KeyTypes, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		// we panic here because if we reach here, something nasty is happenign in flag autocomplete registration
+		panic(err)
+	}
+
 	err = cmd.MarkFlagDirname("output")
 	if err != nil {
 		// we panic here because if we reach here, something nasty is happenign in flag autocomplete registration
@@ -86,7 +118,9 @@ service account is created on the company.`,
 	return cmd
 }
 
-func createJWTServiceAccount(ctx context.Context, client *client.APIClient, name, companyID string, role resources.IAMRole) (*resources.JWTServiceAccountJSON, error) {
+// CreateJWTServiceAccount create a new jwt service account with name and role in companyID, generating a new
+// key pair of keyType, and return its json configuration
+func CreateJWTServiceAccount(ctx context.Context, client *client.APIClient, name, companyID string, role resources.IAMRole, keyType string) (*resources.JWTServiceAccountJSON, error) {
 	if !resources.IsValidIAMRole(role, false) {
 		return nil, fmt.Errorf("invalid service account role %s", role)
 	}
@@ -95,12 +129,15 @@ func createJWTServiceAccount(ctx context.Context, client *client.APIClient, name
 		return nil, errors.New("company id is required, please set it via flag or context")
 	}
 
-	key, err := generateRSAKey()
+	key, err := GenerateKey(keyType)
 	if err != nil {
 		return nil, err
 	}
 
-	payload := requestFromKey(name, role, key)
+	payload, err := requestFromKey(name, role, key)
+	if err != nil {
+		return nil, err
+	}
 	body, err := resources.EncodeResourceToJSON(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode request body: %w", err)
@@ -125,6 +162,12 @@ func createJWTServiceAccount(ctx context.Context, client *client.APIClient, name
 		return nil, err
 	}
 
+	return CredentialsForKey(response.ClientID, key)
+}
+
+// CredentialsForKey return the json configuration of the jwt service account with clientID that will
+// authenticate with key
+func CredentialsForKey(clientID string, key crypto.Signer) (*resources.JWTServiceAccountJSON, error) {
 	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
 		return nil, err
@@ -141,23 +184,25 @@ func createJWTServiceAccount(ctx context.Context, client *client.APIClient, name
 		Type:           defaultJSONType,
 		KeyID:          defaultKeyID,
 		PrivateKeyData: base64.StdEncoding.EncodeToString(pemData),
-		ClientID:       response.ClientID,
+		ClientID:       clientID,
 	}, nil
 }
 
-func saveCredentialsIfNeeded(credentials *resources.JWTServiceAccountJSON, outputPath string, stdout io.Writer) error {
+// SaveCredentialsIfNeeded write credentials as json in outputPath, or in stdout if outputPath is empty;
+// action is the past participle of the operation that has produced the credentials, like created
+func SaveCredentialsIfNeeded(credentials *resources.JWTServiceAccountJSON, outputPath, action string, stdout io.Writer) error {
 	var encoder *json.Encoder
 	var fileDest *os.File
 	if len(outputPath) > 0 {
 		var err error
-		fileDest, err = os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0600)
+		fileDest, err = os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 		if err != nil {
 			return err
 		}
-		fmt.Fprintf(stdout, "Service account created, credentials saved in %s\n", outputPath)
+		fmt.Fprintf(stdout, "Service account %s, credentials saved in %s\n", action, outputPath)
 		encoder = json.NewEncoder(fileDest)
 	} else {
-		fmt.Fprintln(stdout, "Service account created, save the following json for later uses:")
+		fmt.Fprintf(stdout, "Service account %s, save the following json for later uses:\n", action)
 		encoder = json.NewEncoder(stdout)
 	}
 
@@ -171,27 +216,74 @@ func saveCredentialsIfNeeded(credentials *resources.JWTServiceAccountJSON, outpu
 	return encoder.Encode(credentials)
 }
 
-func generateRSAKey() (*rsa.PrivateKey, error) {
-	return rsa.GenerateKey(rand.Reader, rsaKeyBytes)
+// GenerateKey return a new private key of keyType, an empty keyType will generate an RSA key
+func GenerateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case "", keyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBytes)
+	case keyTypeECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case keyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("invalid key type %s, allowed values are rsa, ecdsa and ed25519", keyType)
+	}
 }
 
-func requestFromKey(name string, role resources.IAMRole, key *rsa.PrivateKey) *resources.ServiceAccountRequest {
-	encoder := base64.RawURLEncoding
-	modulus, exponent := rsaPublicKeyToBytes(&key.PublicKey)
+func requestFromKey(name string, role resources.IAMRole, key crypto.Signer) (*resources.ServiceAccountRequest, error) {
+	publicKey, err := PublicJWK(key)
+	if err != nil {
+		return nil, err
+	}
 
 	return &resources.ServiceAccountRequest{
-		Name: name,
-		Role: role,
-		Type: resources.ServiceAccountJWT,
-		PublicKey: &resources.PublicKey{
-			Use:       "sig",
-			Type:      "RSA",
-			Algorithm: "RSA256",
-			KeyID:     defaultKeyID,
-			Modulus:   encoder.EncodeToString(modulus),
-			Exponent:  encoder.EncodeToString(exponent),
-		},
+		Name:      name,
+		Role:      role,
+		Type:      resources.ServiceAccountJWT,
+		PublicKey: publicKey,
+	}, nil
+}
+
+// PublicJWK return the public part of key encoded as a JWK, following
+// https://www.rfc-editor.org/rfc/rfc7518#section-6 for RSA and EC keys and
+// https://www.rfc-editor.org/rfc/rfc8037#section-2 for Ed25519 keys
+func PublicJWK(key crypto.Signer) (*resources.PublicKey, error) {
+	encoder := base64.RawURLEncoding
+	jwk := &resources.PublicKey{
+		Use:   "sig",
+		KeyID: defaultKeyID,
 	}
+
+	switch publicKey := key.Public().(type) {
+	case *rsa.PublicKey:
+		modulus, exponent := rsaPublicKeyToBytes(publicKey)
+		jwk.Type = "RSA"
+		jwk.Algorithm = "RSA256"
+		jwk.Modulus = encoder.EncodeToString(modulus)
+		jwk.Exponent = encoder.EncodeToString(exponent)
+	case *ecdsa.PublicKey:
+		// the uncompressed point is 0x04 followed by x and y, already padded to the curve size
+		point, err := publicKey.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		coordinateSize := (len(point) - 1) / 2
+		jwk.Type = "EC"
+		jwk.Algorithm = "ES256"
+		jwk.Curve = "P-256"
+		jwk.X = encoder.EncodeToString(point[1 : 1+coordinateSize])
+		jwk.Y = encoder.EncodeToString(point[1+coordinateSize:])
+	case ed25519.PublicKey:
+		jwk.Type = "OKP"
+		jwk.Algorithm = "EdDSA"
+		jwk.Curve = "Ed25519"
+		jwk.X = encoder.EncodeToString(publicKey)
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+
+	return jwk, nil
 }
 
 // rsaPublicKeyToBytes take an RSA PublicKey struct as inpunt and return two
@@ -17,6 +17,7 @@ package jwt
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -31,10 +32,11 @@ import (
 )
 
 func TestRequestFromKey(t *testing.T) {
-	key, err := generateRSAKey()
+	key, err := GenerateKey(keyTypeRSA)
 	assert.NoError(t, err)
 
-	payload := requestFromKey("testName", resources.IAMRoleCompanyOwner, key)
+	payload, err := requestFromKey("testName", resources.IAMRoleCompanyOwner, key)
+	require.NoError(t, err)
 	assert.Equal(t, "testName", payload.Name)
 	assert.Equal(t, resources.IAMRoleCompanyOwner, payload.Role)
 	assert.Equal(t, "sig", payload.PublicKey.Use)
@@ -44,11 +46,68 @@ func TestRequestFromKey(t *testing.T) {
 	assert.Equal(t, "AQAB", payload.PublicKey.Exponent)
 }
 
+func TestPublicJWK(t *testing.T) {
+	testCases := map[string]struct {
+		keyType           string
+		expectedType      string
+		expectedAlgorithm string
+		expectedCurve     string
+		expectedXLen      int
+		expectedYLen      int
+	}{
+		"ecdsa key": {
+			keyType:           keyTypeECDSA,
+			expectedType:      "EC",
+			expectedAlgorithm: "ES256",
+			expectedCurve:     "P-256",
+			expectedXLen:      32,
+			expectedYLen:      32,
+		},
+		"ed25519 key": {
+			keyType:           keyTypeEd25519,
+			expectedType:      "OKP",
+			expectedAlgorithm: "EdDSA",
+			expectedCurve:     "Ed25519",
+			expectedXLen:      32,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			key, err := GenerateKey(testCase.keyType)
+			require.NoError(t, err)
+
+			jwk, err := PublicJWK(key)
+			require.NoError(t, err)
+			assert.Equal(t, "sig", jwk.Use)
+			assert.Equal(t, defaultKeyID, jwk.KeyID)
+			assert.Equal(t, testCase.expectedType, jwk.Type)
+			assert.Equal(t, testCase.expectedAlgorithm, jwk.Algorithm)
+			assert.Equal(t, testCase.expectedCurve, jwk.Curve)
+			assert.Empty(t, jwk.Modulus)
+			assert.Empty(t, jwk.Exponent)
+
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			require.NoError(t, err)
+			assert.Len(t, x, testCase.expectedXLen)
+			y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+			require.NoError(t, err)
+			assert.Len(t, y, testCase.expectedYLen)
+		})
+	}
+}
+
+func TestGenerateKeyInvalidType(t *testing.T) {
+	_, err := GenerateKey("dsa")
+	assert.EqualError(t, err, "invalid key type dsa, allowed values are rsa, ecdsa and ed25519")
+}
+
 func TestCreateServiceAccount(t *testing.T) {
 	testCases := map[string]struct {
 		server    *httptest.Server
 		companyID string
 		role      resources.IAMRole
+		keyType   string
 		expectErr bool
 	}{
 		"create successul": {
@@ -56,6 +115,25 @@ func TestCreateServiceAccount(t *testing.T) {
 			companyID: "company",
 			role:      resources.IAMRoleGuest,
 		},
+		"create successul with ecdsa key": {
+			server:    testServer(t),
+			companyID: "company",
+			role:      resources.IAMRoleGuest,
+			keyType:   keyTypeECDSA,
+		},
+		"create successul with ed25519 key": {
+			server:    testServer(t),
+			companyID: "company",
+			role:      resources.IAMRoleGuest,
+			keyType:   keyTypeEd25519,
+		},
+		"wrong key type": {
+			server:    testServer(t),
+			companyID: "unused",
+			role:      resources.IAMRoleGuest,
+			keyType:   "dsa",
+			expectErr: true,
+		},
 		"wrong role": {
 			server:    testServer(t),
 			companyID: "unused",
@@ -78,7 +156,7 @@ func TestCreateServiceAccount(t *testing.T) {
 				Host: server.URL,
 			})
 			require.NoError(t, err)
-			response, err := createJWTServiceAccount(t.Context(), client, "foo", testCase.companyID, testCase.role)
+			response, err := CreateJWTServiceAccount(t.Context(), client, "foo", testCase.companyID, testCase.role, testCase.keyType)
 			if testCase.expectErr {
 				assert.Error(t, err)
 				assert.Nil(t, response)
@@ -106,14 +184,14 @@ func TestSaveCredentials(t *testing.T) {
 	"client-id": "client-id"
 }
 `
-	err := saveCredentialsIfNeeded(testCredentials, "", testBuffer)
+	err := SaveCredentialsIfNeeded(testCredentials, "", "created", testBuffer)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedString, testBuffer.String())
 	testBuffer.Reset()
 
 	testFile := filepath.Join(t.TempDir(), "file.json")
 	expectedString = fmt.Sprintf("Service account created, credentials saved in %s\n", testFile)
-	err = saveCredentialsIfNeeded(testCredentials, testFile, testBuffer)
+	err = SaveCredentialsIfNeeded(testCredentials, testFile, "created", testBuffer)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedString, testBuffer.String())
 }
@@ -37,14 +37,21 @@ func RemoveCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Remove a service account from a company",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
+			if err := options.ConfirmDestructiveAction(fmt.Sprintf("The service account %s will be removed from the company", options.ServiceAccountID)); err != nil {
+				return err
+			}
 			err = removeCompanyServiceAccount(cmd.Context(), client, restConfig.CompanyID, options.ServiceAccountID)
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
@@ -0,0 +1,409 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceaccount
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/cmd/company/iam/serviceaccount/basic"
+	"github.com/mia-platform/miactl/internal/cmd/company/iam/serviceaccount/jwt"
+	"github.com/mia-platform/miactl/internal/iam"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+const (
+	rotateServiceAccountTemplate = "/api/companies/%s/service-accounts/%s/rotate"
+	encryptedPrivateKeyType      = "ENCRYPTED PRIVATE KEY"
+)
+
+// rotatedCredentials contains the new credentials of a service account, jwt is set only for jwt service accounts
+type rotatedCredentials struct {
+	clientID     string
+	clientSecret string
+	jwt          *resources.JWTServiceAccountJSON
+
+	// recreated is true if the server does not support the rotation and the service account has been recreated
+	recreated bool
+}
+
+func RotateCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate the credentials of a service account",
+		Long: `Rotate the credentials of a service account in a company.
+
+For jwt service accounts a new key pair is generated locally and the new public key is
+registered, for basic service accounts a new client secret is generated.
+
+If the server does not support the rotation, the service account is recreated with the
+same name and company role, its project and environment roles are applied again and
+then the old one is removed: in this case the client id of the service account changes.
+
+The new credentials can be saved directly in an existing miactl auth configuration
+using the update-auth flag.`,
+
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			restConfig, err := options.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+			client, err := client.APIClientForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+
+			// the credentials of a dry run are not registered on the server and cannot replace the current ones
+			updateAuth := len(options.UpdateAuthName) > 0 && !options.DryRun
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = options.MiactlConfig
+			if updateAuth {
+				// check the auth configuration before rotating, so the old credentials are not lost if it cannot be updated
+				if err := checkAuthConfig(locator, options.UpdateAuthName); err != nil {
+					return err
+				}
+			}
+
+			credentials, err := rotateServiceAccount(cmd.Context(), client, restConfig.CompanyID, options.ServiceAccountID, options.ServiceAccountKeyType)
+			if err != nil {
+				if credentials == nil {
+					return err
+				}
+				// the new service account has been created, its credentials are available only now
+				return errors.Join(err, printCredentials(credentials, options.OutputPath, cmd.OutOrStdout()))
+			}
+
+			if options.DryRun {
+				fmt.Fprintln(cmd.OutOrStdout(), "Dry run: the credentials have not been rotated, so there are no new credentials.")
+				return nil
+			}
+
+			if credentials.recreated {
+				fmt.Fprintf(cmd.ErrOrStderr(), "The server does not support the rotation, the service account has been recreated with id %s\n", credentials.clientID)
+			}
+
+			if updateAuth {
+				if err := updateAuthConfig(locator, options.UpdateAuthName, credentials); err != nil {
+					return errors.Join(err, printCredentials(credentials, options.OutputPath, cmd.OutOrStdout()))
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Auth \"%s\" updated with the new credentials.\n", options.UpdateAuthName)
+			}
+
+			return printCredentials(credentials, options.OutputPath, cmd.OutOrStdout())
+		},
+	}
+
+	options.AddRotateServiceAccountFlags(cmd.Flags())
+	err := cmd.RegisterFlagCompletionFunc("key-type", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return jwt.KeyTypes, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		// we panic here because if we reach here, something nasty is happening in flag autocomplete registration
+		panic(err)
+	}
+
+	return cmd
+}
+
+func rotateServiceAccount(ctx context.Context, client *client.APIClient, companyID, serviceAccountID, keyType string) (*rotatedCredentials, error) {
+	if len(companyID) == 0 {
+		return nil, errors.New("company id is required, please set it via flag or context")
+	}
+
+	if len(serviceAccountID) == 0 {
+		return nil, errors.New("the service account id is required")
+	}
+
+	serviceAccount, err := findServiceAccount(ctx, client, companyID, serviceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var key crypto.Signer
+	payload := new(resources.RotateServiceAccountRequest)
+	switch serviceAccount.AuthMethod {
+	case resources.ServiceAccountJWT:
+		if key, err = jwt.GenerateKey(keyType); err != nil {
+			return nil, err
+		}
+		if payload.PublicKey, err = jwt.PublicJWK(key); err != nil {
+			return nil, err
+		}
+	case resources.ServiceAccountBasic:
+	default:
+		return nil, fmt.Errorf("unsupported authentication method %s for service account %s", serviceAccount.AuthMethod, serviceAccountID)
+	}
+
+	body, err := resources.EncodeResourceToJSON(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	resp, err := client.
+		Post().
+		APIPath(fmt.Sprintf(rotateServiceAccountTemplate, companyID, serviceAccountID)).
+		Body(body).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		// the service account exists, so the server does not know the rotate endpoint
+		return recreateServiceAccount(ctx, client, companyID, serviceAccount, keyType)
+	}
+
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+
+	if key != nil {
+		credentials, err := jwt.CredentialsForKey(serviceAccountID, key)
+		if err != nil {
+			return nil, err
+		}
+		return &rotatedCredentials{clientID: serviceAccountID, jwt: credentials}, nil
+	}
+
+	response := new(resources.ServiceAccount)
+	if err := resp.ParseResponse(response); err != nil {
+		return nil, err
+	}
+
+	return &rotatedCredentials{clientID: serviceAccountID, clientSecret: response.ClientSecret}, nil
+}
+
+// recreateServiceAccount create a new service account with the same name, type and roles of serviceAccount,
+// the old service account is removed only after the new one has received all the roles; if a step fails after
+// the creation, the new credentials are returned together with the error, because they cannot be read again
+func recreateServiceAccount(ctx context.Context, client *client.APIClient, companyID string, serviceAccount *resources.ServiceAccountIdentity, keyType string) (*rotatedCredentials, error) {
+	if len(serviceAccount.Roles) == 0 {
+		return nil, fmt.Errorf("cannot recreate service account %s without a company role", serviceAccount.ID)
+	}
+	role := resources.IAMRole(serviceAccount.Roles[0])
+
+	projectsRole, err := projectsRoleForIdentity(ctx, client, companyID, serviceAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := &rotatedCredentials{recreated: true}
+	switch serviceAccount.AuthMethod {
+	case resources.ServiceAccountJWT:
+		credentials.jwt, err = jwt.CreateJWTServiceAccount(ctx, client, serviceAccount.Name, companyID, role, keyType)
+		if err != nil {
+			return nil, err
+		}
+		credentials.clientID = credentials.jwt.ClientID
+	default:
+		basicCredentials, err := basic.CreateBasicServiceAccount(ctx, client, serviceAccount.Name, companyID, role)
+		if err != nil {
+			return nil, err
+		}
+		credentials.clientID = basicCredentials[0]
+		credentials.clientSecret = basicCredentials[1]
+	}
+
+	if len(projectsRole) > 0 {
+		change := resources.EditIAMRole{ProjectsRole: projectsRole}
+		resp, err := iam.EditIAMResourceRole(ctx, client, companyID, credentials.clientID, iam.ServiceAccountsEntityName, change)
+		if err != nil {
+			return credentials, fmt.Errorf("service account recreated with id %s but its project roles are not applied: %w", credentials.clientID, err)
+		}
+		if err := resp.Error(); err != nil {
+			return credentials, fmt.Errorf("service account recreated with id %s but its project roles are not applied: %w", credentials.clientID, err)
+		}
+	}
+
+	if err := removeCompanyServiceAccount(ctx, client, companyID, serviceAccount.ID); err != nil {
+		return credentials, fmt.Errorf("service account recreated with id %s but the old one cannot be removed: %w", credentials.clientID, err)
+	}
+
+	return credentials, nil
+}
+
+// findServiceAccount return the service account of the company with serviceAccountID, searching it in all
+// the pages of the service accounts list
+func findServiceAccount(ctx context.Context, apiClient *client.APIClient, companyID, serviceAccountID string) (*resources.ServiceAccountIdentity, error) {
+	request, err := iam.SpecificEntitiesRequest(apiClient, companyID, iam.ServiceAccountsEntityName)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccounts, err := client.ListPages[resources.ServiceAccountIdentity](ctx, client.NewPager(request, client.PageOptions{All: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		if serviceAccount.ID == serviceAccountID {
+			return &serviceAccount, nil
+		}
+	}
+
+	return nil, fmt.Errorf("service account %s not found in company %s", serviceAccountID, companyID)
+}
+
+// projectsRoleForIdentity return the project and environment roles of the identity with identityID, in the
+// format used for editing them; an error is returned if the identity is not found in all the pages of the
+// identities list, because its roles would be lost when recreating it
+func projectsRoleForIdentity(ctx context.Context, apiClient *client.APIClient, companyID, identityID string) ([]resources.EditProjectRole, error) {
+	request := iam.AllIAMEntitiesRequest(apiClient, companyID, nil, map[string]bool{iam.ServiceAccountsEntityName: true})
+	identities, err := client.ListPages[resources.IAMIdentity](ctx, client.NewPager(request, client.PageOptions{All: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	projectsRole := make([]resources.EditProjectRole, 0)
+	for _, identity := range identities {
+		if identity.ID != identityID {
+			continue
+		}
+		found = true
+
+		for _, project := range identity.ProjectsRole {
+			projectRole := resources.EditProjectRole{ProjectID: project.ID}
+			if len(project.Roles) > 0 {
+				role := resources.IAMRole(project.Roles[0])
+				projectRole.Role = &role
+			}
+
+			for _, environment := range project.Environments {
+				if len(environment.Roles) == 0 {
+					continue
+				}
+				projectRole.EnvironmentsRole = append(projectRole.EnvironmentsRole, resources.EditEnvironmentRole{
+					EnvironmentID: environment.ID,
+					Role:          resources.IAMRole(environment.Roles[0]),
+				})
+			}
+
+			if projectRole.Role != nil || len(projectRole.EnvironmentsRole) > 0 {
+				projectsRole = append(projectsRole, projectRole)
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("cannot read the roles of service account %s in company %s", identityID, companyID)
+	}
+	return projectsRole, nil
+}
+
+// checkAuthConfig return an error if the auth configuration named authName cannot be updated with new credentials
+func checkAuthConfig(locator *cliconfig.ConfigPathLocator, authName string) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	_, err = authConfigToUpdate(config, authName)
+	return err
+}
+
+// authConfigToUpdate return the auth configuration named authName, an error is returned if it does not exist
+// or if it reads the jwt private key from an encrypted file, that cannot be replaced with an unencrypted key
+func authConfigToUpdate(config *api.Config, authName string) (*api.AuthConfig, error) {
+	authConfig, found := config.Auth[authName]
+	if !found || authConfig == nil {
+		return nil, fmt.Errorf("auth \"%s\" not found", authName)
+	}
+
+	if len(authConfig.JWTPrivateKeyFile) == 0 {
+		return authConfig, nil
+	}
+
+	pemData, err := os.ReadFile(authConfig.JWTPrivateKeyFile)
+	switch {
+	case os.IsNotExist(err):
+		return authConfig, nil
+	case err != nil:
+		return nil, fmt.Errorf("cannot read the private key file: %w", err)
+	}
+
+	if block, _ := pem.Decode(pemData); block != nil && block.Type == encryptedPrivateKeyType {
+		return nil, fmt.Errorf("the private key file %s of auth \"%s\" is encrypted and cannot be replaced with an unencrypted key, save the new credentials with the output flag and encrypt them instead", authConfig.JWTPrivateKeyFile, authName)
+	}
+	return authConfig, nil
+}
+
+// updateAuthConfig save credentials in the auth configuration named authName, if the auth configuration reads
+// the jwt private key from a file the new key is written in the same file
+func updateAuthConfig(locator *cliconfig.ConfigPathLocator, authName string, credentials *rotatedCredentials) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	authConfig, err := authConfigToUpdate(config, authName)
+	if err != nil {
+		return err
+	}
+
+	authConfig.ClientID = credentials.clientID
+	authConfig.Exec = nil
+	if credentials.jwt == nil {
+		authConfig.ClientSecret = credentials.clientSecret
+		authConfig.JWTKeyID = ""
+		authConfig.JWTPrivateKeyData = ""
+		authConfig.JWTPrivateKeyFile = ""
+		return locator.WriteConfig(config)
+	}
+
+	authConfig.ClientSecret = ""
+	authConfig.JWTKeyID = credentials.jwt.KeyID
+	if len(authConfig.JWTPrivateKeyFile) > 0 {
+		pemData, err := base64.StdEncoding.DecodeString(credentials.jwt.PrivateKeyData)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(authConfig.JWTPrivateKeyFile, pemData, 0600); err != nil {
+			return fmt.Errorf("cannot write the private key file: %w", err)
+		}
+		authConfig.JWTPrivateKeyData = ""
+	} else {
+		authConfig.JWTPrivateKeyData = credentials.jwt.PrivateKeyData
+	}
+
+	return locator.WriteConfig(config)
+}
+
+func printCredentials(credentials *rotatedCredentials, outputPath string, out io.Writer) error {
+	if credentials.jwt != nil {
+		return jwt.SaveCredentialsIfNeeded(credentials.jwt, outputPath, "rotated", out)
+	}
+
+	fmt.Fprintln(out, "Service account rotated, please save the following parameters:")
+	fmt.Fprintln(out, "")
+	fmt.Fprintf(out, "Client ID: %s\nClient Secret: %s\n", credentials.clientID, credentials.clientSecret)
+	return nil
+}
@@ -38,14 +38,18 @@ func AddCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Add a user to a company",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			err = addUserToCompany(cmd.Context(), client, restConfig.CompanyID, options.UserEmail, resources.IAMRole(options.IAMRole))
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
@@ -38,14 +38,21 @@ func RemoveCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Remove a user from a company",
 
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
+			if err := options.ConfirmDestructiveAction(fmt.Sprintf("The user %s will be removed from the company", options.EntityID)); err != nil {
+				return err
+			}
 			err = removeCompanyUser(cmd.Context(), client, restConfig.CompanyID, options.EntityID, options.KeepUserGroupMemeberships)
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
@@ -17,7 +17,6 @@ package company
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -33,49 +32,57 @@ const (
 
 // ListCmd return a new cobra command for listing companies
 func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List user companies",
 		Long: `List the companies that the current user can access.
 
-Companies can be used to logically group projects by organizations or internal teams.`,
+Companies can be used to logically group projects by organizations or internal teams.
+Only the first page of companies is shown by default, use the --all flag for listing all of them.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			printer := options.Printer()
-			return listCompanies(cmd.Context(), client, printer)
+			if err != nil {
+				return err
+			}
+			printer, err := options.Printer()
+			if err != nil {
+				return err
+			}
+			return listCompanies(cmd.Context(), client, options.PageOptions(), printer)
 		},
 	}
+
+	options.AddPaginationFlags(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
+	return cmd
 }
 
 // listCompanies retrieves the companies belonging to the current context
-func listCompanies(ctx context.Context, client *client.APIClient, p printer.IPrinter) error {
-	// execute the request
-	resp, err := client.Get().APIPath(listCompaniesEndpoint).Do(ctx)
+func listCompanies(ctx context.Context, apiClient *client.APIClient, pageOptions client.PageOptions, p printer.ListPrinter) error {
+	request := apiClient.Get().APIPath(listCompaniesEndpoint)
+	companies, err := client.ListPages[*resources.Company](ctx, client.NewPager(request, pageOptions))
 	if err != nil {
-		return fmt.Errorf("error executing request: %w", err)
-	}
-
-	if err := resp.Error(); err != nil {
 		return err
 	}
 
-	companies := make([]*resources.Company, 0)
-	if err := resp.ParseResponse(&companies); err != nil {
-		return fmt.Errorf("error parsing response body: %w", err)
-	}
+	return p.PrintList(printer.NewList(companies, companyName, printer.Table[*resources.Company]{
+		Headers: []string{"Name", "Company ID", "Git Provider", "Pipelines"},
+		Row:     rowForCompany,
+	}))
+}
 
-	p.Keys("Name", "Company ID", "Git Provider", "Pipelines")
-	for _, company := range companies {
-		repositoryType := company.Repository.Type
-		if repositoryType == "" {
-			repositoryType = "gitlab"
-		}
-		p.Record(company.Name, company.TenantID, repositoryType, company.Pipelines.Type)
-	}
+func companyName(company *resources.Company) string {
+	return company.TenantID
+}
 
-	p.Print()
-	return nil
+func rowForCompany(company *resources.Company) []string {
+	repositoryType := company.Repository.Type
+	if repositoryType == "" {
+		repositoryType = "gitlab"
+	}
+	return []string{company.Name, company.TenantID, repositoryType, company.Pipelines.Type}
 }
@@ -41,6 +41,7 @@ func TestListCompanies(t *testing.T) {
 		server       *httptest.Server
 		clientConfig *client.Config
 		companiesURI string
+		pageOptions  client.PageOptions
 		err          bool
 	}{
 		"valid get response": {
@@ -49,6 +50,21 @@ func TestListCompanies(t *testing.T) {
 				Transport: http.DefaultTransport,
 			},
 		},
+		"valid response fetching all pages": {
+			server: mockServer(t, true),
+			clientConfig: &client.Config{
+				Transport: http.DefaultTransport,
+			},
+			pageOptions: client.PageOptions{All: true},
+		},
+		"invalid page size": {
+			server: mockServer(t, true),
+			clientConfig: &client.Config{
+				Transport: http.DefaultTransport,
+			},
+			pageOptions: client.PageOptions{PageSize: -1},
+			err:         true,
+		},
 		"invalid body response": {
 			server: mockServer(t, false),
 			clientConfig: &client.Config{
@@ -62,9 +78,9 @@ func TestListCompanies(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			defer testCase.server.Close()
 			testCase.clientConfig.Host = testCase.server.URL
-			client, err := client.APIClientForConfig(testCase.clientConfig)
+			apiClient, err := client.APIClientForConfig(testCase.clientConfig)
 			require.NoError(t, err)
-			err = listCompanies(t.Context(), client, &printer.NopPrinter{})
+			err = listCompanies(t.Context(), apiClient, testCase.pageOptions, &printer.NopPrinter{})
 			if testCase.err {
 				assert.Error(t, err)
 			} else {
@@ -109,7 +125,7 @@ func mockServer(t *testing.T, validResponse bool) *httptest.Server {
 	}
 ]`
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.RequestURI != listCompaniesEndpoint && r.Method != http.MethodGet {
+		if r.URL.Path != listCompaniesEndpoint && r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusNotFound)
 			assert.Fail(t, "unsupported call")
 			return
@@ -41,29 +41,41 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" && restConfig.ProjectID == "" {
 				return ErrRequiredCompanyIDOrProjectID
 			}
 
+			printer, err := options.Printer(clioptions.DisableWrapLines(true))
+			if err != nil {
+				return err
+			}
+
 			if restConfig.ProjectID != "" {
 				rules, err := New(client).ListProjectRules(cmd.Context(), restConfig.ProjectID)
-				cobra.CheckErr(err)
-				printProjectList(rules, options.Printer(clioptions.DisableWrapLines(true)))
-				return nil
+				if err != nil {
+					return err
+				}
+				return printProjectList(rules, printer)
 			}
 
 			rules, err := New(client).ListTenantRules(cmd.Context(), restConfig.CompanyID)
-			cobra.CheckErr(err)
-			printTenantList(rules, options.Printer(clioptions.DisableWrapLines(true)))
-			return nil
+			if err != nil {
+				return err
+			}
+			return printTenantList(rules, printer)
 		},
 	}
 
+	options.AddListOutputFlag(cmd.Flags())
 	return cmd
 }
 
@@ -92,33 +104,49 @@ func createRecord(disallowedRules []rulesentities.RuleSet, allowedRules []rulese
 	return ruleInfo
 }
 
-func printTenantList(rules []*rulesentities.SaveChangesRules, p printer.IPrinter) {
-	tableColumnLabel := []string{"#", "Roles", "Ruleset"}
-
-	p.Keys(tableColumnLabel...)
-	for i, rule := range rules {
-		ruleInfo := createRecord(rule.DisallowedRuleSet, rule.AllowedRuleSet)
-		p.Record(
-			strconv.Itoa(i),
-			strings.Join(rule.RoleIDs, ", "),
-			strings.Join(ruleInfo, ", "),
-		)
+func printTenantList(rules []*rulesentities.SaveChangesRules, p printer.ListPrinter) error {
+	positions := rulePositions(rules)
+	return p.PrintList(printer.NewList(rules, positionOf(positions), printer.Table[*rulesentities.SaveChangesRules]{
+		Headers: []string{"#", "Roles", "Ruleset"},
+		Row: func(rule *rulesentities.SaveChangesRules) []string {
+			ruleInfo := createRecord(rule.DisallowedRuleSet, rule.AllowedRuleSet)
+			return []string{
+				positions[rule],
+				strings.Join(rule.RoleIDs, ", "),
+				strings.Join(ruleInfo, ", "),
+			}
+		},
+	}))
+}
+
+func printProjectList(rules []*rulesentities.ProjectSaveChangesRules, p printer.ListPrinter) error {
+	positions := rulePositions(rules)
+	return p.PrintList(printer.NewList(rules, positionOf(positions), printer.Table[*rulesentities.ProjectSaveChangesRules]{
+		Headers: []string{"#", "Roles", "Ruleset", "Inherited"},
+		Row: func(rule *rulesentities.ProjectSaveChangesRules) []string {
+			ruleInfo := createRecord(rule.DisallowedRuleSet, rule.AllowedRuleSet)
+			return []string{
+				positions[rule],
+				strings.Join(rule.RoleIDs, ", "),
+				strings.Join(ruleInfo, ", "),
+				strconv.FormatBool(rule.IsInheritedFromTenant),
+			}
+		},
+	}))
+}
+
+// rulePositions return the position of every rule in the list, the rules have no id so it is used as their
+// identifier in the table and by the name output
+func rulePositions[T any](rules []*T) map[*T]string {
+	positions := make(map[*T]string, len(rules))
+	for index, rule := range rules {
+		positions[rule] = strconv.Itoa(index)
 	}
-	p.Print()
+	return positions
 }
 
-func printProjectList(rules []*rulesentities.ProjectSaveChangesRules, p printer.IPrinter) {
-	tableColumnLabel := []string{"#", "Roles", "Ruleset", "Inherited"}
-
-	p.Keys(tableColumnLabel...)
-	for i, rule := range rules {
-		ruleInfo := createRecord(rule.DisallowedRuleSet, rule.AllowedRuleSet)
-		p.Record(
-			strconv.Itoa(i),
-			strings.Join(rule.RoleIDs, ", "),
-			strings.Join(ruleInfo, ", "),
-			strconv.FormatBool(rule.IsInheritedFromTenant),
-		)
+func positionOf[T any](positions map[*T]string) func(*T) string {
+	return func(rule *T) string {
+		return positions[rule]
 	}
-	p.Print()
 }
@@ -34,24 +34,34 @@ func UpdateRules(o *clioptions.CLIOptions) *cobra.Command {
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" && restConfig.ProjectID == "" {
 				return ErrRequiredCompanyIDOrProjectID
 			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			rules, err := readFile(o.InputFilePath)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.ProjectID != "" {
 				err = New(client).UpdateProjectRules(cmd.Context(), restConfig.ProjectID, rules)
-				cobra.CheckErr(err)
+				if err != nil {
+					return err
+				}
 			} else {
 				err = New(client).UpdateTenantRules(cmd.Context(), restConfig.CompanyID, rules)
-				cobra.CheckErr(err)
+				if err != nil {
+					return err
+				}
 			}
 
 			fmt.Printf("Rules updated successfully")
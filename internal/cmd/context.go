@@ -30,8 +30,9 @@ func ContextCmd(options *clioptions.CLIOptions) *cobra.Command {
 with this order:
 
  1. if the --config flag is used that file will be selected
- 2. if the $MIACTL_CONFIG environment is set with a valid path the context will be read and write
-back in that file
+ 2. if the $MIACONFIG environment is set with a list of valid paths, contexts and credentials will be
+merged from all the files, the first file that defines an entry wins. Changes are written back in the
+file that has defined the modified entry, or in the first file for new entries
  3. if the $XDG_CONFIG_HOME environment the $XDG_CONFIG_HOME/miactl/config file is used
  4. lastly if nothing of the precedent rules apply the path $HOME/.config/miactl/config is used
 `,
@@ -42,8 +43,15 @@ back in that file
 	cmd.AddCommand(
 		context.AuthCmd(options),
 		context.SetCmd(options),
+		context.SetPreferencesCmd(options),
 		context.UseCmd(options),
 		context.ListCmd(options),
+		context.DeleteCmd(options),
+		context.RenameCmd(options),
+		context.CopyCmd(options),
+		context.ViewCmd(options),
+		context.MigrateCmd(options),
+		context.CheckCmd(options),
 	)
 
 	return cmd
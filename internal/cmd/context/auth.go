@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"dario.cat/mergo"
 	"github.com/spf13/cobra"
@@ -35,7 +37,24 @@ func AuthCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Use:   "auth NAME",
 		Short: "Set an auth configuration for miactl",
 		Long: `Set an auth configuration for miactl. You can set service account access
-and then attach it to one or more contexts.`,
+and then attach it to one or more contexts.
+
+Instead of storing the service account secrets in the config file you can use an exec
+credential plugin: a command that will print on its standard output a json document like
+the following one, that miactl will use as access token until its expiration.
+
+{
+  "kind": "ExecCredential",
+  "status": {
+    "token": "access-token",
+    "expirationTimestamp": "2024-01-01T00:00:00Z"
+  }
+}
+
+A jwt service account private key can be kept outside the config file using the
+--jwt-private-key-file flag: only the path of the pem file will be saved, and the key will be read
+when a new access token is needed. The file can contain an encrypted PKCS#8 key, its passphrase
+is read from the MIACTL_JWT_PRIVATE_KEY_PASSPHRASE environment variable or asked on the terminal when needed.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			authName := args[0]
@@ -59,19 +78,35 @@ and then attach it to one or more contexts.`,
 	options.AddContextAuthFlags(flags)
 
 	// add sub commands
+	cmd.AddCommand(
+		AuthListCmd(options),
+		AuthDeleteCmd(options),
+	)
 
 	return cmd
 }
 
 func setAuth(authName string, opts *clioptions.CLIOptions) (bool, error) {
-	if len(opts.JWTJsonPath) > 0 && (len(opts.BasicClientID) > 0 || len(opts.BasicClientSecret) > 0) {
+	hasBasic := len(opts.BasicClientID) > 0 || len(opts.BasicClientSecret) > 0
+	if len(opts.JWTJsonPath) > 0 && hasBasic {
 		return false, errors.New("is not possible to set both jwt and basic service account configs")
 	}
 
+	if len(opts.JWTPrivateKeyFile) > 0 && len(opts.JWTJsonPath) == 0 {
+		return false, errors.New("the private key file can be set only with a jwt service account config")
+	}
+
+	if len(opts.ExecCommand) > 0 && (len(opts.JWTJsonPath) > 0 || hasBasic) {
+		return false, errors.New("is not possible to set both exec and service account configs")
+	}
+
 	locator := cliconfig.NewConfigPathLocator()
 	locator.ExplicitPath = opts.MiactlConfig
+	if len(opts.ExecCommand) > 0 {
+		return saveExecCredential(authName, opts.ExecCommand, opts.ExecArgs, opts.ExecEnv, locator)
+	}
 	if len(opts.JWTJsonPath) > 0 {
-		return saveJWTServiceAccount(authName, opts.JWTJsonPath, locator)
+		return saveJWTServiceAccount(authName, opts.JWTJsonPath, opts.JWTPrivateKeyFile, locator)
 	}
 	return saveBasicServiceAccount(authName, opts.BasicClientID, opts.BasicClientSecret, locator)
 }
@@ -87,6 +122,7 @@ func saveBasicServiceAccount(name, clientID, clientSecret string, locator *clico
 		ClientSecret:      clientSecret,
 		JWTKeyID:          "",
 		JWTPrivateKeyData: "",
+		JWTPrivateKeyFile: "",
 	}
 
 	authConfig, found := config.Auth[name]
@@ -97,6 +133,7 @@ func saveBasicServiceAccount(name, clientID, clientSecret string, locator *clico
 	if err := mergo.Merge(authConfig, newAuth, mergo.WithOverride); err != nil {
 		return false, err
 	}
+	authConfig.Exec = nil
 
 	if config.Auth != nil {
 		config.Auth[name] = authConfig
@@ -109,7 +146,9 @@ func saveBasicServiceAccount(name, clientID, clientSecret string, locator *clico
 	return found, locator.WriteConfig(config)
 }
 
-func saveJWTServiceAccount(name, jwtJSONPath string, locator *cliconfig.ConfigPathLocator) (bool, error) {
+// saveJWTServiceAccount save the jwt service account found at jwtJSONPath, if privateKeyFile is set only its
+// path is saved in place of the private key data
+func saveJWTServiceAccount(name, jwtJSONPath, privateKeyFile string, locator *cliconfig.ConfigPathLocator) (bool, error) {
 	fileData, err := os.ReadFile(jwtJSONPath)
 	if err != nil {
 		return false, err
@@ -136,11 +175,60 @@ func saveJWTServiceAccount(name, jwtJSONPath string, locator *cliconfig.ConfigPa
 		ClientSecret:      "",
 		JWTKeyID:          jwtServiceAccount.KeyID,
 		JWTPrivateKeyData: jwtServiceAccount.PrivateKeyData,
+		JWTPrivateKeyFile: "",
+	}
+
+	if len(privateKeyFile) > 0 {
+		absPath, err := filepath.Abs(privateKeyFile)
+		if err != nil {
+			return false, err
+		}
+		newAuth.JWTPrivateKeyData = ""
+		newAuth.JWTPrivateKeyFile = absPath
 	}
 
 	if err := mergo.Merge(authConfig, newAuth, mergo.WithOverride); err != nil {
 		return false, err
 	}
+	// the merge skip empty values, only one between the key data and the key file must remain set
+	authConfig.JWTPrivateKeyData = newAuth.JWTPrivateKeyData
+	authConfig.JWTPrivateKeyFile = newAuth.JWTPrivateKeyFile
+	authConfig.Exec = nil
+
+	if config.Auth != nil {
+		config.Auth[name] = authConfig
+	} else {
+		config.Auth = map[string]*api.AuthConfig{
+			name: authConfig,
+		}
+	}
+
+	return found, locator.WriteConfig(config)
+}
+
+func saveExecCredential(name, command string, args, env []string, locator *cliconfig.ConfigPathLocator) (bool, error) {
+	envVars := make([]api.ExecEnvVar, 0, len(env))
+	for _, envVar := range env {
+		key, value, found := strings.Cut(envVar, "=")
+		if !found || len(key) == 0 {
+			return false, fmt.Errorf("invalid exec environment variable \"%s\", must be in the form key=value", envVar)
+		}
+		envVars = append(envVars, api.ExecEnvVar{Name: key, Value: value})
+	}
+
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	_, found := config.Auth[name]
+	authConfig := &api.AuthConfig{
+		Exec: &api.ExecConfig{
+			Command: command,
+			Args:    args,
+			Env:     envVars,
+		},
+	}
 
 	if config.Auth != nil {
 		config.Auth[name] = authConfig
@@ -0,0 +1,88 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func AuthDeleteCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete NAME [flags]",
+		Short: "Delete an auth configuration from the config file",
+		Long: `Delete an auth configuration from the config file. If one or more contexts are still
+using the credential the command will fail, unless the --force flag is used; in that case
+the credential will be also detached from these contexts.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+			authName := args[0]
+			if err := opts.ConfirmDestructiveAction(fmt.Sprintf("The credential %s will be deleted", authName)); err != nil {
+				return err
+			}
+			if err := deleteAuth(authName, opts.Force, locator); err != nil {
+				return err
+			}
+
+			fmt.Printf("Auth \"%s\" deleted.\n", authName)
+			return nil
+		},
+		ValidArgsFunction: authsCompletion(opts),
+	}
+
+	flags := cmd.Flags()
+	opts.AddDeleteAuthFlags(flags)
+
+	return cmd
+}
+
+func deleteAuth(authName string, force bool, locator *cliconfig.ConfigPathLocator) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, found := config.Auth[authName]; !found {
+		return fmt.Errorf("no auth named \"%s\" exists", authName)
+	}
+
+	usedBy := make([]string, 0)
+	for contextName, contextConfig := range config.Contexts {
+		if contextConfig != nil && contextConfig.AuthName == authName {
+			usedBy = append(usedBy, contextName)
+		}
+	}
+	sort.Strings(usedBy)
+
+	if len(usedBy) > 0 && !force {
+		return fmt.Errorf("auth \"%s\" is used by the contexts: %s; use --force to delete it anyway", authName, strings.Join(usedBy, ", "))
+	}
+
+	for _, contextName := range usedBy {
+		config.Contexts[contextName].AuthName = ""
+	}
+	delete(config.Auth, authName)
+
+	return locator.WriteConfig(config)
+}
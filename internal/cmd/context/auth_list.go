@@ -0,0 +1,83 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func AuthListCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [flags]",
+		Short: "List available auth configurations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+
+			return printAuths(cmd.OutOrStdout(), locator)
+		},
+	}
+
+	return cmd
+}
+
+func listAuths(config *api.Config) []string {
+	authNames := make([]string, 0, len(config.Auth))
+	for name := range config.Auth {
+		authNames = append(authNames, name)
+	}
+	sort.Strings(authNames)
+
+	return authNames
+}
+
+func printAuths(out io.Writer, locator *cliconfig.ConfigPathLocator) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range listAuths(config) {
+		fmt.Fprintln(out, name)
+	}
+	return nil
+}
+
+// authsCompletion return a completion function that will propose the auth names found
+// in the config file selected by opts
+func authsCompletion(opts *clioptions.CLIOptions) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		locator := cliconfig.NewConfigPathLocator()
+		locator.ExplicitPath = opts.MiactlConfig
+		config, err := locator.ReadConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return listAuths(config), cobra.ShellCompDirectiveNoFileComp
+	}
+}
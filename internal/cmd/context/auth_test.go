@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
 	"github.com/mia-platform/miactl/internal/clioptions"
 	"github.com/mia-platform/miactl/internal/resources"
 )
@@ -80,6 +82,15 @@ func TestSetAuth(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"config with both exec and basic set": {
+			authName:   "credentialTest",
+			configPath: copyFile(t, filepath.Join(testdata, "auth.yaml")),
+			options: &clioptions.CLIOptions{
+				BasicClientID: "id",
+				ExecCommand:   "command",
+			},
+			expectErr: true,
+		},
 	}
 
 	for testName, testCase := range testCases {
@@ -123,3 +134,93 @@ func TestJWTUser(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, override)
 }
+
+func TestJWTUserWithPrivateKeyFile(t *testing.T) {
+	tempDir := t.TempDir()
+	jwtFile := filepath.Join(tempDir, "jwt.json")
+	data, err := json.Marshal(&resources.JWTServiceAccountJSON{
+		Type:           "jwt",
+		KeyID:          "key-id",
+		PrivateKeyData: "data",
+		ClientID:       "client-id",
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(jwtFile, data, 0600))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	testdata := filepath.Join(wd, "testdata")
+	configFile := copyFile(t, filepath.Join(testdata, "auth.yaml"))
+
+	_, err = setAuth("jwt", &clioptions.CLIOptions{
+		MiactlConfig: configFile,
+		JWTJsonPath:  jwtFile,
+	})
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(tempDir, "key.pem")
+	override, err := setAuth("jwt", &clioptions.CLIOptions{
+		MiactlConfig:      configFile,
+		JWTJsonPath:       jwtFile,
+		JWTPrivateKeyFile: keyFile,
+	})
+	require.NoError(t, err)
+	assert.True(t, override)
+
+	locator := cliconfig.NewConfigPathLocator()
+	locator.ExplicitPath = configFile
+	config, err := locator.ReadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, &api.AuthConfig{
+		ClientID:          "client-id",
+		JWTKeyID:          "key-id",
+		JWTPrivateKeyFile: keyFile,
+	}, config.Auth["jwt"])
+
+	_, err = setAuth("jwt", &clioptions.CLIOptions{
+		MiactlConfig:      configFile,
+		JWTPrivateKeyFile: keyFile,
+	})
+	assert.Error(t, err)
+}
+
+func TestExecCredential(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	testdata := filepath.Join(wd, "testdata")
+
+	t.Run("save exec credential over basic one", func(t *testing.T) {
+		configFile := copyFile(t, filepath.Join(testdata, "auth.yaml"))
+		options := &clioptions.CLIOptions{
+			MiactlConfig: configFile,
+			ExecCommand:  "vault",
+			ExecArgs:     []string{"read", "secret"},
+			ExecEnv:      []string{"VAULT_ADDR=https://vault.example.com"},
+		}
+		override, err := setAuth("credential1", options)
+		require.NoError(t, err)
+		assert.True(t, override)
+
+		locator := cliconfig.NewConfigPathLocator()
+		locator.ExplicitPath = configFile
+		config, err := locator.ReadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, &api.AuthConfig{
+			Exec: &api.ExecConfig{
+				Command: "vault",
+				Args:    []string{"read", "secret"},
+				Env:     []api.ExecEnvVar{{Name: "VAULT_ADDR", Value: "https://vault.example.com"}},
+			},
+		}, config.Auth["credential1"])
+	})
+
+	t.Run("invalid env", func(t *testing.T) {
+		options := &clioptions.CLIOptions{
+			MiactlConfig: copyFile(t, filepath.Join(testdata, "auth.yaml")),
+			ExecCommand:  "vault",
+			ExecEnv:      []string{"VAULT_ADDR"},
+		}
+		_, err := setAuth("credential1", options)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,435 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/encoding"
+	"github.com/mia-platform/miactl/internal/printer"
+	"github.com/mia-platform/miactl/internal/resources"
+	"github.com/mia-platform/miactl/internal/transport"
+)
+
+const (
+	versionEndpoint             = "/api/version"
+	listCompaniesEndpoint       = "/api/backend/tenants/"
+	getProjectEndpointTemplate  = "/api/backend/projects/%s"
+	checkTLSHandshakeTimeout    = 10 * time.Second
+	checkStatusPass             = "pass"
+	checkStatusFail             = "fail"
+	checkStatusSkip             = "skip"
+	checkOutputTable            = "table"
+	defaultHTTPSPort            = "443"
+	certificateExpiryDateFormat = time.DateOnly
+)
+
+// checkResult is the outcome of a single step of the context check
+type checkResult struct {
+	Step    string `json:"step"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// checkState contains the values shared between the steps of the context check
+type checkState struct {
+	contextName string
+	config      *client.Config
+	configErr   error
+	apiClient   *client.APIClient
+	project     *resources.Project
+}
+
+type checkStep struct {
+	name string
+	run  func(ctx context.Context, state *checkState) (string, string)
+}
+
+var checkSteps = []checkStep{
+	{name: "context", run: checkContext},
+	{name: "tls", run: checkTLS},
+	{name: "version", run: checkVersion},
+	{name: "auth", run: checkAuth},
+	{name: "company", run: checkCompany},
+	{name: "project", run: checkProject},
+	{name: "environment", run: checkEnvironment},
+}
+
+func CheckCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [CONTEXT] [flags]",
+		Short: "Check the connection to the Console using a context",
+		Long: `Check the connection to the Console using a context, passed as argument or with the --context
+flag, the current one will be used if no context is passed.
+
+Every layer of the connection is checked in order: the TLS handshake with the configured certificate
+authority, the reachability of the Console, the authentication, and the existence of the configured
+company, project and environment. The command will stop and exit with an error at the first failing step.`,
+		Args: cobra.MatchAll(
+			cobra.MaximumNArgs(1),
+			contextArgOrFlag,
+		),
+		// a failing check is not a usage error, avoid to print the usage after the results
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state := &checkState{contextName: "current context"}
+			if len(args) > 0 {
+				opts.Context = args[0]
+			}
+			if len(opts.Context) > 0 {
+				state.contextName = opts.Context
+			}
+			state.config, state.configErr = opts.ToRESTConfig()
+
+			results := runChecks(cmd.Context(), state)
+			return printCheckResults(cmd.OutOrStdout(), results, opts.OutputFormat)
+		},
+		ValidArgsFunction: contextsCompletion(opts),
+	}
+
+	flags := cmd.Flags()
+	opts.AddContextCheckFlags(flags)
+
+	return cmd
+}
+
+// contextArgOrFlag return an error if the context is passed both as argument and with the context flag
+func contextArgOrFlag(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && cmd.Flags().Changed("context") {
+		return errors.New("the context can be passed as argument or with the --context flag, not both")
+	}
+	return nil
+}
+
+// runChecks run all the check steps in order, after the first failing step all the others are skipped
+func runChecks(ctx context.Context, state *checkState) []checkResult {
+	results := make([]checkResult, 0, len(checkSteps))
+	failed := false
+	for _, step := range checkSteps {
+		if failed {
+			results = append(results, checkResult{Step: step.name, Status: checkStatusSkip, Message: "previous step failed"})
+			continue
+		}
+
+		status, message := step.run(ctx, state)
+		failed = status == checkStatusFail
+		results = append(results, checkResult{Step: step.name, Status: status, Message: message})
+	}
+
+	return results
+}
+
+func printCheckResults(out io.Writer, results []checkResult, outputFormat string) error {
+	switch outputFormat {
+	case checkOutputTable:
+		p := printer.NewTablePrinter(printer.TablePrinterOptions{}, out)
+		p.Keys("Step", "Status", "Message")
+		for _, result := range results {
+			p.Record(result.Step, result.Status, result.Message)
+		}
+		p.Print()
+	default:
+		data, err := encoding.MarshalData(results, outputFormat, encoding.MarshalOptions{Indent: true})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	}
+
+	for _, result := range results {
+		if result.Status == checkStatusFail {
+			return fmt.Errorf("context check failed at the %s step", result.Step)
+		}
+	}
+	return nil
+}
+
+func checkContext(_ context.Context, state *checkState) (string, string) {
+	if state.configErr != nil {
+		return checkStatusFail, state.configErr.Error()
+	}
+
+	if len(state.config.Host) == 0 {
+		return checkStatusFail, "no endpoint configured"
+	}
+
+	return checkStatusPass, fmt.Sprintf("using %s with endpoint %s", state.contextName, state.config.Host)
+}
+
+func checkTLS(ctx context.Context, state *checkState) (string, string) {
+	endpoint, err := url.Parse(state.config.Host)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	if endpoint.Scheme != "https" {
+		return checkStatusSkip, "the endpoint is not using https"
+	}
+
+	transportConfig := &transport.Config{TLSConfig: tlsConfigForClient(state.config), Proxy: proxyConfigForClient(state.config)}
+	tlsConfig, err := transport.TLSConfigFor(transportConfig)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	clientCertificateMessage := ""
+	if len(tlsConfig.Certificates) > 0 && tlsConfig.Certificates[0].Leaf != nil {
+		notAfter := tlsConfig.Certificates[0].Leaf.NotAfter
+		if time.Now().After(notAfter) {
+			return checkStatusFail, fmt.Sprintf("client certificate expired on %s", notAfter.Format(certificateExpiryDateFormat))
+		}
+		clientCertificateMessage = fmt.Sprintf(", client certificate expires on %s", notAfter.Format(certificateExpiryDateFormat))
+	}
+
+	proxyFunc, err := transport.ProxyFuncFor(transportConfig.Proxy)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+	proxyURL, err := proxyFunc(&http.Request{URL: endpoint})
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	var connectionState *tls.ConnectionState
+	if proxyURL != nil {
+		connectionState, err = proxiedConnectionState(ctx, transportConfig, endpoint)
+	} else {
+		connectionState, err = directConnectionState(ctx, tlsConfig, endpoint)
+	}
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	message := fmt.Sprintf("%s handshake completed", tls.VersionName(connectionState.Version))
+	if proxyURL != nil {
+		message = fmt.Sprintf("%s through proxy %s", message, proxyURL.Redacted())
+	}
+	if len(connectionState.PeerCertificates) > 0 {
+		certificate := connectionState.PeerCertificates[0]
+		message = fmt.Sprintf("%s, server certificate expires on %s", message, certificate.NotAfter.Format(certificateExpiryDateFormat))
+	}
+	return checkStatusPass, message + clientCertificateMessage
+}
+
+// directConnectionState return the state of a TLS connection opened directly with the endpoint host
+func directConnectionState(ctx context.Context, tlsConfig *tls.Config, endpoint *url.URL) (*tls.ConnectionState, error) {
+	tlsConfig.ServerName = endpoint.Hostname()
+
+	port := endpoint.Port()
+	if len(port) == 0 {
+		port = defaultHTTPSPort
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: checkTLSHandshakeTimeout},
+		Config:    tlsConfig,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(endpoint.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	connectionState := conn.(*tls.Conn).ConnectionState()
+	return &connectionState, nil
+}
+
+// proxiedConnectionState return the state of the TLS connection used for a request sent to the endpoint
+// through the configured proxy
+func proxiedConnectionState(ctx context.Context, config *transport.Config, endpoint *url.URL) (*tls.ConnectionState, error) {
+	roundTripper, err := transport.NewTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTLSHandshakeTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := roundTripper.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.TLS == nil {
+		return nil, errors.New("no TLS connection has been established with the endpoint")
+	}
+	return response.TLS, nil
+}
+
+func checkVersion(ctx context.Context, state *checkState) (string, string) {
+	// the version endpoint is public, so use a client without authentication for checking only the reachability
+	roundTripper, err := transport.NewTransport(&transport.Config{
+		UserAgent: state.config.UserAgent,
+		TLSConfig: tlsConfigForClient(state.config),
+		Proxy:     proxyConfigForClient(state.config),
+	})
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	config := *state.config
+	config.Transport = roundTripper
+	apiClient, err := client.APIClientForConfig(&config)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	response, err := apiClient.Get().APIPath(versionEndpoint).Do(ctx)
+	switch {
+	case err != nil:
+		return checkStatusFail, err.Error()
+	case response.StatusCode() == http.StatusNotFound:
+		return checkStatusPass, "Console is reachable, but does not expose its version"
+	case response.Error() != nil:
+		return checkStatusFail, response.Error().Error()
+	}
+
+	version := new(resources.Version)
+	if err := response.ParseResponse(version); err != nil {
+		return checkStatusFail, fmt.Sprintf("failed to parse version response: %s", err)
+	}
+
+	return checkStatusPass, fmt.Sprintf("Console version %s", version.Version)
+}
+
+func checkAuth(ctx context.Context, state *checkState) (string, string) {
+	token, err := client.AccessTokenForConfig(ctx, state.config)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	// reuse the obtained token for all the following requests
+	config := *state.config
+	config.BearerToken = token
+	if state.apiClient, err = client.APIClientForConfig(&config); err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	return checkStatusPass, "access token obtained"
+}
+
+func checkCompany(ctx context.Context, state *checkState) (string, string) {
+	companyID := state.config.CompanyID
+	if len(companyID) == 0 {
+		return checkStatusSkip, "no company id configured"
+	}
+
+	response, err := state.apiClient.Get().APIPath(listCompaniesEndpoint).SetParam("search", companyID).Do(ctx)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+	if err := response.Error(); err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	companies := make([]*resources.Company, 0)
+	if err := response.ParseResponse(&companies); err != nil {
+		return checkStatusFail, fmt.Sprintf("failed to parse companies response: %s", err)
+	}
+
+	for _, company := range companies {
+		if company.TenantID == companyID {
+			return checkStatusPass, fmt.Sprintf("company %s found", company.Name)
+		}
+	}
+
+	return checkStatusFail, fmt.Sprintf("no company found with id %s", companyID)
+}
+
+func checkProject(ctx context.Context, state *checkState) (string, string) {
+	projectID := state.config.ProjectID
+	if len(projectID) == 0 {
+		return checkStatusSkip, "no project id configured"
+	}
+
+	response, err := state.apiClient.Get().APIPath(fmt.Sprintf(getProjectEndpointTemplate, projectID)).Do(ctx)
+	if err != nil {
+		return checkStatusFail, err.Error()
+	}
+	if err := response.Error(); err != nil {
+		return checkStatusFail, err.Error()
+	}
+
+	project := new(resources.Project)
+	if err := response.ParseResponse(project); err != nil {
+		return checkStatusFail, fmt.Sprintf("failed to parse project response: %s", err)
+	}
+
+	if companyID := state.config.CompanyID; len(companyID) > 0 && project.CompanyID != companyID {
+		return checkStatusFail, fmt.Sprintf("project %s does not belong to company %s", projectID, companyID)
+	}
+
+	state.project = project
+	return checkStatusPass, fmt.Sprintf("project %s found", project.Name)
+}
+
+func checkEnvironment(_ context.Context, state *checkState) (string, string) {
+	environment := state.config.Environment
+	switch {
+	case len(environment) == 0:
+		return checkStatusSkip, "no environment configured"
+	case state.project == nil:
+		return checkStatusSkip, "no project id configured"
+	}
+
+	for _, projectEnvironment := range state.project.Environments {
+		if projectEnvironment.EnvID == environment {
+			return checkStatusPass, fmt.Sprintf("environment %s found", projectEnvironment.DisplayName)
+		}
+	}
+
+	return checkStatusFail, fmt.Sprintf("no environment %s found in project %s", environment, state.project.Name)
+}
+
+// tlsConfigForClient return the transport TLS settings found in config
+func tlsConfigForClient(config *client.Config) transport.TLSConfig {
+	return transport.TLSConfig{
+		Insecure: config.Insecure,
+		CAFile:   config.CAFile,
+		CertFile: config.CertFile,
+		KeyFile:  config.KeyFile,
+		CertData: config.CertData,
+		KeyData:  config.KeyData,
+	}
+}
+
+// proxyConfigForClient return the transport proxy settings found in config
+func proxyConfigForClient(config *client.Config) transport.ProxyConfig {
+	return transport.ProxyConfig{
+		URL:     config.ProxyURL,
+		NoProxy: config.NoProxy,
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func checkTestServer(t *testing.T, useTLS bool) *httptest.Server {
+	t.Helper()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/version":
+			w.Write([]byte(`{"version":"14.0.0","major":"14","minor":"0"}`))
+		case r.URL.Path == "/api/backend/tenants/" && r.URL.Query().Get("search") == "company-id":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			w.Write([]byte(`[{"name":"Company","tenantId":"company-id"}]`))
+		case r.URL.Path == "/api/backend/tenants/":
+			w.Write([]byte(`[]`))
+		case r.URL.Path == "/api/backend/projects/project-id":
+			w.Write([]byte(`{"name":"Project","tenantId":"company-id","environments":[{"label":"Development","envId":"development"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode":404,"error":"Not Found","message":"not found"}`))
+		}
+	})
+
+	var server *httptest.Server
+	if useTLS {
+		server = httptest.NewTLSServer(handler)
+	} else {
+		server = httptest.NewServer(handler)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caData, 0600))
+	return caPath
+}
+
+func TestRunChecks(t *testing.T) {
+	tlsServer := checkTestServer(t, true)
+	caPath := writeServerCA(t, tlsServer)
+	server := checkTestServer(t, false)
+
+	testCases := map[string]struct {
+		config           *client.Config
+		configErr        error
+		expectedStatuses []string
+	}{
+		"all steps pass": {
+			config: &client.Config{
+				Host:            tlsServer.URL,
+				TLSClientConfig: client.TLSClientConfig{CAFile: caPath},
+				BearerToken:     "token",
+				CompanyID:       "company-id",
+				ProjectID:       "project-id",
+				Environment:     "development",
+			},
+			expectedStatuses: []string{"pass", "pass", "pass", "pass", "pass", "pass", "pass"},
+		},
+		"error reading the config": {
+			configErr:        errors.New("context not found"),
+			expectedStatuses: []string{"fail", "skip", "skip", "skip", "skip", "skip", "skip"},
+		},
+		"unknown certificate authority": {
+			config: &client.Config{
+				Host:        tlsServer.URL,
+				BearerToken: "token",
+			},
+			expectedStatuses: []string{"pass", "fail", "skip", "skip", "skip", "skip", "skip"},
+		},
+		"plain http and no ids": {
+			config: &client.Config{
+				Host:        server.URL,
+				BearerToken: "token",
+			},
+			expectedStatuses: []string{"pass", "skip", "pass", "pass", "skip", "skip", "skip"},
+		},
+		"missing company": {
+			config: &client.Config{
+				Host:        server.URL,
+				BearerToken: "token",
+				CompanyID:   "other-company",
+			},
+			expectedStatuses: []string{"pass", "skip", "pass", "pass", "fail", "skip", "skip"},
+		},
+		"missing project": {
+			config: &client.Config{
+				Host:        server.URL,
+				BearerToken: "token",
+				ProjectID:   "other-project",
+			},
+			expectedStatuses: []string{"pass", "skip", "pass", "pass", "skip", "fail", "skip"},
+		},
+		"missing environment": {
+			config: &client.Config{
+				Host:        server.URL,
+				BearerToken: "token",
+				CompanyID:   "company-id",
+				ProjectID:   "project-id",
+				Environment: "production",
+			},
+			expectedStatuses: []string{"pass", "skip", "pass", "pass", "pass", "pass", "fail"},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			state := &checkState{
+				contextName: "context",
+				config:      testCase.config,
+				configErr:   testCase.configErr,
+			}
+
+			results := runChecks(t.Context(), state)
+			statuses := make([]string, 0, len(results))
+			for _, result := range results {
+				statuses = append(statuses, result.Status)
+			}
+			assert.Equal(t, testCase.expectedStatuses, statuses, results)
+		})
+	}
+}
+
+func TestCheckTLSClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	caPath := writeServerCA(t, server)
+
+	expiration := time.Now().Add(24 * time.Hour)
+	certData, keyData := generateClientCertificate(t, expiration)
+	expiredCertData, expiredKeyData := generateClientCertificate(t, time.Now().Add(-time.Hour))
+
+	testCases := map[string]struct {
+		tlsConfig       client.TLSClientConfig
+		expectedStatus  string
+		expectedMessage string
+	}{
+		"valid client certificate": {
+			tlsConfig:       client.TLSClientConfig{CAFile: caPath, CertData: certData, KeyData: keyData},
+			expectedStatus:  checkStatusPass,
+			expectedMessage: "client certificate expires on " + expiration.Format(certificateExpiryDateFormat),
+		},
+		"expired client certificate": {
+			tlsConfig:       client.TLSClientConfig{CAFile: caPath, CertData: expiredCertData, KeyData: expiredKeyData},
+			expectedStatus:  checkStatusFail,
+			expectedMessage: "client certificate expired on",
+		},
+		"missing client key": {
+			tlsConfig:       client.TLSClientConfig{CAFile: caPath, CertData: certData},
+			expectedStatus:  checkStatusFail,
+			expectedMessage: "without the client key",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			state := &checkState{config: &client.Config{Host: server.URL, TLSClientConfig: testCase.tlsConfig}}
+			status, message := checkTLS(t.Context(), state)
+			assert.Equal(t, testCase.expectedStatus, status, message)
+			assert.Contains(t, message, testCase.expectedMessage)
+		})
+	}
+}
+
+// generateClientCertificate return a pem encoded self signed certificate expiring at notAfter and its key
+func generateClientCertificate(t *testing.T, notAfter time.Time) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "miactl"},
+		NotBefore:    notAfter.Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestPrintCheckResults(t *testing.T) {
+	passed := []checkResult{
+		{Step: "context", Status: "pass", Message: "using context"},
+	}
+	failed := []checkResult{
+		{Step: "context", Status: "pass", Message: "using context"},
+		{Step: "tls", Status: "fail", Message: "handshake error"},
+	}
+
+	testCases := map[string]struct {
+		results        []checkResult
+		outputFormat   string
+		expectedOutput string
+		expectErr      bool
+	}{
+		"table output": {
+			results:        passed,
+			outputFormat:   "table",
+			expectedOutput: "context  pass    using context",
+		},
+		"json output": {
+			results:      passed,
+			outputFormat: "json",
+			expectedOutput: `[
+  {
+    "step": "context",
+    "status": "pass",
+    "message": "using context"
+  }
+]`,
+		},
+		"failed step": {
+			results:        failed,
+			outputFormat:   "table",
+			expectedOutput: "tls      fail    handshake error",
+			expectErr:      true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			buffer := bytes.NewBuffer([]byte{})
+			err := printCheckResults(buffer, testCase.results, testCase.outputFormat)
+			if testCase.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Contains(t, buffer.String(), testCase.expectedOutput)
+		})
+	}
+}
+
+func TestCheckCmdContextArgs(t *testing.T) {
+	testCases := map[string]struct {
+		args      []string
+		expectErr bool
+	}{
+		"context as argument": {
+			args: []string{"production"},
+		},
+		"context flag": {
+			args: []string{"--context", "production"},
+		},
+		"context as argument and flag": {
+			args:      []string{"production", "--context", "staging"},
+			expectErr: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			cmd := CheckCmd(clioptions.NewCLIOptions())
+			require.NoError(t, cmd.ParseFlags(testCase.args))
+
+			err := cmd.ValidateArgs(cmd.Flags().Args())
+			if testCase.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func CopyCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy SOURCE DESTINATION [flags]",
+		Short: "Copy a context with a new name",
+		Long: `Copy a context with a new name. The new context will share all the settings of the source one,
+including the credential, and can then be modified with the set command.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+			source, destination := args[0], args[1]
+			if err := copyContext(source, destination, locator); err != nil {
+				return err
+			}
+
+			fmt.Printf("Context \"%s\" copied to \"%s\".\n", source, destination)
+			return nil
+		},
+		ValidArgsFunction: contextsCompletion(opts),
+	}
+
+	return cmd
+}
+
+func copyContext(source, destination string, locator *cliconfig.ConfigPathLocator) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	contextConfig, found := config.Contexts[source]
+	if !found {
+		return fmt.Errorf("no context named \"%s\" exists", source)
+	}
+
+	if _, found := config.Contexts[destination]; found {
+		return fmt.Errorf("a context named \"%s\" already exists", destination)
+	}
+
+	config.Contexts[destination] = contextConfig.DeepCopy()
+	return locator.WriteConfig(config)
+}
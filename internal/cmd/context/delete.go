@@ -0,0 +1,70 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func DeleteCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete CONTEXT [flags]",
+		Short: "Delete a context from the config file",
+		Long: `Delete a context from the config file. If the context is the current one,
+the current context will be unset.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+			contextName := args[0]
+			if err := opts.ConfirmDestructiveAction(fmt.Sprintf("The context %s will be deleted", contextName)); err != nil {
+				return err
+			}
+			if err := deleteContext(contextName, locator); err != nil {
+				return err
+			}
+
+			fmt.Printf("Context \"%s\" deleted.\n", contextName)
+			return nil
+		},
+		ValidArgsFunction: contextsCompletion(opts),
+	}
+
+	return cmd
+}
+
+func deleteContext(contextName string, locator *cliconfig.ConfigPathLocator) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, found := config.Contexts[contextName]; !found {
+		return fmt.Errorf("no context named \"%s\" exists", contextName)
+	}
+
+	delete(config.Contexts, contextName)
+	if config.CurrentContext == contextName {
+		config.CurrentContext = ""
+	}
+
+	return locator.WriteConfig(config)
+}
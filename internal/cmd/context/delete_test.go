@@ -0,0 +1,72 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+)
+
+func TestDeleteContext(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	testdata := filepath.Join(wd, "testdata", "config.yaml")
+
+	testCases := map[string]struct {
+		contextName            string
+		expectedCurrentContext string
+		expectErr              bool
+	}{
+		"delete context": {
+			contextName:            "context1",
+			expectedCurrentContext: "context2",
+		},
+		"delete current context": {
+			contextName:            "context2",
+			expectedCurrentContext: "",
+		},
+		"missing context": {
+			contextName: "foo",
+			expectErr:   true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = copyFile(t, testdata)
+
+			err := deleteContext(testCase.contextName, locator)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			config, err := locator.ReadConfig()
+			require.NoError(t, err)
+			assert.NotContains(t, config.Contexts, testCase.contextName)
+			assert.Len(t, config.Contexts, 2)
+			assert.Equal(t, testCase.expectedCurrentContext, config.CurrentContext)
+		})
+	}
+}
@@ -70,3 +70,20 @@ func printContexts(out io.Writer, locator *cliconfig.ConfigPathLocator) error {
 	}
 	return nil
 }
+
+// contextsCompletion return a completion function that will propose the context names found
+// in the config file selected by opts
+func contextsCompletion(opts *clioptions.CLIOptions) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		locator := cliconfig.NewConfigPathLocator()
+		locator.ExplicitPath = opts.MiactlConfig
+		config, err := locator.ReadConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return listContexts(config), cobra.ShellCompDirectiveNoFileComp
+	}
+}
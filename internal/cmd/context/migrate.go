@@ -0,0 +1,71 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func MigrateCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [flags]",
+		Short: "Upgrade the config files to the current format",
+		Long: `Upgrade the config files to the current format. The previous content of every
+upgraded file is saved alongside it with the .bak suffix.
+
+Config files written by older versions of miactl are upgraded automatically the first time
+they are read; use the --dry-run flag to see the result of the upgrade before it is made.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+
+			return migrateConfig(cmd.OutOrStdout(), locator, opts.DryRun)
+		},
+	}
+
+	opts.AddMigrateFlags(cmd.Flags())
+	return cmd
+}
+
+func migrateConfig(out io.Writer, locator *cliconfig.ConfigPathLocator, dryRun bool) error {
+	migrations, err := locator.MigrateConfig(dryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(migrations) == 0 {
+		fmt.Fprintln(out, "Config files are already up to date.")
+		return nil
+	}
+
+	for _, migration := range migrations {
+		if dryRun {
+			fmt.Fprintf(out, "Config file \"%s\" will be migrated from %s to %s:\n%s", migration.Path, migration.FromVersion, migration.ToVersion, string(migration.Data))
+			continue
+		}
+
+		fmt.Fprintf(out, "Config file \"%s\" migrated from %s to %s, backup saved in \"%s\".\n", migration.Path, migration.FromVersion, migration.ToVersion, migration.BackupPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	testDataFolder := filepath.Join(wd, "testdata")
+
+	testCases := map[string]struct {
+		testdata         string
+		dryRun           bool
+		expectedOutput   string
+		expectedMigrated bool
+	}{
+		"migrate legacy config": {
+			testdata:         "legacy-config.yaml",
+			expectedOutput:   "migrated from v0 to v1, backup saved in",
+			expectedMigrated: true,
+		},
+		"dry run legacy config": {
+			testdata:       "legacy-config.yaml",
+			dryRun:         true,
+			expectedOutput: "will be migrated from v0 to v1:\napiVersion: v1\nkind: Config\n",
+		},
+		"config already up to date": {
+			testdata:       "config.yaml",
+			expectedOutput: "Config files are already up to date.\n",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			configPath := copyFile(t, filepath.Join(testDataFolder, testCase.testdata))
+			originalData, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = configPath
+
+			buffer := bytes.NewBuffer([]byte{})
+			err = migrateConfig(buffer, locator, testCase.dryRun)
+			require.NoError(t, err)
+			assert.Contains(t, buffer.String(), testCase.expectedOutput)
+
+			configData, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			backupData, backupErr := os.ReadFile(configPath + ".bak")
+			if !testCase.expectedMigrated {
+				assert.Equal(t, originalData, configData)
+				assert.ErrorIs(t, backupErr, os.ErrNotExist)
+				return
+			}
+
+			require.NoError(t, backupErr)
+			assert.Equal(t, originalData, backupData)
+			assert.Contains(t, string(configData), "apiVersion: v1\nkind: Config\n")
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func RenameCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename OLD_NAME NEW_NAME [flags]",
+		Short: "Rename a context in the config file",
+		Long: `Rename a context in the config file. If the context is the current one,
+the current context will be updated to the new name.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+			oldName, newName := args[0], args[1]
+			if err := renameContext(oldName, newName, locator); err != nil {
+				return err
+			}
+
+			fmt.Printf("Context \"%s\" renamed to \"%s\".\n", oldName, newName)
+			return nil
+		},
+		ValidArgsFunction: contextsCompletion(opts),
+	}
+
+	return cmd
+}
+
+func renameContext(oldName, newName string, locator *cliconfig.ConfigPathLocator) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	contextConfig, found := config.Contexts[oldName]
+	if !found {
+		return fmt.Errorf("no context named \"%s\" exists", oldName)
+	}
+
+	if _, found := config.Contexts[newName]; found {
+		return fmt.Errorf("a context named \"%s\" already exists", newName)
+	}
+
+	delete(config.Contexts, oldName)
+	config.Contexts[newName] = contextConfig
+	if config.CurrentContext == oldName {
+		config.CurrentContext = newName
+	}
+
+	return locator.WriteConfig(config)
+}
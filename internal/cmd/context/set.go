@@ -17,12 +17,14 @@ package context
 
 import (
 	"fmt"
+	"reflect"
 
 	"dario.cat/mergo"
 	"github.com/spf13/cobra"
 
 	"github.com/mia-platform/miactl/internal/cliconfig"
 	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/clioptions"
 )
 
@@ -31,9 +33,14 @@ func SetCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Use:   "set CONTEXT [flags]",
 		Short: "Set a context for miactl",
 		Args:  cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			contextName := args[0]
-			modified, err := setContext(args[0], options)
+			preferences, err := options.ContextPreferencesFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			modified, err := setContext(args[0], options, preferences)
 			if err != nil {
 				return err
 			}
@@ -54,11 +61,12 @@ func SetCmd(options *clioptions.CLIOptions) *cobra.Command {
 	options.AddProjectFlags(flags)
 	options.AddEnvironmentFlags(flags)
 	options.AddAuthFlags(flags)
+	options.AddContextPreferencesFlags(flags)
 
 	return cmd
 }
 
-func setContext(contextName string, opts *clioptions.CLIOptions) (bool, error) {
+func setContext(contextName string, opts *clioptions.CLIOptions, preferences *api.Preferences) (bool, error) {
 	locator := cliconfig.NewConfigPathLocator()
 	locator.ExplicitPath = opts.MiactlConfig
 
@@ -75,6 +83,15 @@ func setContext(contextName string, opts *clioptions.CLIOptions) (bool, error) {
 		InsecureSkipTLSVerify: opts.Insecure,
 		AuthName:              opts.Auth,
 		Environment:           opts.Environment,
+		LoginMode:             opts.LoginMode,
+		ClientCertificate:     opts.ClientCertFile,
+		ClientKey:             opts.ClientKeyFile,
+		ProxyURL:              opts.ProxyURL,
+		NoProxy:               opts.NoProxy,
+	}
+
+	if err := client.ValidateLoginMode(opts.LoginMode); err != nil {
+		return false, err
 	}
 
 	contextConfig, found := config.Contexts[contextName]
@@ -86,6 +103,18 @@ func setContext(contextName string, opts *clioptions.CLIOptions) (bool, error) {
 		return false, err
 	}
 
+	// a file set with the flags replace the inline data previously saved
+	if len(opts.ClientCertFile) > 0 {
+		contextConfig.ClientCertificateData = ""
+	}
+	if len(opts.ClientKeyFile) > 0 {
+		contextConfig.ClientKeyData = ""
+	}
+
+	if contextConfig.Preferences, err = mergePreferences(contextConfig.Preferences, preferences); err != nil {
+		return false, err
+	}
+
 	if config.Contexts != nil {
 		config.Contexts[contextName] = contextConfig
 	} else {
@@ -96,3 +125,16 @@ func setContext(contextName string, opts *clioptions.CLIOptions) (bool, error) {
 
 	return found, locator.WriteConfig(config)
 }
+
+// mergePreferences return current with its values overridden by the ones set in preferences
+func mergePreferences(current, preferences *api.Preferences) (*api.Preferences, error) {
+	if preferences == nil || reflect.DeepEqual(*preferences, api.Preferences{}) {
+		return current, nil
+	}
+
+	if current == nil {
+		current = new(api.Preferences)
+	}
+
+	return current, mergo.Merge(current, preferences, mergo.WithOverride)
+}
@@ -0,0 +1,72 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/clioptions"
+)
+
+func SetPreferencesCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-preferences [flags]",
+		Short: "Set the preferences shared by all the contexts",
+		Long: `Set the preferences shared by all the contexts, saved at the top level of the config file.
+The preferences set in a context with the set command win over these ones.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			preferences, err := options.ContextPreferencesFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = options.MiactlConfig
+			if err := setGlobalPreferences(locator, preferences); err != nil {
+				return err
+			}
+
+			fmt.Println("Preferences modified.")
+			return nil
+		},
+	}
+
+	options.AddContextPreferencesFlags(cmd.Flags())
+	return cmd
+}
+
+func setGlobalPreferences(locator *cliconfig.ConfigPathLocator, preferences *api.Preferences) error {
+	if preferences == nil || reflect.DeepEqual(*preferences, api.Preferences{}) {
+		return errors.New("no preference to set, use one of the flags of the command")
+	}
+
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if config.Preferences, err = mergePreferences(config.Preferences, preferences); err != nil {
+		return err
+	}
+	return locator.WriteConfig(config)
+}
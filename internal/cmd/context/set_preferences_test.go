@@ -0,0 +1,74 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+)
+
+func TestSetGlobalPreferences(t *testing.T) {
+	falseValue := false
+	testCases := map[string]struct {
+		configPath          string
+		preferences         *api.Preferences
+		expectedPreferences *api.Preferences
+		expectErr           bool
+	}{
+		"empty file": {
+			configPath:          filepath.Join(t.TempDir(), "empty"),
+			preferences:         &api.Preferences{OutputFormat: "yaml"},
+			expectedPreferences: &api.Preferences{OutputFormat: "yaml"},
+		},
+		"merge with the existing preferences": {
+			configPath:          copyFile(t, filepath.Join("testdata", "config-with-preferences.yaml")),
+			preferences:         &api.Preferences{Revision: "main"},
+			expectedPreferences: &api.Preferences{OutputFormat: "json", Revision: "main", WrapLines: &falseValue},
+		},
+		"no preference": {
+			configPath:  filepath.Join(t.TempDir(), "empty"),
+			preferences: &api.Preferences{},
+			expectErr:   true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = testCase.configPath
+
+			err := setGlobalPreferences(locator, testCase.preferences)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			config, err := locator.ReadConfig()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedPreferences, config.Preferences)
+			if len(config.Contexts) > 0 {
+				assert.Equal(t, "yaml", config.Contexts["context1"].Preferences.OutputFormat)
+			}
+		})
+	}
+}
@@ -23,18 +23,26 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
 	"github.com/mia-platform/miactl/internal/clioptions"
 )
 
 func TestSetContext(t *testing.T) {
+	trueValue := true
+	falseValue := false
 	wd, err := os.Getwd()
 	require.NoError(t, err)
 	testdata := filepath.Join(wd, "testdata")
 	testCases := map[string]struct {
-		configPath     string
-		contextName    string
-		options        *clioptions.CLIOptions
-		expectOverride bool
+		configPath          string
+		contextName         string
+		options             *clioptions.CLIOptions
+		preferences         *api.Preferences
+		expectOverride      bool
+		expectedPreferences *api.Preferences
+		expectedLoginMode   string
+		expectErr           bool
 	}{
 		"empty file": {
 			contextName: "context",
@@ -58,6 +66,53 @@ func TestSetContext(t *testing.T) {
 			},
 			expectOverride: true,
 		},
+		"set preferences": {
+			contextName: "context1",
+			configPath:  copyFile(t, filepath.Join(testdata, "config.yaml")),
+			options:     &clioptions.CLIOptions{},
+			preferences: &api.Preferences{
+				OutputFormat: "yaml",
+				WrapLines:    &falseValue,
+			},
+			expectOverride: true,
+			expectedPreferences: &api.Preferences{
+				OutputFormat: "yaml",
+				WrapLines:    &falseValue,
+			},
+		},
+		"merge preferences": {
+			contextName: "context1",
+			configPath:  copyFile(t, filepath.Join(testdata, "config-with-preferences.yaml")),
+			options:     &clioptions.CLIOptions{},
+			preferences: &api.Preferences{
+				Revision:                  "main",
+				ConfirmDestructiveActions: &trueValue,
+			},
+			expectOverride: true,
+			expectedPreferences: &api.Preferences{
+				OutputFormat:              "yaml",
+				Revision:                  "main",
+				DeployType:                "deploy_all",
+				ConfirmDestructiveActions: &trueValue,
+			},
+		},
+		"set login mode": {
+			contextName: "context1",
+			configPath:  copyFile(t, filepath.Join(testdata, "config.yaml")),
+			options: &clioptions.CLIOptions{
+				LoginMode: "device",
+			},
+			expectOverride:    true,
+			expectedLoginMode: "device",
+		},
+		"invalid login mode": {
+			contextName: "context1",
+			configPath:  copyFile(t, filepath.Join(testdata, "config.yaml")),
+			options: &clioptions.CLIOptions{
+				LoginMode: "carrier-pigeon",
+			},
+			expectErr: true,
+		},
 		"config with only auth": {
 			contextName: "contextTest",
 			configPath:  copyFile(t, filepath.Join(testdata, "auth.yaml")),
@@ -72,9 +127,20 @@ func TestSetContext(t *testing.T) {
 			tempFile := testCase.configPath
 
 			testCase.options.MiactlConfig = tempFile
-			override, err := setContext(testCase.contextName, testCase.options)
+			override, err := setContext(testCase.contextName, testCase.options, testCase.preferences)
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
 			assert.NoError(t, err)
 			assert.Equal(t, testCase.expectOverride, override)
+
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = tempFile
+			config, err := locator.ReadConfig()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedPreferences, config.Contexts[testCase.contextName].Preferences)
+			assert.Equal(t, testCase.expectedLoginMode, config.Contexts[testCase.contextName].LoginMode)
 		})
 	}
 }
@@ -0,0 +1,155 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/cliconfig"
+	"github.com/mia-platform/miactl/internal/cliconfig/api"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/encoding"
+)
+
+const redactedValue = "REDACTED"
+
+func ViewCmd(opts *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view [flags]",
+		Short: "Display the current configuration",
+		Long: `Display the current configuration. Client secrets, private keys and proxy passwords
+will be redacted from the output.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			locator := cliconfig.NewConfigPathLocator()
+			locator.ExplicitPath = opts.MiactlConfig
+
+			return printConfig(cmd.OutOrStdout(), locator, opts.OutputFormat, opts.Minify)
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.AddContextViewFlags(flags)
+
+	return cmd
+}
+
+func printConfig(out io.Writer, locator *cliconfig.ConfigPathLocator, outputFormat string, minify bool) error {
+	config, err := locator.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	if minify {
+		if config, err = minifiedConfig(config); err != nil {
+			return err
+		}
+	}
+
+	data, err := encoding.MarshalData(redactedConfig(config), outputFormat, encoding.MarshalOptions{Indent: true})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+// minifiedConfig return a new config containing only the current context and the credential attached to it
+func minifiedConfig(config *api.Config) (*api.Config, error) {
+	currentContext := config.CurrentContext
+	if len(currentContext) == 0 {
+		return nil, errors.New("current context is not set")
+	}
+
+	contextConfig, found := config.Contexts[currentContext]
+	if !found {
+		return nil, fmt.Errorf("no context named \"%s\" exists", currentContext)
+	}
+
+	minified := api.NewConfig()
+	minified.CurrentContext = currentContext
+	minified.Contexts[currentContext] = contextConfig
+	if authConfig, found := config.Auth[contextConfig.AuthName]; found {
+		minified.Auth[contextConfig.AuthName] = authConfig
+	}
+
+	return minified, nil
+}
+
+// redactedConfig return a copy of config with all the secret values replaced
+func redactedConfig(config *api.Config) *api.Config {
+	redacted := config.DeepCopy()
+	for _, contextConfig := range redacted.Contexts {
+		if contextConfig == nil {
+			continue
+		}
+		if len(contextConfig.ClientKeyData) > 0 {
+			contextConfig.ClientKeyData = redactedValue
+		}
+		contextConfig.ProxyURL = redactedProxyURL(contextConfig.ProxyURL)
+	}
+
+	for _, authConfig := range redacted.Auth {
+		if authConfig == nil {
+			continue
+		}
+		if len(authConfig.ClientSecret) > 0 {
+			authConfig.ClientSecret = redactedValue
+		}
+		if len(authConfig.JWTPrivateKeyData) > 0 {
+			authConfig.JWTPrivateKeyData = redactedValue
+		}
+		redactExecEnv(authConfig.Exec)
+	}
+
+	if redacted.CacheEncryption != nil {
+		redactExecEnv(redacted.CacheEncryption.KeyExec)
+	}
+
+	return redacted
+}
+
+// redactExecEnv replace the values of the environment variables of exec, they often contain credentials
+func redactExecEnv(exec *api.ExecConfig) {
+	if exec == nil {
+		return
+	}
+
+	for index := range exec.Env {
+		if len(exec.Env[index].Value) > 0 {
+			exec.Env[index].Value = redactedValue
+		}
+	}
+}
+
+// redactedProxyURL return proxyURL with the password replaced, if it contains one
+func redactedProxyURL(proxyURL string) string {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.User == nil {
+		return proxyURL
+	}
+
+	if _, found := parsed.User.Password(); found {
+		parsed.User = url.UserPassword(parsed.User.Username(), redactedValue)
+	}
+	return parsed.String()
+}
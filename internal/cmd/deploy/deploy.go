@@ -34,6 +34,7 @@ Can trigger deployments to specific environments and monitor their status.`,
 		triggerCmd(options),
 		newStatusAddCmd(options),
 		latestDeploymentCmd(options),
+		historyCmd(options),
 	)
 
 	return cmd
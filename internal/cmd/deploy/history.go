@@ -0,0 +1,109 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/printer"
+	"github.com/mia-platform/miactl/internal/resources"
+	"github.com/mia-platform/miactl/internal/util"
+)
+
+func historyCmd(options *clioptions.CLIOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the deployments of the project",
+		Long: `List the deployments of the project, starting from the most recent one.
+
+The deployments can be filtered by environment with the --environment flag.
+Only the first page of deployments is shown by default, use the --all flag for
+listing all of them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			restConfig, err := options.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+			client, err := client.APIClientForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+			printer, err := options.Printer()
+			if err != nil {
+				return err
+			}
+			return listDeployments(cmd.Context(), client, restConfig.ProjectID, options.Environment, options.PageOptions(), printer)
+		},
+	}
+
+	flags := cmd.Flags()
+	options.AddConnectionFlags(flags)
+	options.AddContextFlags(flags)
+	options.AddCompanyFlags(flags)
+	options.AddProjectFlags(flags)
+	options.AddEnvironmentFlags(flags)
+	options.AddPaginationFlags(flags)
+	options.AddListOutputFlag(flags)
+
+	return cmd
+}
+
+// listDeployments print the deployments of projectID, filtered by environment if not empty
+func listDeployments(ctx context.Context, apiClient *client.APIClient, projectID, environment string, pageOptions client.PageOptions, p printer.ListPrinter) error {
+	if len(projectID) == 0 {
+		return errors.New("missing project id, please set one with the flag or context")
+	}
+
+	request := apiClient.
+		Get().
+		APIPath(fmt.Sprintf(deploymentsEndpointTemplate, projectID))
+	if len(environment) > 0 {
+		request.SetParam("environment", environment)
+	}
+
+	deployments, err := client.ListPages[resources.DeploymentHistory](ctx, client.NewPager(request, pageOptions))
+	if err != nil {
+		return err
+	}
+
+	return p.PrintList(printer.NewList(deployments, deploymentName, printer.Table[resources.DeploymentHistory]{
+		Headers:     []string{"ID", "Environment", "Ref", "Status", "Finished"},
+		WideHeaders: []string{"Pipeline ID", "Finished At"},
+		Row:         rowForDeployment,
+	}))
+}
+
+func deploymentName(deployment resources.DeploymentHistory) string {
+	return deployment.ID
+}
+
+func rowForDeployment(deployment resources.DeploymentHistory) []string {
+	finished := "-"
+	finishedAt := "-"
+	if !deployment.FinishedAt.IsZero() {
+		finished = fmt.Sprintf("%s ago", util.HumanDuration(time.Since(deployment.FinishedAt)))
+		finishedAt = deployment.FinishedAt.Format(time.RFC3339)
+	}
+	return []string{deployment.ID, deployment.Environment, deployment.Ref, deployment.Status, finished, deployment.PipelineID, finishedAt}
+}
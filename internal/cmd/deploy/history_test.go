@@ -0,0 +1,113 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+	"github.com/mia-platform/miactl/internal/printer"
+	"github.com/mia-platform/miactl/internal/resources"
+)
+
+func TestListDeployments(t *testing.T) {
+	testProjectID := "test-project-id"
+	deployments := []resources.DeploymentHistory{
+		{ID: "deploy-3", Ref: "main", Status: "running", Environment: "dev"},
+		{ID: "deploy-2", Ref: "main", Status: "success", Environment: "dev", FinishedAt: time.Now().Add(-time.Hour)},
+		{ID: "deploy-1", Ref: "v1.0.0", Status: "failed", Environment: "dev", FinishedAt: time.Now().Add(-2 * time.Hour)},
+	}
+
+	testCases := map[string]struct {
+		projectID        string
+		environment      string
+		pageOptions      client.PageOptions
+		expectedPages    []string
+		expectedIDs      []string
+		notExpectedIDs   []string
+		expectedErrorMsg string
+	}{
+		"first page": {
+			projectID:      testProjectID,
+			pageOptions:    client.PageOptions{Page: 1, PageSize: 2},
+			expectedPages:  []string{"1"},
+			expectedIDs:    []string{"deploy-3", "deploy-2", "60m ago"},
+			notExpectedIDs: []string{"deploy-1"},
+		},
+		"all pages for an environment": {
+			projectID:     testProjectID,
+			environment:   "dev",
+			pageOptions:   client.PageOptions{All: true, PageSize: 2},
+			expectedPages: []string{"1", "2"},
+			expectedIDs:   []string{"deploy-3", "deploy-2", "deploy-1"},
+		},
+		"missing project": {
+			expectedErrorMsg: "missing project id, please set one with the flag or context",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			requestedPages := make([]string, 0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, fmt.Sprintf("/api/deploy/projects/%s/deployment/", testProjectID), r.URL.Path)
+				assert.Equal(t, testCase.environment, r.URL.Query().Get("environment"))
+
+				page, err := strconv.Atoi(r.URL.Query().Get("page"))
+				require.NoError(t, err)
+				pageSize, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+				require.NoError(t, err)
+				requestedPages = append(requestedPages, r.URL.Query().Get("page"))
+
+				start := min((page-1)*pageSize, len(deployments))
+				data, err := resources.EncodeResourceToJSON(deployments[start:min(start+pageSize, len(deployments))])
+				require.NoError(t, err)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(data)
+			}))
+			defer server.Close()
+
+			apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL})
+			require.NoError(t, err)
+
+			output := &strings.Builder{}
+			tablePrinter := printer.NewTablePrinter(printer.TablePrinterOptions{}, output)
+			err = listDeployments(t.Context(), apiClient, testCase.projectID, testCase.environment, testCase.pageOptions, tablePrinter)
+			if len(testCase.expectedErrorMsg) > 0 {
+				assert.EqualError(t, err, testCase.expectedErrorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedPages, requestedPages)
+			for _, expected := range testCase.expectedIDs {
+				assert.Contains(t, output.String(), expected)
+			}
+			for _, notExpected := range testCase.notExpectedIDs {
+				assert.NotContains(t, output.String(), notExpected)
+			}
+		})
+	}
+}
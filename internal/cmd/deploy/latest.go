@@ -28,7 +28,7 @@ import (
 )
 
 const (
-	deploymentsLatestEndpointTemplate = "/api/deploy/projects/%s/deployment/"
+	deploymentsEndpointTemplate = "/api/deploy/projects/%s/deployment/"
 )
 
 func latestDeploymentCmd(options *clioptions.CLIOptions) *cobra.Command {
@@ -62,33 +62,24 @@ func runLatestDeployment(ctx context.Context, options *clioptions.CLIOptions) er
 		return errors.New("projectId is required")
 	}
 
-	client, err := client.APIClientForConfig(restConfig)
+	apiClient, err := client.APIClientForConfig(restConfig)
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.
+	request := apiClient.
 		Get().
-		APIPath(fmt.Sprintf(deploymentsLatestEndpointTemplate, projectID)).
-		SetParam("page", "1").
-		SetParam("per_page", "1").
+		APIPath(fmt.Sprintf(deploymentsEndpointTemplate, projectID)).
 		SetParam("scope", "success").
-		SetParam("environment", options.Environment).
-		Do(ctx)
+		SetParam("environment", options.Environment)
 
+	// only the most recent deployment is needed
+	pager := client.NewPager(request, client.PageOptions{Page: 1, PageSize: 1})
+	deployments, err := client.ListPages[resources.DeploymentHistory](ctx, pager)
 	if err != nil {
-		return fmt.Errorf("error executing request: %w", err)
-	}
-
-	if err := resp.Error(); err != nil {
 		return err
 	}
 
-	var deployments []resources.DeploymentHistory
-	if err := resp.ParseResponse(&deployments); err != nil {
-		return fmt.Errorf("cannot parse server response: %w", err)
-	}
-
 	if len(deployments) == 0 {
 		fmt.Println("No successful deployments found")
 		return nil
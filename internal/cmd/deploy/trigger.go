@@ -25,6 +25,7 @@ import (
 
 	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/exitcode"
 	"github.com/mia-platform/miactl/internal/resources"
 )
 
@@ -41,7 +42,8 @@ func triggerCmd(options *clioptions.CLIOptions) *cobra.Command {
 
 The deploy will be performed by the pipeline setup in project, the command will then keep
 listening on updates of the status for keep the user informed on the updates. The command
-will exit with error if the pipeline will not end with a success.`,
+will exit with error if the pipeline will not end with a success, or if it will not end
+within the duration set with the timeout flag.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			environmentName := args[0]
@@ -62,6 +64,7 @@ func deployTriggerOptions(cmd *cobra.Command, options *clioptions.CLIOptions) {
 	options.AddCompanyFlags(flags)
 	options.AddProjectFlags(flags)
 	options.AddDeployFlags(flags)
+	options.AddWaitTimeoutFlags(flags)
 	if err := cmd.MarkFlagRequired("revision"); err != nil {
 		// if there is an error something very wrong is happening, panic
 		panic(err)
@@ -88,19 +91,29 @@ func runDeployTrigger(ctx context.Context, environmentName string, options *clio
 		return err
 	}
 
+	ctx, cancel := options.WaitContext(ctx)
+	defer cancel()
+
 	resp, err := triggerPipeline(ctx, client, environmentName, projectID, options)
 	if err != nil {
 		return fmt.Errorf("error executing the deploy request: %w", err)
 	}
 	fmt.Printf("Deploying project %s in the environment '%s'\n", projectID, environmentName)
+	if options.DryRun {
+		// the pipeline has not been started, so there is no status to wait for
+		return nil
+	}
 
 	status, err := waitStatus(ctx, client, projectID, resp.ID, environmentName)
-	if err != nil {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitcode.Mark(fmt.Errorf("the pipeline did not end within %s", options.WaitTimeout), exitcode.ErrTimeout)
+	case err != nil:
 		return fmt.Errorf("error retrieving the pipeline status: %w", err)
 	}
 
 	if status == "failed" {
-		return errors.New("pipeline failed")
+		return exitcode.ErrPipelineFailed
 	}
 
 	fmt.Printf("Pipeline ended with %s\n", status)
@@ -153,7 +166,12 @@ var sleepDuration = (1 * time.Second) + (500 * time.Millisecond)
 func waitStatus(ctx context.Context, client *client.APIClient, projectID string, deployID string, environmentName string) (string, error) {
 	var outStatus *resources.PipelineStatus
 	for {
-		time.Sleep(sleepDuration)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(sleepDuration):
+		}
+
 		resp, err := client.
 			Get().
 			APIPath(fmt.Sprintf(pipelineStatusEndpointTemplate, projectID, deployID)).
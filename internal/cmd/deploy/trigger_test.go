@@ -21,11 +21,13 @@ import (
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/exitcode"
 	"github.com/mia-platform/miactl/internal/resources"
 )
 
@@ -33,33 +35,46 @@ func TestDeploy(t *testing.T) {
 	sleepDuration = 0
 
 	testCases := map[string]struct {
-		server    *httptest.Server
-		projectID string
-		expectErr bool
+		server      *httptest.Server
+		projectID   string
+		waitTimeout time.Duration
+		expectErr   bool
+		expectCode  int
 	}{
 		"pipeline succeed": {
 			server:    testTriggerServer(t),
 			projectID: "correct",
 		},
 		"pipeline failed": {
-			server:    testFailedTriggerServer(t),
-			projectID: "failed",
-			expectErr: true,
+			server:     testFailedTriggerServer(t),
+			projectID:  "failed",
+			expectErr:  true,
+			expectCode: exitcode.PipelineFailed,
 		},
 		"pipeline fails": {
-			server:    testTriggerServer(t),
-			projectID: "fails-bad-request",
-			expectErr: true,
+			server:     testTriggerServer(t),
+			projectID:  "fails-bad-request",
+			expectErr:  true,
+			expectCode: exitcode.GenericError,
 		},
 		"wait status fails": {
-			server:    testTriggerServer(t),
-			projectID: "fails-wait-status",
-			expectErr: true,
+			server:     testTriggerServer(t),
+			projectID:  "fails-wait-status",
+			expectErr:  true,
+			expectCode: exitcode.RemoteFailure,
+		},
+		"pipeline does not end before the timeout": {
+			server:      testTriggerServer(t),
+			projectID:   "running",
+			waitTimeout: 100 * time.Millisecond,
+			expectErr:   true,
+			expectCode:  exitcode.Timeout,
 		},
 		"missing project ID": {
-			server:    testTriggerServer(t),
-			projectID: "",
-			expectErr: true,
+			server:     testTriggerServer(t),
+			projectID:  "",
+			expectErr:  true,
+			expectCode: exitcode.GenericError,
 		},
 	}
 
@@ -72,10 +87,12 @@ func TestDeploy(t *testing.T) {
 				ProjectID:    testCase.projectID,
 				Revision:     "revision",
 				MiactlConfig: filepath.Join(t.TempDir(), "nofile"),
+				WaitTimeout:  testCase.waitTimeout,
 			}
 			err := runDeployTrigger(t.Context(), "environmentName", options)
 			if testCase.expectErr {
 				require.Error(t, err)
+				assert.Equal(t, testCase.expectCode, exitcode.FromError(err))
 				return
 			}
 			assert.NoError(t, err)
@@ -88,7 +105,9 @@ func testTriggerServer(t *testing.T) *httptest.Server {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Helper()
 		switch {
-		case r.Method == http.MethodPost && (r.URL.Path == fmt.Sprintf(deployProjectEndpointTemplate, "correct") || r.URL.Path == fmt.Sprintf(deployProjectEndpointTemplate, "fails-wait-status")):
+		case r.Method == http.MethodPost && (r.URL.Path == fmt.Sprintf(deployProjectEndpointTemplate, "correct") ||
+			r.URL.Path == fmt.Sprintf(deployProjectEndpointTemplate, "fails-wait-status") ||
+			r.URL.Path == fmt.Sprintf(deployProjectEndpointTemplate, "running")):
 			data, err := resources.EncodeResourceToJSON(&resources.DeployProject{
 				ID:  "1",
 				URL: "http://example.com",
@@ -103,6 +122,13 @@ func testTriggerServer(t *testing.T) *httptest.Server {
 			})
 			require.NoError(t, err)
 			w.Write(data)
+		case r.Method == http.MethodGet && r.URL.Path == fmt.Sprintf(pipelineStatusEndpointTemplate, "running", "1"):
+			data, err := resources.EncodeResourceToJSON(&resources.PipelineStatus{
+				ID:     "1",
+				Status: "running",
+			})
+			require.NoError(t, err)
+			w.Write(data)
 		case r.Method == http.MethodPost && r.URL.Path == fmt.Sprintf(deployProjectEndpointTemplate, "fails-bad-request"):
 			respBody := `{"error": "Bad Request","message":"some bad request"}`
 
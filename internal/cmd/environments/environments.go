@@ -60,17 +60,26 @@ func listEnvironmentsCmd(o *clioptions.CLIOptions) *cobra.Command {
 		Long:  "List all environments for a given project id",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			return printEnvironments(cmd.Context(), client, restConfig.CompanyID, restConfig.ProjectID, o.Printer())
+			if err != nil {
+				return err
+			}
+			printer, err := o.Printer()
+			if err != nil {
+				return err
+			}
+			return printEnvironments(cmd.Context(), client, restConfig.CompanyID, restConfig.ProjectID, printer)
 		},
 	}
 
+	o.AddListOutputFlag(cmd.Flags())
 	return cmd
 }
 
-func printEnvironments(ctx context.Context, client *client.APIClient, companyID, projectID string, p printer.IPrinter) error {
+func printEnvironments(ctx context.Context, client *client.APIClient, companyID, projectID string, p printer.ListPrinter) error {
 	switch {
 	case len(companyID) == 0:
 		return errors.New("missing company id, please set one with the flag or context")
@@ -99,38 +108,37 @@ func printEnvironments(ctx context.Context, client *client.APIClient, companyID,
 		return errors.New("no project found with this id in the current company")
 	}
 
-	environments := project.Environments
-	if len(environments) == 0 {
-		fmt.Printf("No environment found for %s project\n", project.Name)
-		return nil
-	}
-
-	p.Keys("Name", "Environment ID", "Production", "Cluster", "Kubernetes Namespace")
-
-	clustersCache := make(map[string]string, 0)
-	for _, env := range environments {
+	clusterNames := make(map[string]string, 0)
+	for _, env := range project.Environments {
 		clusterID := env.Cluster.ID
-		clusterName, found := clustersCache[clusterID]
-		if !found {
-			name, err := clusterNameForID(ctx, client, companyID, clusterID)
-			if err != nil {
-				return err
-			}
-			clustersCache[clusterID] = name
-			clusterName = name
+		if _, found := clusterNames[clusterID]; found {
+			continue
 		}
 
-		p.Record(
-			env.DisplayName,
-			env.EnvID,
-			strconv.FormatBool(env.IsProduction),
-			clusterName,
-			env.Cluster.Namespace,
-		)
+		name, err := clusterNameForID(ctx, client, companyID, clusterID)
+		if err != nil {
+			return err
+		}
+		clusterNames[clusterID] = name
 	}
 
-	p.Print()
-	return nil
+	list := printer.NewList(project.Environments, environmentName, printer.Table[resources.Environment]{
+		Headers: []string{"Name", "Environment ID", "Production", "Cluster", "Kubernetes Namespace"},
+		Row: func(env resources.Environment) []string {
+			return []string{
+				env.DisplayName,
+				env.EnvID,
+				strconv.FormatBool(env.IsProduction),
+				clusterNames[env.Cluster.ID],
+				env.Cluster.Namespace,
+			}
+		},
+	})
+	return p.PrintList(list.WithEmptyMessage(fmt.Sprintf("No environment found for %s project", project.Name)))
+}
+
+func environmentName(env resources.Environment) string {
+	return env.EnvID
 }
 
 func clusterNameForID(ctx context.Context, client *client.APIClient, companyID, clusterID string) (string, error) {
@@ -42,16 +42,26 @@ func Command(o *clioptions.CLIOptions) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			return printEventsList(cmd.Context(), client, restConfig.ProjectID, restConfig.Environment, args[1], o.Printer())
+			if err != nil {
+				return err
+			}
+			printer, err := o.Printer()
+			if err != nil {
+				return err
+			}
+			return printEventsList(cmd.Context(), client, restConfig.ProjectID, restConfig.Environment, args[0], printer)
 		},
 	}
+
+	o.AddListOutputFlag(cmd.Flags())
 	return cmd
 }
 
-func printEventsList(ctx context.Context, client *client.APIClient, projectID, environment, resourceName string, p printer.IPrinter) error {
+func printEventsList(ctx context.Context, client *client.APIClient, projectID, environment, resourceName string, p printer.ListPrinter) error {
 	if projectID == "" {
 		return errors.New("missing project id, please set one with the flag or context")
 	}
@@ -79,18 +89,15 @@ func printEventsList(ctx context.Context, client *client.APIClient, projectID, e
 		return err
 	}
 
-	if len(events) == 0 {
-		fmt.Printf("No events found for %s in %s environment\n", resourceName, environment)
-		return nil
-	}
-
-	p.Keys("Last Seen", "Type", "Reason", "Object", "Message")
-	for _, event := range events {
-		p.Record(rowForEvent(event)...)
-	}
+	list := printer.NewList(events, eventName, printer.Table[resources.RuntimeEvent]{
+		Headers: []string{"Last Seen", "Type", "Reason", "Object", "Message"},
+		Row:     rowForEvent,
+	})
+	return p.PrintList(list.WithEmptyMessage(fmt.Sprintf("No events found for %s in %s environment", resourceName, environment)))
+}
 
-	p.Print()
-	return nil
+func eventName(event resources.RuntimeEvent) string {
+	return event.Object
 }
 
 func rowForEvent(event resources.RuntimeEvent) []string {
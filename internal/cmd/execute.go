@@ -0,0 +1,61 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mia-platform/miactl/internal/exitcode"
+)
+
+// Execute run rootCmd with ctx and return the exit code for the process; the errors returned before the
+// selected command has started, like unknown commands or flags, wrong arguments and missing required flags,
+// are usage errors
+func Execute(ctx context.Context, rootCmd *cobra.Command) int {
+	started := false
+	preRun := rootCmd.PersistentPreRunE
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if preRun != nil {
+			if err := preRun(cmd, args); err != nil {
+				started = true
+				cmd.SilenceUsage = true
+				return err
+			}
+		}
+
+		// cobra validates the flags only after the hooks, and the pre run can set the required flags
+		// with the preferences, so they are validated here for knowing if it is a usage error
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err
+		}
+
+		started = true
+		// the usage is printed only for the usage errors
+		cmd.SilenceUsage = true
+		return nil
+	}
+
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil && !started {
+		err = exitcode.Mark(err, exitcode.ErrUsage)
+	}
+	return exitcode.FromError(err)
+}
@@ -0,0 +1,119 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mia-platform/miactl/internal/exitcode"
+)
+
+func TestExecute(t *testing.T) {
+	testCases := map[string]struct {
+		args         []string
+		runErr       error
+		preRunErr    error
+		required     bool
+		grouped      bool
+		expectedCode int
+	}{
+		"success": {
+			args:         []string{"run"},
+			expectedCode: exitcode.Success,
+		},
+		"unknown command": {
+			args:         []string{"unknown"},
+			expectedCode: exitcode.Usage,
+		},
+		"unknown flag": {
+			args:         []string{"run", "--unknown"},
+			expectedCode: exitcode.Usage,
+		},
+		"wrong arguments": {
+			args:         []string{"run", "argument"},
+			expectedCode: exitcode.Usage,
+		},
+		"missing required flag": {
+			args:         []string{"run"},
+			required:     true,
+			expectedCode: exitcode.Usage,
+		},
+		"required flag set": {
+			args:         []string{"run", "--name", "value"},
+			required:     true,
+			expectedCode: exitcode.Success,
+		},
+		"flag group not satisfied": {
+			args:         []string{"run", "--name", "value"},
+			grouped:      true,
+			expectedCode: exitcode.Usage,
+		},
+		"failing command": {
+			args:         []string{"run"},
+			runErr:       exitcode.ErrPipelineFailed,
+			expectedCode: exitcode.PipelineFailed,
+		},
+		"failing pre run": {
+			args:         []string{"run"},
+			preRunErr:    errors.New("invalid preference"),
+			expectedCode: exitcode.GenericError,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			rootCmd := &cobra.Command{
+				Use: "miactl",
+				PersistentPreRunE: func(*cobra.Command, []string) error {
+					return testCase.preRunErr
+				},
+			}
+			runCmd := &cobra.Command{
+				Use:  "run",
+				Args: cobra.NoArgs,
+				RunE: func(*cobra.Command, []string) error {
+					return testCase.runErr
+				},
+			}
+			runCmd.Flags().String("name", "", "")
+			runCmd.Flags().String("other", "", "")
+			if testCase.required {
+				runCmd.MarkFlagRequired("name")
+			}
+			if testCase.grouped {
+				runCmd.MarkFlagsRequiredTogether("name", "other")
+			}
+			rootCmd.AddCommand(runCmd)
+			rootCmd.SetArgs(testCase.args)
+			out := new(bytes.Buffer)
+			rootCmd.SetOut(out)
+			rootCmd.SetErr(out)
+
+			assert.Equal(t, testCase.expectedCode, Execute(t.Context(), rootCmd))
+			if testCase.expectedCode == exitcode.Usage {
+				assert.Contains(t, strings.ToLower(out.String()), "usage")
+			} else {
+				assert.NotContains(t, out.String(), "Usage:")
+			}
+		})
+	}
+}
@@ -34,10 +34,14 @@ An extension can be activated on the whole company or a specific project, use th
 context to select where you want to activate it.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" {
 				return ErrRequiredCompanyID
@@ -50,7 +54,9 @@ context to select where you want to activate it.`,
 
 			extensibilityClient := New(client)
 			err = extensibilityClient.Activate(cmd.Context(), restConfig.CompanyID, o.EntityID, scope)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			fmt.Printf("Successfully activated extension %s for %s\n", o.EntityID, scope)
 			return nil
 		},
@@ -39,10 +39,14 @@ extension-id either within the manifest file or via command line argument.
 If an extension-id is found an updated is performed, if not instead a new extension will be created.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" {
 				return ErrRequiredCompanyID
@@ -67,7 +71,9 @@ If an extension-id is found an updated is performed, if not instead a new extens
 
 			extensibilityClient := New(client)
 			extensionID, err := extensibilityClient.Apply(cmd.Context(), restConfig.CompanyID, extensionData)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			fmt.Printf("Successfully applied extension with id %s\n", extensionID)
 			return nil
 		},
@@ -33,10 +33,14 @@ func DeactivateCmd(o *clioptions.CLIOptions) *cobra.Command {
 The cli context is used to select where the extension shall be deactivated.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" {
 				return ErrRequiredCompanyID
@@ -49,7 +53,9 @@ The cli context is used to select where the extension shall be deactivated.`,
 
 			extensibilityClient := New(client)
 			err = extensibilityClient.Deactivate(cmd.Context(), restConfig.CompanyID, o.EntityID, scope)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			fmt.Printf("Successfully deactivated extension %s for %s\n", o.EntityID, scope)
 			return nil
 		},
@@ -31,10 +31,14 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Delete a previously registered extension for the Company.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" {
 				return ErrRequiredCompanyID
@@ -44,9 +48,15 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 				return ErrRequiredExtensionID
 			}
 
+			if err := options.ConfirmDestructiveAction(fmt.Sprintf("The extension %s will be deleted", options.EntityID)); err != nil {
+				return err
+			}
+
 			extensibilityClient := New(client)
 			err = extensibilityClient.Delete(cmd.Context(), restConfig.CompanyID, options.EntityID)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			fmt.Println("Successfully deleted extension from Company")
 			return nil
 		},
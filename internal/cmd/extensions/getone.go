@@ -33,10 +33,14 @@ func GetOneCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Get details for a single registered extension for the company.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" {
 				return ErrRequiredCompanyID
@@ -47,7 +51,9 @@ func GetOneCmd(options *clioptions.CLIOptions) *cobra.Command {
 
 			extensibilityClient := New(client)
 			extension, err := extensibilityClient.GetOne(cmd.Context(), restConfig.CompanyID, options.EntityID)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			serialized, err := encoding.MarshalData(extension, options.OutputFormat, encoding.MarshalOptions{Indent: true})
 			if err != nil {
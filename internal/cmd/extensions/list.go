@@ -35,10 +35,18 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "List registered extensions for the company.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
+			printer, err := options.Printer(clioptions.DisableWrapLines(true))
+			if err != nil {
+				return err
+			}
 
 			if restConfig.CompanyID == "" {
 				return ErrRequiredCompanyID
@@ -46,14 +54,16 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 
 			extensibilityClient := New(client)
 			extensions, err := extensibilityClient.List(cmd.Context(), restConfig.CompanyID, options.ResolveExtensionsDetails)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
-			printExtensionsList(extensions, options.Printer(clioptions.DisableWrapLines(true)), options.ResolveExtensionsDetails)
-			return nil
+			return printExtensionsList(extensions, printer, options.ResolveExtensionsDetails)
 		},
 	}
 
 	addResolveDetailsFlag(options, cmd)
+	options.AddListOutputFlag(cmd.Flags())
 	return cmd
 }
 
@@ -62,37 +72,47 @@ func addResolveDetailsFlag(options *clioptions.CLIOptions, cmd *cobra.Command) {
 	flags.BoolVar(&options.ResolveExtensionsDetails, "resolve-details", false, "Retrieve also menu and category info")
 }
 
-func printExtensionsList(extensions []*extensibility.ExtensionInfo, p printer.IPrinter, resolveDetails bool) {
+func printExtensionsList(extensions []*extensibility.ExtensionInfo, p printer.ListPrinter, resolveDetails bool) error {
 	tableColumnLabel := []string{"ID", "Name", "Entry", "Destination", "Description"}
 	if resolveDetails {
 		tableColumnLabel = append(tableColumnLabel, "Menu (id)")
 		tableColumnLabel = append(tableColumnLabel, "Category (id)")
 	}
-	p.Keys(tableColumnLabel...)
-	for _, extension := range extensions {
-		tableRow := []string{
-			extension.ExtensionID,
-			extension.Name,
-			extension.Entry,
-			extension.Destination.ID,
-			extension.Description,
+
+	return p.PrintList(printer.NewList(extensions, extensionName, printer.Table[*extensibility.ExtensionInfo]{
+		Headers: tableColumnLabel,
+		Row: func(extension *extensibility.ExtensionInfo) []string {
+			return rowForExtension(extension, resolveDetails)
+		},
+	}))
+}
+
+func extensionName(extension *extensibility.ExtensionInfo) string {
+	return extension.ExtensionID
+}
+
+func rowForExtension(extension *extensibility.ExtensionInfo, resolveDetails bool) []string {
+	tableRow := []string{
+		extension.ExtensionID,
+		extension.Name,
+		extension.Entry,
+		extension.Destination.ID,
+		extension.Description,
+	}
+	if resolveDetails {
+		if extension.Menu == nil {
+			tableRow = append(tableRow, "")
+		} else {
+			tableRow = append(tableRow, menucolumn(extension.Menu.ID, extension.Menu.LabelIntl))
 		}
-		if resolveDetails {
-			if extension.Menu == nil {
-				tableRow = append(tableRow, "")
-			} else {
-				tableRow = append(tableRow, menucolumn(extension.Menu.ID, extension.Menu.LabelIntl))
-			}
 
-			if extension.Category == nil {
-				tableRow = append(tableRow, "")
-			} else {
-				tableRow = append(tableRow, menucolumn(extension.Category.ID, extension.Category.LabelIntl))
-			}
+		if extension.Category == nil {
+			tableRow = append(tableRow, "")
+		} else {
+			tableRow = append(tableRow, menucolumn(extension.Category.ID, extension.Category.LabelIntl))
 		}
-		p.Record(tableRow...)
 	}
-	p.Print()
+	return tableRow
 }
 
 func menucolumn(id string, labelIntl extensibility.IntlMessages) string {
@@ -55,11 +55,12 @@ func TestPrintExtensionsList(t *testing.T) {
 		}
 
 		str := &strings.Builder{}
-		printExtensionsList(
+		err := printExtensionsList(
 			data,
 			printer.NewTablePrinter(printer.TablePrinterOptions{}, str),
 			false,
 		)
+		require.NoError(t, err)
 
 		expectedTokens := []string{
 			"ID", "NAME", "ENTRY", "DESTINATION", "DESCRIPTION",
@@ -103,11 +104,12 @@ func TestPrintExtensionsList(t *testing.T) {
 		}
 
 		str := &strings.Builder{}
-		printExtensionsList(
+		err := printExtensionsList(
 			data,
 			printer.NewTablePrinter(printer.TablePrinterOptions{}, str),
 			true,
 		)
+		require.NoError(t, err)
 
 		expectedTokens := []string{
 			"ID", "NAME", "ENTRY", "DESTINATION", "DESCRIPTION", "MENU (ID)", "CATEGORY (ID)",
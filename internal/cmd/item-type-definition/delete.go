@@ -52,9 +52,13 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 		SuggestFor: []string{"rm"},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), client, 14, 1)
 			if versionError != nil {
@@ -70,14 +74,18 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 			}
 
 			if options.ItemTypeDefinitionName != "" {
+				confirmMessage := fmt.Sprintf("The item type definition %s will be deleted", options.ItemTypeDefinitionName)
+				if err := options.ConfirmDestructiveAction(confirmMessage); err != nil {
+					return err
+				}
+
 				err = deleteITD(
 					cmd.Context(),
 					client,
 					companyID,
 					options.ItemTypeDefinitionName,
 				)
-				cobra.CheckErr(err)
-				return nil
+				return err
 			}
 
 			return errors.New("invalid input parameters")
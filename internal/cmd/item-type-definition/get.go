@@ -46,9 +46,13 @@ func GetCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  getCmdLong,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), client, 14, 1)
 			if versionError != nil {
@@ -65,7 +69,9 @@ func GetCmd(options *clioptions.CLIOptions) *cobra.Command {
 				options.ItemTypeDefinitionName,
 				options.OutputFormat,
 			)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			fmt.Println(serializedItem)
 			return nil
@@ -60,6 +60,7 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 
 	options.AddPublicFlag(cmd.Flags())
 	options.AddPageFlag(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
 
 	return cmd
 }
@@ -67,9 +68,17 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 func runListCmd(options *clioptions.CLIOptions) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		restConfig, err := options.ToRESTConfig()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 		apiClient, err := client.APIClientForConfig(restConfig)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+		printer, err := options.Printer()
+		if err != nil {
+			return err
+		}
 
 		canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), apiClient, 14, 1)
 		if versionError != nil {
@@ -85,36 +94,44 @@ func runListCmd(options *clioptions.CLIOptions) func(cmd *cobra.Command, args []
 			Page:      options.Page,
 		}
 
-		err = PrintItds(cmd.Context(), apiClient, listItemsOptions, options.Printer(), listItdEndpoint)
-		cobra.CheckErr(err)
+		err = PrintItds(cmd.Context(), apiClient, listItemsOptions, printer, listItdEndpoint)
+		if err != nil {
+			return err
+		}
 
 		return nil
 	}
 }
 
-func PrintItds(context context.Context, client *client.APIClient, options GetItdsOptions, p printer.IPrinter, endpoint string) error {
+func PrintItds(context context.Context, client *client.APIClient, options GetItdsOptions, p printer.ListPrinter, endpoint string) error {
 	itds, err := fetchItds(context, client, options, endpoint)
 	if err != nil {
 		return err
 	}
 
-	p.Keys("Name", "Display Name", "Visibility", "Publisher", "Versioning Supported")
-	for _, itd := range itds {
-		publisher := itd.Metadata.Publisher.Name
-		if publisher == "" {
-			publisher = "-"
-		}
+	return p.PrintList(printer.NewList(itds, itdName, printer.Table[*itd.ItemTypeDefinition]{
+		Headers: []string{"Name", "Display Name", "Visibility", "Publisher", "Versioning Supported"},
+		Row:     rowForItd,
+	}))
+}
 
-		p.Record(
-			itd.Metadata.Name,
-			itd.Metadata.DisplayName,
-			itd.Metadata.Visibility.Scope,
-			publisher,
-			strconv.FormatBool(itd.Spec.IsVersioningSupported),
-		)
+func itdName(definition *itd.ItemTypeDefinition) string {
+	return definition.Metadata.Name
+}
+
+func rowForItd(definition *itd.ItemTypeDefinition) []string {
+	publisher := definition.Metadata.Publisher.Name
+	if publisher == "" {
+		publisher = "-"
+	}
+
+	return []string{
+		definition.Metadata.Name,
+		definition.Metadata.DisplayName,
+		definition.Metadata.Visibility.Scope,
+		publisher,
+		strconv.FormatBool(definition.Spec.IsVersioningSupported),
 	}
-	p.Print()
-	return nil
 }
 
 func fetchItds(ctx context.Context, client *client.APIClient, options GetItdsOptions, endpoint string) ([]*itd.ItemTypeDefinition, error) {
@@ -69,9 +69,13 @@ func PutCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Example: putExample,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			canUseNewAPI, versionError := util.VersionCheck(cmd.Context(), client, 14, 1)
 			if versionError != nil {
@@ -93,7 +97,9 @@ func PutCmd(options *clioptions.CLIOptions) *cobra.Command {
 				options.ItemTypeDefinitionFilePath,
 				options.OutputFormat,
 			)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			fmt.Println(outcome)
 
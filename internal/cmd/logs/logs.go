@@ -52,17 +52,23 @@ miactl runtime logs api-gateway
 miactl runtime logs "^job-name$"`,
 
 		Args: cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			stream, err := getLogs(cmd.Context(), client, restConfig.ProjectID, restConfig.Environment, args[0], o.FollowLogs)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			defer stream.Close()
 			_, err = io.Copy(cmd.OutOrStdout(), stream)
-			cobra.CheckErr(err)
+			return err
 		},
 	}
 
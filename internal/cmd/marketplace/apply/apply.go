@@ -76,9 +76,13 @@ func ApplyCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Example: applyExample,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			companyID := restConfig.CompanyID
 			if len(companyID) == 0 {
@@ -91,13 +95,20 @@ func ApplyCmd(options *clioptions.CLIOptions) *cobra.Command {
 				companyID,
 				options.MarketplaceResourcePaths,
 			)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
+			if options.DryRun {
+				// the response of the dry run does not contain the outcome of the items
+				fmt.Println("Dry run: the items have not been applied.")
+				return nil
+			}
 			fmt.Println(outcome)
 
 			return nil
 		},
-		PostRun: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
+		PostRunE: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
 	}
 
 	options.AddMarketplaceApplyFlags(cmd)
@@ -56,19 +56,26 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 		SuggestFor: []string{"rm"},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			companyID := restConfig.CompanyID
 			if len(companyID) == 0 {
 				return marketplace.ErrMissingCompanyID
 			}
 
+			if err := options.ConfirmDestructiveAction("The selected Marketplace item will be deleted"); err != nil {
+				return err
+			}
+
 			if options.MarketplaceItemObjectID != "" {
 				err = deleteItemByObjectID(cmd.Context(), client, companyID, options.MarketplaceItemObjectID)
-				cobra.CheckErr(err)
-				return nil
+				return err
 			}
 
 			if options.MarketplaceItemVersion != "" && options.MarketplaceItemID != "" {
@@ -79,13 +86,12 @@ func DeleteCmd(options *clioptions.CLIOptions) *cobra.Command {
 					options.MarketplaceItemID,
 					options.MarketplaceItemVersion,
 				)
-				cobra.CheckErr(err)
-				return nil
+				return err
 			}
 
 			return errors.New("invalid input parameters")
 		},
-		PostRun: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
+		PostRunE: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
 	}
 
 	itemObjectIDFlagName := options.AddMarketplaceItemObjectIDFlag(cmd.Flags())
@@ -53,9 +53,13 @@ func GetCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  cmdGetLongDescription,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			serializedItem, err := getItemEncodedWithFormat(
 				cmd.Context(),
@@ -66,12 +70,14 @@ func GetCmd(options *clioptions.CLIOptions) *cobra.Command {
 				options.MarketplaceItemVersion,
 				options.OutputFormat,
 			)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			fmt.Println(serializedItem)
 			return nil
 		},
-		PostRun: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
+		PostRunE: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
 	}
 
 	options.AddOutputFormatFlag(cmd.Flags(), encoding.JSON)
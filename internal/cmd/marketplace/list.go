@@ -40,31 +40,39 @@ const (
 // ListCmd return a new cobra command for listing marketplace items
 func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     listCmdUse,
-		Short:   "List marketplace items",
-		Long:    listCmdLong,
-		Run:     runListCmd(options),
-		PostRun: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
+		Use:      listCmdUse,
+		Short:    "List marketplace items",
+		Long:     listCmdLong,
+		RunE:     runListCmd(options),
+		PostRunE: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
 	}
 
 	options.AddPublicFlag(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
 
 	return cmd
 }
 
-func runListCmd(options *clioptions.CLIOptions) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, _ []string) {
+func runListCmd(options *clioptions.CLIOptions) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
 		restConfig, err := options.ToRESTConfig()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 		apiClient, err := client.APIClientForConfig(restConfig)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+		printer, err := options.Printer()
+		if err != nil {
+			return err
+		}
 
 		marketplaceItemsOptions := commonMarketplace.GetMarketplaceItemsOptions{
 			CompanyID: restConfig.CompanyID,
 			Public:    options.MarketplaceFetchPublicItems,
 		}
 
-		err = commonMarketplace.PrintMarketplaceItems(cmd.Context(), apiClient, marketplaceItemsOptions, options.Printer(), listMarketplaceEndpoint)
-		cobra.CheckErr(err)
+		return commonMarketplace.PrintMarketplaceItems(cmd.Context(), apiClient, marketplaceItemsOptions, printer, listMarketplaceEndpoint)
 	}
 }
@@ -34,11 +34,19 @@ func ListVersionCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Short: "List versions of a Marketplace item",
 		Long: `List the currently available versions of a Marketplace item.
 The command will output a table with each version of the item.`,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
+			printer, err := options.Printer(clioptions.DisableWrapLines(true))
+			if err != nil {
+				return err
+			}
 
 			releases, err := commonMarketplace.GetItemVersions(
 				cmd.Context(),
@@ -47,15 +55,16 @@ The command will output a table with each version of the item.`,
 				restConfig.CompanyID,
 				options.MarketplaceItemID,
 			)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
-			commonMarketplace.PrintItemVersionList(releases, options.Printer(
-				clioptions.DisableWrapLines(true),
-			))
+			return commonMarketplace.PrintItemVersionList(releases, printer)
 		},
-		PostRun: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
+		PostRunE: util.CheckVersionAndShowMessage(options, 14, 0, marketplace.DeprecatedMessage),
 	}
 
+	options.AddListOutputFlag(cmd.Flags())
 	flagName := options.AddMarketplaceItemIDFlag(cmd.Flags())
 	err := cmd.MarkFlagRequired(flagName)
 	if err != nil {
@@ -54,10 +54,14 @@ func ApplyCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  applyProjectCmdLong,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			cmdOptions := applyProjectOptions{
 				RevisionName: options.Revision,
@@ -55,10 +55,14 @@ func DescribeCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  describeProjectCmdLong,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			cmdOptions := describeProjectOptions{
 				RevisionName: options.Revision,
@@ -18,7 +18,6 @@ package iam
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -26,7 +25,7 @@ import (
 	"github.com/mia-platform/miactl/internal/clioptions"
 	"github.com/mia-platform/miactl/internal/iam"
 	"github.com/mia-platform/miactl/internal/printer"
-	"github.com/mia-platform/miactl/internal/util"
+	"github.com/mia-platform/miactl/internal/resources"
 )
 
 func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
@@ -39,9 +38,13 @@ all of them noting the type and the current role associated with them`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			entityTypes := map[string]bool{
 				iam.GroupsEntityName:          options.ShowGroups,
@@ -49,17 +52,23 @@ all of them noting the type and the current role associated with them`,
 				iam.ServiceAccountsEntityName: options.ShowServiceAccounts,
 			}
 
-			return listAllIAMEntities(cmd.Context(), client, restConfig.CompanyID, restConfig.ProjectID, entityTypes, options.Printer())
+			printer, err := options.Printer()
+			if err != nil {
+				return err
+			}
+			return listAllIAMEntities(cmd.Context(), client, restConfig.CompanyID, restConfig.ProjectID, entityTypes, options.PageOptions(), printer)
 		},
 	}
 
 	options.AddIAMListFlags(cmd.Flags())
+	options.AddPaginationFlags(cmd.Flags())
+	options.AddListOutputFlag(cmd.Flags())
 	cmd.MarkFlagsMutuallyExclusive("users", "groups", "serviceAccounts")
 
 	return cmd
 }
 
-func listAllIAMEntities(ctx context.Context, client *client.APIClient, companyID, projectID string, entityTypes map[string]bool, p printer.IPrinter) error {
+func listAllIAMEntities(ctx context.Context, apiClient *client.APIClient, companyID, projectID string, entityTypes map[string]bool, pageOptions client.PageOptions, p printer.ListPrinter) error {
 	if len(companyID) == 0 {
 		return errors.New("missing company id, please set one with the flag or context")
 	}
@@ -68,23 +77,14 @@ func listAllIAMEntities(ctx context.Context, client *client.APIClient, companyID
 		return errors.New("missing project id, please set one with the flag or context")
 	}
 
-	resp, err := iam.ListAllIAMEntities(ctx, client, companyID, []string{projectID}, entityTypes)
-
-	if err != nil {
-		return fmt.Errorf("error executing request: %w", err)
-	}
-
-	if err := resp.Error(); err != nil {
-		return err
-	}
-
-	rows, err := util.RowsForResources(resp, iam.RowForProjectIAMIdentity(projectID))
+	request := iam.AllIAMEntitiesRequest(apiClient, companyID, []string{projectID}, entityTypes)
+	identities, err := client.ListPages[resources.IAMIdentity](ctx, client.NewPager(request, pageOptions))
 	if err != nil {
 		return err
 	}
 
-	p.Keys("ID", "Type", "Name", "Roles", "Environments Roles").
-		BulkRecords(rows...).
-		Print()
-	return nil
+	return p.PrintList(printer.NewList(identities, iam.IAMIdentityName, printer.Table[resources.IAMIdentity]{
+		Headers: []string{"ID", "Type", "Name", "Roles", "Environments Roles"},
+		Row:     iam.RowForProjectIAMIdentity(projectID),
+	}))
 }
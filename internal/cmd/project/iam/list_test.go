@@ -60,9 +60,9 @@ func TestListAllIAMIdentities(t *testing.T) {
 				Transport: http.DefaultTransport,
 				Host:      testCase.server.URL,
 			}
-			client, err := client.APIClientForConfig(clientConfig)
+			apiClient, err := client.APIClientForConfig(clientConfig)
 			require.NoError(t, err)
-			err = listAllIAMEntities(t.Context(), client, companyID, projectID, testCase.searchParams, &printer.NopPrinter{})
+			err = listAllIAMEntities(t.Context(), apiClient, companyID, projectID, testCase.searchParams, client.PageOptions{}, &printer.NopPrinter{})
 			if testCase.err {
 				assert.Error(t, err)
 			} else {
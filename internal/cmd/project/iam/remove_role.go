@@ -38,9 +38,13 @@ func RemoveRoleCmd(options *clioptions.CLIOptions) *cobra.Command {
 		ValidArgs: validArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			changes := roleChanges{
 				companyID:       restConfig.CompanyID,
 				projectID:       restConfig.ProjectID,
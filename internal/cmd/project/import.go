@@ -64,9 +64,13 @@ func ImportCmd(o *clioptions.CLIOptions) *cobra.Command {
 		Long:  importCmdLong,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			return importResources(cmd.Context(), client, restConfig.ProjectID, o.Revision, o.InputFilePath, cmd.ErrOrStderr())
 		},
 	}
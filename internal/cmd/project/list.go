@@ -18,7 +18,7 @@ package project
 import (
 	"context"
 	"errors"
-	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -41,51 +41,69 @@ func ListCmd(options *clioptions.CLIOptions) *cobra.Command {
 
 The company can be set via the dedicated flag, or it will be inferred from
 the current context. If no company can be selected the command will return
-an error.`,
+an error.
+
+Only the first page of projects is shown by default, use the --all flag for
+listing all of them.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			return listProjects(cmd.Context(), client, restConfig.CompanyID, options.Printer())
+			if err != nil {
+				return err
+			}
+			printer, err := options.Printer()
+			if err != nil {
+				return err
+			}
+			return listProjects(cmd.Context(), client, restConfig.CompanyID, options.PageOptions(), printer)
 		},
 	}
 
+	options.AddPaginationFlags(prjListCmd.Flags())
+	options.AddListOutputFlag(prjListCmd.Flags())
 	return prjListCmd
 }
 
 // listProjects retrieves the projects with the company ID of the current context
-func listProjects(ctx context.Context, client *client.APIClient, companyID string, p printer.IPrinter) error {
+func listProjects(ctx context.Context, apiClient *client.APIClient, companyID string, pageOptions client.PageOptions, p printer.ListPrinter) error {
 	if len(companyID) == 0 {
 		return errors.New("missing company id, please set one with the flag or context")
 	}
 
-	// execute the request
-	resp, err := client.
+	request := apiClient.
 		Get().
 		SetParam("tenantIds", companyID).
-		APIPath(listProjectsEndpoint).
-		Do(ctx)
+		APIPath(listProjectsEndpoint)
+	projects, err := client.ListPages[*resources.Project](ctx, client.NewPager(request, pageOptions))
 	if err != nil {
-		return fmt.Errorf("error executing request: %w", err)
-	}
-
-	if err := resp.Error(); err != nil {
 		return err
 	}
 
-	projects := make([]*resources.Project, 0)
-	if err := resp.ParseResponse(&projects); err != nil {
-		return fmt.Errorf("error parsing response body: %w", err)
-	}
-
-	p.Keys("Name", "Project ID", "Configuration Git Path")
+	companyProjects := make([]*resources.Project, 0, len(projects))
 	for _, project := range projects {
 		if project.CompanyID == companyID {
-			p.Record(project.Name, project.ID, project.ConfigurationGitPath)
+			companyProjects = append(companyProjects, project)
 		}
 	}
 
-	p.Print()
-	return nil
+	return p.PrintList(printer.NewList(companyProjects, projectName, printer.Table[*resources.Project]{
+		Headers:     []string{"Name", "Project ID", "Configuration Git Path"},
+		WideHeaders: []string{"Environments"},
+		Row:         rowForProject,
+	}))
+}
+
+func projectName(project *resources.Project) string {
+	return project.ID
+}
+
+func rowForProject(project *resources.Project) []string {
+	environments := make([]string, 0, len(project.Environments))
+	for _, environment := range project.Environments {
+		environments = append(environments, environment.EnvID)
+	}
+	return []string{project.Name, project.ID, project.ConfigurationGitPath, strings.Join(environments, ", ")}
 }
@@ -18,6 +18,7 @@ package project
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,11 +59,11 @@ func TestGetProjects(t *testing.T) {
 			server := testCase.testServer
 			defer server.Close()
 
-			client, err := client.APIClientForConfig(&client.Config{
+			apiClient, err := client.APIClientForConfig(&client.Config{
 				Host: server.URL,
 			})
 			require.NoError(t, err)
-			err = listProjects(t.Context(), client, testCase.companyID, &printer.NopPrinter{})
+			err = listProjects(t.Context(), apiClient, testCase.companyID, client.PageOptions{}, &printer.NopPrinter{})
 			if testCase.expectError {
 				assert.Error(t, err)
 				return
@@ -73,6 +74,39 @@ func TestGetProjects(t *testing.T) {
 	}
 }
 
+func TestGetAllProjects(t *testing.T) {
+	requestedPages := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, listProjectsEndpoint, r.URL.Path)
+		require.Equal(t, "2", r.URL.Query().Get("per_page"))
+
+		page := r.URL.Query().Get("page")
+		requestedPages = append(requestedPages, page)
+		switch page {
+		case "1":
+			w.Write([]byte(`[{"_id": "first", "name": "First", "tenantId": "foo-company"}, {"_id": "second", "name": "Second", "tenantId": "foo-company"}]`))
+		case "2":
+			w.Write([]byte(`[{"_id": "third", "name": "Third", "tenantId": "foo-company"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	output := &strings.Builder{}
+	tablePrinter := printer.NewTablePrinter(printer.TablePrinterOptions{}, output)
+	err = listProjects(t.Context(), apiClient, "foo-company", client.PageOptions{All: true, PageSize: 2}, tablePrinter)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "2"}, requestedPages)
+	for _, projectID := range []string{"first", "second", "third"} {
+		assert.Contains(t, output.String(), projectID)
+	}
+}
+
 func listTestServer(t *testing.T) *httptest.Server {
 	t.Helper()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
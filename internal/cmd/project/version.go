@@ -71,10 +71,14 @@ func CreateVersionCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  createVersionCmdLong,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			cmdOptions := versionProjectOptions{
 				ProjectID:          restConfig.ProjectID,
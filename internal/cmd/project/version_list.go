@@ -47,10 +47,14 @@ func VersionListCmd(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  listVersionsCmdLong,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 
 			cmdOptions := listVersionsOptions{
 				ProjectID:    restConfig.ProjectID,
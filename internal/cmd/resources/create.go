@@ -27,6 +27,7 @@ import (
 
 	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/exitcode"
 	"github.com/mia-platform/miactl/internal/resources"
 )
 
@@ -60,10 +61,18 @@ func jobCommand(options *clioptions.CLIOptions) *cobra.Command {
 		Long:  "Create a job from a cronjob in the selected environment and project",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			restConfig, err := options.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			err = createJob(cmd.Context(), client, restConfig.ProjectID, restConfig.Environment, options.FromCronJob, options.WaitJobCompletion, options.WaitJobTimeoutSeconds)
+			if err != nil {
+				return err
+			}
+			timeout := options.WaitTimeout
+			if timeout <= 0 {
+				timeout = time.Duration(options.WaitJobTimeoutSeconds) * time.Second
+			}
+			err = createJob(cmd.Context(), client, restConfig.ProjectID, restConfig.Environment, options.FromCronJob, options.WaitJobCompletion && !options.DryRun, timeout)
 			if err != nil {
 				if !errors.Is(err, errCreateJobValidation) {
 					cmd.SilenceUsage = true
@@ -85,7 +94,7 @@ func jobCommand(options *clioptions.CLIOptions) *cobra.Command {
 	return cmd
 }
 
-func createJob(ctx context.Context, client *client.APIClient, projectID, environment, cronjobName string, waitJobCompletion bool, waitJobTimeoutSeconds int) error {
+func createJob(ctx context.Context, client *client.APIClient, projectID, environment, cronjobName string, waitJobCompletion bool, waitTimeout time.Duration) error {
 	if err := validateCreateJobParams(projectID, environment); err != nil {
 		return err
 	}
@@ -101,7 +110,7 @@ func createJob(ctx context.Context, client *client.APIClient, projectID, environ
 		return nil
 	}
 
-	return waitForJobCompletion(ctx, client, projectID, environment, jobName, waitJobTimeoutSeconds)
+	return waitForJobCompletion(ctx, client, projectID, environment, jobName, waitTimeout)
 }
 
 func validateCreateJobParams(projectID, environment string) error {
@@ -146,14 +155,15 @@ func triggerJobCreation(ctx context.Context, client *client.APIClient, projectID
 	return createResponse.JobName, nil
 }
 
-func waitForJobCompletion(ctx context.Context, client *client.APIClient, projectID, environment, jobName string, timeoutSeconds int) error {
-	return waitForJobCompletionWithInterval(ctx, client, projectID, environment, jobName, timeoutSeconds, 10*time.Second)
+func waitForJobCompletion(ctx context.Context, client *client.APIClient, projectID, environment, jobName string, timeout time.Duration) error {
+	return waitForJobCompletionWithInterval(ctx, client, projectID, environment, jobName, timeout, 10*time.Second)
 }
 
-func waitForJobCompletionWithInterval(ctx context.Context, client *client.APIClient, projectID, environment, jobName string, timeoutSeconds int, tickerInterval time.Duration) error {
-	fmt.Printf("Waiting for job %s to complete (timeout: %ds)...\n", jobName, timeoutSeconds)
+func waitForJobCompletionWithInterval(ctx context.Context, client *client.APIClient, projectID, environment, jobName string, timeout time.Duration, tickerInterval time.Duration) error {
+	fmt.Printf("Waiting for job %s to complete (timeout: %s)...\n", jobName, timeout)
 
-	timeout := time.After(time.Duration(timeoutSeconds) * time.Second)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	ticker := time.NewTicker(tickerInterval)
 	defer ticker.Stop()
 
@@ -162,10 +172,13 @@ func waitForJobCompletionWithInterval(ctx context.Context, client *client.APICli
 
 	for {
 		select {
-		case <-timeout:
-			return fmt.Errorf("job %s did not complete within %d seconds", jobName, timeoutSeconds)
+		case <-ctx.Done():
+			return waitJobError(ctx, jobName, timeout)
 		case <-ticker.C:
 			completed, err := checkJobStatus(ctx, client, projectID, environment, jobName)
+			if ctx.Err() != nil {
+				return waitJobError(ctx, jobName, timeout)
+			}
 			if err != nil {
 				retries++
 				if retries >= maxRetries {
@@ -184,6 +197,14 @@ func waitForJobCompletionWithInterval(ctx context.Context, client *client.APICli
 	}
 }
 
+// waitJobError return the error for a wait interrupted by the expiration or the cancellation of ctx
+func waitJobError(ctx context.Context, jobName string, timeout time.Duration) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return exitcode.Mark(fmt.Errorf("job %s did not complete within %s", jobName, timeout), exitcode.ErrTimeout)
+	}
+	return ctx.Err()
+}
+
 func checkJobStatus(ctx context.Context, client *client.APIClient, projectID, environment, jobName string) (bool, error) {
 	job, err := fetchJobStatus(ctx, client, projectID, environment, jobName)
 	if err != nil {
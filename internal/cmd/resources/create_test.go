@@ -177,7 +177,7 @@ func TestCreateJob(t *testing.T) {
 			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
 			defer cancel()
 
-			err = createJob(ctx, client, testCase.projectID, testCase.environment, "cronjob-name", testCase.waitJobCompletion, testCase.waitJobTimeoutSeconds)
+			err = createJob(ctx, client, testCase.projectID, testCase.environment, "cronjob-name", testCase.waitJobCompletion, time.Duration(testCase.waitJobTimeoutSeconds)*time.Second)
 			if testCase.err {
 				require.Error(t, err)
 			} else {
@@ -258,7 +258,7 @@ func TestWaitForJobCompletion(t *testing.T) {
 			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
 			defer cancel()
 
-			err = waitForJobCompletionWithInterval(ctx, client, testCase.projectID, testCase.environment, testCase.jobName, testCase.waitJobTimeoutSeconds, 100*time.Millisecond)
+			err = waitForJobCompletionWithInterval(ctx, client, testCase.projectID, testCase.environment, testCase.jobName, time.Duration(testCase.waitJobTimeoutSeconds)*time.Second, 100*time.Millisecond)
 			if testCase.err {
 				require.Error(t, err)
 			} else {
@@ -25,7 +25,7 @@ import (
 	"github.com/mia-platform/miactl/internal/client"
 	"github.com/mia-platform/miactl/internal/clioptions"
 	"github.com/mia-platform/miactl/internal/printer"
-	"github.com/mia-platform/miactl/internal/util"
+	"github.com/mia-platform/miactl/internal/resources"
 
 	"github.com/spf13/cobra"
 )
@@ -106,10 +106,18 @@ Use "miactl runtime api-resources" for a complete list of currently supported re
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			restConfig, err := o.ToRESTConfig()
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			client, err := client.APIClientForConfig(restConfig)
-			cobra.CheckErr(err)
-			return printList(cmd.Context(), client, restConfig.ProjectID, args[0], restConfig.Environment, o.Printer())
+			if err != nil {
+				return err
+			}
+			printer, err := o.Printer()
+			if err != nil {
+				return err
+			}
+			return printList(cmd.Context(), client, restConfig.ProjectID, args[0], restConfig.Environment, printer)
 		},
 		Example: `# List all pods in current context
 miactl runtime list pods
@@ -119,6 +127,7 @@ miactl runtime list services --environment development`,
 	}
 
 	o.AddEnvironmentFlags(cmd.Flags())
+	o.AddListOutputFlag(cmd.Flags())
 
 	return cmd
 }
@@ -138,7 +147,7 @@ func resourcesCompletions(args []string, toComplete string) []string {
 	return resources
 }
 
-func printList(ctx context.Context, client *client.APIClient, projectID, resourceType, environment string, p printer.IPrinter) error {
+func printList(ctx context.Context, client *client.APIClient, projectID, resourceType, environment string, p printer.ListPrinter) error {
 	if projectID == "" {
 		return errors.New("missing project id, please set one with the flag or context")
 	}
@@ -168,40 +177,52 @@ func printList(ctx context.Context, client *client.APIClient, projectID, resourc
 		return err
 	}
 
-	tableHeaders := make([]string, 0)
 	canonicalType := ""
-	var rows [][]string
+	var list *printer.List
 	switch resourceType {
 	case PodResourceType, PodsResourceType:
-		tableHeaders = append(tableHeaders, "Status", "Name", "Application", "Ready", "Phase", "Restart", "Age")
-		rows, err = util.RowsForResources(resp, rowForPod)
+		list, err = listForResources(resp, podName, printer.Table[resources.Pod]{
+			Headers: []string{"Status", "Name", "Application", "Ready", "Phase", "Restart", "Age"},
+			Row:     rowForPod,
+		})
 		canonicalType = PodsResourceType
 	case CronJobResourceType, CronJobsResourceType:
-		tableHeaders = append(tableHeaders, "Name", "Schedule", "Suspend", "Active", "Last Schedule", "Age")
-		rows, err = util.RowsForResources(resp, rowForCronJob)
+		list, err = listForResources(resp, cronJobName, printer.Table[resources.CronJob]{
+			Headers: []string{"Name", "Schedule", "Suspend", "Active", "Last Schedule", "Age"},
+			Row:     rowForCronJob,
+		})
 		canonicalType = CronJobsResourceType
 	case DeploymentResourceType, DeploymentsResourceType:
-		tableHeaders = append(tableHeaders, "Name", "Ready", "Up-to-Date", "Available", "Age")
-		rows, err = util.RowsForResources(resp, rowForDeployment)
+		list, err = listForResources(resp, deploymentName, printer.Table[resources.Deployment]{
+			Headers: []string{"Name", "Ready", "Up-to-Date", "Available", "Age"},
+			Row:     rowForDeployment,
+		})
 		canonicalType = DeploymentsResourceType
 	case JobResourceType, JobsResourceType:
-		tableHeaders = append(tableHeaders, "Name", "Finished Pods", "Duration", "Age")
-		rows, err = util.RowsForResources(resp, rowForJob)
+		list, err = listForResources(resp, jobName, printer.Table[resources.Job]{
+			Headers: []string{"Name", "Finished Pods", "Duration", "Age"},
+			Row:     rowForJob,
+		})
 		canonicalType = JobsResourceType
 	case ServiceResourceType, ServicesResourceType:
-		tableHeaders = append(tableHeaders, "Name", "Type", "Cluster-IP", "Port(s)", "Age")
-		rows, err = util.RowsForResources(resp, rowForService)
+		list, err = listForResources(resp, serviceName, printer.Table[resources.Service]{
+			Headers: []string{"Name", "Type", "Cluster-IP", "Port(s)", "Age"},
+			Row:     rowForService,
+		})
 		canonicalType = ServicesResourceType
 	}
 	if err != nil {
 		return err
 	}
 
-	if len(rows) == 0 {
-		fmt.Printf("No %s found for %s environment\n", canonicalType, environment)
-		return nil
-	}
+	return p.PrintList(list.WithEmptyMessage(fmt.Sprintf("No %s found for %s environment", canonicalType, environment)))
+}
 
-	p.Keys(tableHeaders...).BulkRecords(rows...).Print()
-	return nil
+// listForResources parse the resources in the response body and return their List
+func listForResources[T any](response *client.Response, name func(T) string, table printer.Table[T]) (*printer.List, error) {
+	items := make([]T, 0)
+	if err := response.ParseResponse(&items); err != nil {
+		return nil, err
+	}
+	return printer.NewList(items, name, table), nil
 }
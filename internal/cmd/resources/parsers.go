@@ -28,6 +28,10 @@ import (
 	"github.com/mia-platform/miactl/internal/util"
 )
 
+func serviceName(service resources.Service) string {
+	return service.Name
+}
+
 func rowForService(service resources.Service) []string {
 	ports := make([]string, 0, len(service.Ports))
 	for _, port := range service.Ports {
@@ -48,6 +52,10 @@ func rowForService(service resources.Service) []string {
 	}
 }
 
+func podName(pod resources.Pod) string {
+	return pod.Name
+}
+
 func rowForPod(pod resources.Pod) []string {
 	totalRestart := 0
 	totalContainers := 0
@@ -89,6 +97,10 @@ func rowForPod(pod resources.Pod) []string {
 	}
 }
 
+func jobName(job resources.Job) string {
+	return job.Name
+}
+
 func rowForJob(job resources.Job) []string {
 	duration := "-"
 	if !job.CompletionTime.IsZero() {
@@ -103,6 +115,10 @@ func rowForJob(job resources.Job) []string {
 	}
 }
 
+func deploymentName(deployment resources.Deployment) string {
+	return deployment.Name
+}
+
 func rowForDeployment(deployment resources.Deployment) []string {
 	return []string{
 		deployment.Name,
@@ -113,6 +129,10 @@ func rowForDeployment(deployment resources.Deployment) []string {
 	}
 }
 
+func cronJobName(cronjob resources.CronJob) string {
+	return cronjob.Name
+}
+
 func rowForCronJob(cronjob resources.CronJob) []string {
 	return []string{
 		cronjob.Name,
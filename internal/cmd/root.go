@@ -18,11 +18,15 @@ package cmd
 import (
 	"context"
 	"os"
+	"slices"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 
 	"github.com/mia-platform/miactl/internal/clioptions"
+	"github.com/mia-platform/miactl/internal/cmd/api"
+	"github.com/mia-platform/miactl/internal/cmd/audit"
+	"github.com/mia-platform/miactl/internal/cmd/auth"
 	"github.com/mia-platform/miactl/internal/cmd/deploy"
 	"github.com/mia-platform/miactl/internal/cmd/extensions"
 	"github.com/mia-platform/miactl/internal/logger"
@@ -46,8 +50,23 @@ func NewRootCommand() *cobra.Command {
 	// add cmd flags
 	options.AddGlobalFlags(rootCmd.PersistentFlags())
 
+	// restore the defaults of the running command for the flags shared with other commands, and apply the
+	// preferences saved in the config file to the flags not set by the user
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if err := options.ResetFlagDefaults(cmd.Flags()); err != nil {
+			return err
+		}
+		if !usesPreferences(cmd) {
+			return nil
+		}
+		return options.ApplyPreferences(cmd.Context(), cmd.Flags())
+	}
+
 	// add sub commands
 	rootCmd.AddCommand(
+		auth.LoginCmd(options),
+		auth.LogoutCmd(options),
+		AuthCmd(options),
 		deploy.NewDeployCmd(options),
 		CompanyCmd(options),
 		ContextCmd(options),
@@ -58,7 +77,26 @@ func NewRootCommand() *cobra.Command {
 		RuntimeCmd(options),
 		VersionCmd(options),
 		extensions.NewCommand(options),
+		api.NewCommand(options),
+		audit.NewCommand(options),
 	)
 
 	return rootCmd
 }
+
+// commandsWithoutPreferences are the commands, and their subcommands, that never read the config file
+var commandsWithoutPreferences = []string{
+	"version",
+	"help",
+	"completion",
+	cobra.ShellCompRequestCmd,
+	cobra.ShellCompNoDescRequestCmd,
+}
+
+// usesPreferences return false if cmd is one of commandsWithoutPreferences or one of their subcommands
+func usesPreferences(cmd *cobra.Command) bool {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return !slices.Contains(commandsWithoutPreferences, cmd.Name())
+}
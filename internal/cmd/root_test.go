@@ -0,0 +1,70 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsesPreferences(t *testing.T) {
+	rootCmd := NewRootCommand()
+
+	testCases := map[string]struct {
+		args     []string
+		expected bool
+	}{
+		"version": {
+			args: []string{"version"},
+		},
+		"help": {
+			args: []string{"help"},
+		},
+		"completion subcommand": {
+			args: []string{"completion", "bash"},
+		},
+		"remote command": {
+			args:     []string{"project", "list"},
+			expected: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			rootCmd.InitDefaultHelpCmd()
+			rootCmd.InitDefaultCompletionCmd()
+			cmd, _, err := rootCmd.Find(testCase.args)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, usesPreferences(cmd))
+		})
+	}
+}
+
+func TestVersionWithInvalidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte("apiVersion: v99\n"), 0600))
+
+	rootCmd := NewRootCommand()
+	rootCmd.SetArgs([]string{"version", "--config", configPath})
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	assert.NoError(t, rootCmd.Execute())
+}
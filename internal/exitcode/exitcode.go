@@ -0,0 +1,112 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exitcode contains the exit codes of the miactl process and the mapping from the errors returned by
+// the commands to them, so scripts can know why a command has failed.
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+const (
+	// Success is returned when the command has completed without errors
+	Success = 0
+	// GenericError is returned for the errors without a more specific code
+	GenericError = 1
+	// Usage is returned when the command has been invoked with wrong arguments or flags
+	Usage = 2
+	// Auth is returned when the credentials are not valid or are not allowed to perform the action
+	Auth = 3
+	// NotFound is returned when a resource requested to the Console does not exist
+	NotFound = 4
+	// RemoteFailure is returned when the Console cannot be reached or has failed to handle the request
+	RemoteFailure = 5
+	// PipelineFailed is returned when a pipeline started by the command has ended with a failure
+	PipelineFailed = 6
+	// Timeout is returned when the command has not completed within its deadline
+	Timeout = 7
+	// Conflict is returned when the request conflicts with the current state of a resource
+	Conflict = 8
+	// Interrupted is returned when the command has been stopped by an interrupt signal
+	Interrupted = 130
+)
+
+var (
+	// ErrUsage is wrapped by the errors caused by a wrong invocation of the command
+	ErrUsage = errors.New("usage error")
+	// ErrPipelineFailed is wrapped by the errors of the commands whose pipeline has ended with a failure
+	ErrPipelineFailed = errors.New("pipeline failed")
+	// ErrTimeout is wrapped by the errors of the commands that have stopped waiting for a result
+	ErrTimeout = errors.New("timeout")
+)
+
+// markedError add a sentinel to the chain of an error without changing its message
+type markedError struct {
+	err      error
+	sentinel error
+}
+
+func (e *markedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *markedError) Unwrap() []error {
+	return []error{e.err, e.sentinel}
+}
+
+// Mark return an error with the same message of err that also match sentinel with errors.Is
+func Mark(err, sentinel error) error {
+	if err == nil {
+		return nil
+	}
+	return &markedError{err: err, sentinel: sentinel}
+}
+
+// FromError return the exit code of the process for the error returned by a command
+func FromError(err error) int {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return Success
+	case errors.Is(err, ErrUsage):
+		return Usage
+	case errors.Is(err, context.Canceled):
+		return Interrupted
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.Is(err, ErrPipelineFailed):
+		return PipelineFailed
+	case errors.Is(err, client.ErrUnauthorized), errors.Is(err, client.ErrForbidden):
+		return Auth
+	case errors.Is(err, client.ErrNotFound):
+		return NotFound
+	case errors.Is(err, client.ErrConflict):
+		return Conflict
+	case errors.Is(err, client.ErrServerError):
+		return RemoteFailure
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return Timeout
+		}
+		return RemoteFailure
+	default:
+		return GenericError
+	}
+}
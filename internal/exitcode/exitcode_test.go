@@ -0,0 +1,113 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/client"
+)
+
+func TestMark(t *testing.T) {
+	assert.NoError(t, Mark(nil, ErrTimeout))
+
+	err := Mark(errors.New("the pipeline did not end within 1m0s"), ErrTimeout)
+	assert.EqualError(t, err, "the pipeline did not end within 1m0s")
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.NotErrorIs(t, err, ErrUsage)
+}
+
+func TestFromError(t *testing.T) {
+	testCases := map[string]struct {
+		err          error
+		expectedCode int
+	}{
+		"no error": {
+			expectedCode: Success,
+		},
+		"generic error": {
+			err:          errors.New("missing company id"),
+			expectedCode: GenericError,
+		},
+		"usage error": {
+			err:          Mark(errors.New("unknown flag: --foo"), ErrUsage),
+			expectedCode: Usage,
+		},
+		"interrupted": {
+			err:          fmt.Errorf("error executing request: %w", context.Canceled),
+			expectedCode: Interrupted,
+		},
+		"deadline exceeded": {
+			err:          fmt.Errorf("error executing request: %w", context.DeadlineExceeded),
+			expectedCode: Timeout,
+		},
+		"wait timeout": {
+			err:          Mark(errors.New("job did not complete within 1m0s"), ErrTimeout),
+			expectedCode: Timeout,
+		},
+		"pipeline failed": {
+			err:          ErrPipelineFailed,
+			expectedCode: PipelineFailed,
+		},
+		"network error": {
+			err:          fmt.Errorf("error executing request: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+			expectedCode: RemoteFailure,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.expectedCode, FromError(testCase.err))
+		})
+	}
+}
+
+func TestFromResponseError(t *testing.T) {
+	testCases := map[int]int{
+		http.StatusBadRequest:          GenericError,
+		http.StatusUnauthorized:        Auth,
+		http.StatusForbidden:           Auth,
+		http.StatusNotFound:            NotFound,
+		http.StatusConflict:            Conflict,
+		http.StatusInternalServerError: RemoteFailure,
+		http.StatusServiceUnavailable:  RemoteFailure,
+	}
+
+	for statusCode, expectedCode := range testCases {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(statusCode)
+			}))
+			defer server.Close()
+
+			apiClient, err := client.APIClientForConfig(&client.Config{Host: server.URL})
+			require.NoError(t, err)
+			resp, err := apiClient.Get().APIPath("/api/resource").Do(t.Context())
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedCode, FromError(resp.Error()))
+		})
+	}
+}
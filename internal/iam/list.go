@@ -24,6 +24,12 @@ import (
 )
 
 func ListAllIAMEntities(ctx context.Context, client *client.APIClient, companyID string, projectIDs []string, entityTypes map[string]bool) (*client.Response, error) {
+	return AllIAMEntitiesRequest(client, companyID, projectIDs, entityTypes).Do(ctx)
+}
+
+// AllIAMEntitiesRequest return the request for listing the entities of companyID with one of the enabled
+// entityTypes, that have a role in one of projectIDs if not empty
+func AllIAMEntitiesRequest(client *client.APIClient, companyID string, projectIDs []string, entityTypes map[string]bool) *client.Request {
 	request := client.
 		Get().
 		APIPath(fmt.Sprintf(entititesPathTemplate, companyID))
@@ -39,10 +45,20 @@ func ListAllIAMEntities(ctx context.Context, client *client.APIClient, companyID
 		request.SetParam("identityType", entityName)
 	}
 
-	return request.Do(ctx)
+	return request
 }
 
 func ListSpecificEntities(ctx context.Context, client *client.APIClient, companyID string, entityType string) (*client.Response, error) {
+	request, err := SpecificEntitiesRequest(client, companyID, entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	return request.Do(ctx)
+}
+
+// SpecificEntitiesRequest return the request for listing the entities of companyID of type entityType
+func SpecificEntitiesRequest(client *client.APIClient, companyID string, entityType string) (*client.Request, error) {
 	var apiPathTemplate string
 
 	switch entityType {
@@ -56,9 +72,7 @@ func ListSpecificEntities(ctx context.Context, client *client.APIClient, company
 		return nil, errors.New("unknown IAM entity")
 	}
 
-	response, err := client.
+	return client.
 		Get().
-		APIPath(fmt.Sprintf(apiPathTemplate, companyID)).
-		Do(ctx)
-	return response, err
+		APIPath(fmt.Sprintf(apiPathTemplate, companyID)), nil
 }
@@ -16,7 +16,6 @@
 package iam
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
@@ -27,6 +26,26 @@ import (
 	"github.com/mia-platform/miactl/internal/util"
 )
 
+// IAMIdentityName return the identifier of identity printed by the name output
+func IAMIdentityName(identity resources.IAMIdentity) string {
+	return identity.ID
+}
+
+// UserIdentityName return the identifier of identity printed by the name output
+func UserIdentityName(identity resources.UserIdentity) string {
+	return identity.ID
+}
+
+// GroupIdentityName return the identifier of identity printed by the name output
+func GroupIdentityName(identity resources.GroupIdentity) string {
+	return identity.ID
+}
+
+// ServiceAccountIdentityName return the identifier of identity printed by the name output
+func ServiceAccountIdentityName(identity resources.ServiceAccountIdentity) string {
+	return identity.ID
+}
+
 func RowForIAMIdentity(identity resources.IAMIdentity) []string {
 	caser := cases.Title(language.English)
 	return []string{
@@ -39,7 +58,6 @@ func RowForIAMIdentity(identity resources.IAMIdentity) []string {
 
 func RowForProjectIAMIdentity(projectID string) func(resources.IAMIdentity) []string {
 	return func(identity resources.IAMIdentity) []string {
-		fmt.Println(identity)
 		var roleStrings []string
 		inherited := ""
 		caser := cases.Title(language.English)
@@ -0,0 +1,457 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonfilter implement a subset of the jq language for selecting values from a json document:
+// paths like .items[0].name or .["key"], the iteration with .[] and .[]?, the pipe operator, the length
+// and keys functions and select with the == and != comparisons.
+package jsonfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stage produce zero or more outputs for every input value
+type stage func(any) ([]any, error)
+
+// Filter is a parsed expression that can be applied to json values
+type Filter struct {
+	expression string
+	stages     []stage
+}
+
+// Parse return the Filter for expression, or an error if it contains unsupported syntax
+func Parse(expression string) (*Filter, error) {
+	stages, err := parsePipeline(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expression, err)
+	}
+
+	return &Filter{expression: expression, stages: stages}, nil
+}
+
+// Apply return all the values produced by the filter for input, that must be a value decoded by the
+// encoding/json package into an any
+func (f *Filter) Apply(input any) ([]any, error) {
+	values, err := applyStages(f.stages, input)
+	if err != nil {
+		return nil, fmt.Errorf("cannot apply filter %q: %w", f.expression, err)
+	}
+
+	return values, nil
+}
+
+// ApplyJSON decode data and apply the filter to it
+func (f *Filter) ApplyJSON(data []byte) ([]any, error) {
+	var input any
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("cannot parse json: %w", err)
+	}
+	return f.Apply(input)
+}
+
+// parsePipeline parse the stages of expression separated by the pipe operator
+func parsePipeline(expression string) ([]stage, error) {
+	parts, err := splitOutside(expression, "|")
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]stage, 0, len(parts))
+	for _, part := range parts {
+		stage, err := parseStage(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+	return stages, nil
+}
+
+func parseStage(expression string) (stage, error) {
+	switch {
+	case len(expression) == 0:
+		return nil, fmt.Errorf("empty expression")
+	case expression == "length":
+		return lengthOf, nil
+	case expression == "keys":
+		return keysOf, nil
+	case strings.HasPrefix(expression, "select(") && strings.HasSuffix(expression, ")"):
+		return parseSelect(strings.TrimSuffix(strings.TrimPrefix(expression, "select("), ")"))
+	case strings.HasPrefix(expression, "."):
+		return parsePath(expression)
+	default:
+		return nil, fmt.Errorf("unsupported expression %q", expression)
+	}
+}
+
+// parseSelect parse the condition of a select, that can be a filter compared with == or != to a json
+// literal, or only a filter that is tested for truthiness
+func parseSelect(condition string) (stage, error) {
+	operator := ""
+	var operands []string
+	for _, candidate := range []string{"==", "!="} {
+		parts, err := splitOutside(condition, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) == 2 {
+			operator, operands = candidate, parts
+			break
+		}
+	}
+
+	if len(operator) == 0 {
+		stages, err := parsePipeline(condition)
+		if err != nil {
+			return nil, err
+		}
+		return func(value any) ([]any, error) {
+			results, err := applyStages(stages, value)
+			if err != nil {
+				return nil, err
+			}
+			for _, result := range results {
+				if isTruthy(result) {
+					return []any{value}, nil
+				}
+			}
+			return nil, nil
+		}, nil
+	}
+
+	stages, err := parsePipeline(operands[0])
+	if err != nil {
+		return nil, err
+	}
+	var literal any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(operands[1])), &literal); err != nil {
+		return nil, fmt.Errorf("the right side of %s must be a json value: %s", operator, strings.TrimSpace(operands[1]))
+	}
+
+	return func(value any) ([]any, error) {
+		results, err := applyStages(stages, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			if reflect.DeepEqual(result, literal) == (operator == "==") {
+				return []any{value}, nil
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// parsePath parse a sequence of field accesses, indexes and iterations starting with a dot
+func parsePath(expression string) (stage, error) {
+	steps := make([]stage, 0)
+	for position := 0; position < len(expression); {
+		switch expression[position] {
+		case '.':
+			position++
+			if position >= len(expression) || expression[position] == '[' {
+				continue
+			}
+
+			var name string
+			var err error
+			if expression[position] == '"' {
+				name, position, err = readQuoted(expression, position)
+			} else {
+				name, position, err = readIdentifier(expression, position)
+			}
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, fieldStep(name))
+		case '[':
+			step, next, err := parseBracket(expression, position)
+			if err != nil {
+				return nil, err
+			}
+			if next < len(expression) && expression[next] == '?' {
+				step = optional(step)
+				next++
+			}
+			steps = append(steps, step)
+			position = next
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", expression[position], position)
+		}
+	}
+
+	return func(value any) ([]any, error) {
+		return applyStages(steps, value)
+	}, nil
+}
+
+// parseBracket parse the [], [n] or ["key"] at position and return the position following it
+func parseBracket(expression string, position int) (stage, int, error) {
+	end := position + 1
+	if end < len(expression) && expression[end] == '"' {
+		name, next, err := readQuoted(expression, end)
+		if err != nil {
+			return nil, 0, err
+		}
+		if next >= len(expression) || expression[next] != ']' {
+			return nil, 0, fmt.Errorf("missing ] at position %d", next)
+		}
+		return fieldStep(name), next + 1, nil
+	}
+
+	closing := strings.IndexByte(expression[end:], ']')
+	if closing < 0 {
+		return nil, 0, fmt.Errorf("missing ] at position %d", position)
+	}
+
+	content := strings.TrimSpace(expression[end : end+closing])
+	next := end + closing + 1
+	if len(content) == 0 {
+		return iterate, next, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid index %q", content)
+	}
+	return indexStep(index), next, nil
+}
+
+func readIdentifier(expression string, position int) (string, int, error) {
+	end := position
+	for end < len(expression) {
+		r, size := utf8.DecodeRuneInString(expression[end:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		end += size
+	}
+
+	if end == position {
+		return "", 0, fmt.Errorf("missing field name at position %d", position)
+	}
+	return expression[position:end], end, nil
+}
+
+func readQuoted(expression string, position int) (string, int, error) {
+	for end := position + 1; end < len(expression); end++ {
+		switch expression[end] {
+		case '\\':
+			end++
+		case '"':
+			value, err := strconv.Unquote(expression[position : end+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid string %s", expression[position:end+1])
+			}
+			return value, end + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string at position %d", position)
+}
+
+// splitOutside split expression on separator when it is not inside a string or parenthesis
+func splitOutside(expression, separator string) ([]string, error) {
+	parts := make([]string, 0)
+	depth := 0
+	inString := false
+	start := 0
+	for position := 0; position < len(expression); position++ {
+		switch char := expression[position]; {
+		case inString && char == '\\':
+			position++
+		case char == '"':
+			inString = !inString
+		case inString:
+		case char == '(' || char == '[':
+			depth++
+		case char == ')' || char == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(expression[position:], separator):
+			parts = append(parts, expression[start:position])
+			start = position + len(separator)
+			position += len(separator) - 1
+		}
+	}
+
+	if inString {
+		return nil, fmt.Errorf("unterminated string")
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parenthesis")
+	}
+	return append(parts, expression[start:]), nil
+}
+
+func applyStages(stages []stage, value any) ([]any, error) {
+	values := []any{value}
+	for _, stage := range stages {
+		next := make([]any, 0, len(values))
+		for _, value := range values {
+			outputs, err := stage(value)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, outputs...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func fieldStep(name string) stage {
+	return func(value any) ([]any, error) {
+		switch typed := value.(type) {
+		case nil:
+			return []any{nil}, nil
+		case map[string]any:
+			return []any{typed[name]}, nil
+		default:
+			return nil, fmt.Errorf("cannot get the field %q of %s", name, typeName(value))
+		}
+	}
+}
+
+func indexStep(index int) stage {
+	return func(value any) ([]any, error) {
+		switch typed := value.(type) {
+		case nil:
+			return []any{nil}, nil
+		case []any:
+			position := index
+			if position < 0 {
+				position += len(typed)
+			}
+			if position < 0 || position >= len(typed) {
+				return []any{nil}, nil
+			}
+			return []any{typed[position]}, nil
+		default:
+			return nil, fmt.Errorf("cannot get the index %d of %s", index, typeName(value))
+		}
+	}
+}
+
+// optional return a stage that produce no output in place of the errors of step
+func optional(step stage) stage {
+	return func(value any) ([]any, error) {
+		outputs, err := step(value)
+		if err != nil {
+			return nil, nil
+		}
+		return outputs, nil
+	}
+}
+
+func iterate(value any) ([]any, error) {
+	switch typed := value.(type) {
+	case []any:
+		return typed, nil
+	case map[string]any:
+		keys := sortedKeys(typed)
+		values := make([]any, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, typed[key])
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(value))
+	}
+}
+
+func lengthOf(value any) ([]any, error) {
+	switch typed := value.(type) {
+	case nil:
+		return []any{float64(0)}, nil
+	case string:
+		return []any{float64(utf8.RuneCountInString(typed))}, nil
+	case []any:
+		return []any{float64(len(typed))}, nil
+	case map[string]any:
+		return []any{float64(len(typed))}, nil
+	case float64:
+		if typed < 0 {
+			typed = -typed
+		}
+		return []any{typed}, nil
+	default:
+		return nil, fmt.Errorf("%s has no length", typeName(value))
+	}
+}
+
+func keysOf(value any) ([]any, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		keys := make([]any, 0, len(typed))
+		for _, key := range sortedKeys(typed) {
+			keys = append(keys, key)
+		}
+		return []any{keys}, nil
+	case []any:
+		keys := make([]any, 0, len(typed))
+		for index := range typed {
+			keys = append(keys, float64(index))
+		}
+		return []any{keys}, nil
+	default:
+		return nil, fmt.Errorf("%s has no keys", typeName(value))
+	}
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isTruthy follow the jq rules, where only false and null are false
+func isTruthy(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return typed
+	default:
+		return true
+	}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
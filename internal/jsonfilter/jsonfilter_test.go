@@ -0,0 +1,172 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const document = `{
+	"name": "miactl",
+	"empty": null,
+	"with space": true,
+	"items": [
+		{"id": 1, "name": "first", "tags": ["a", "b"], "enabled": true},
+		{"id": 2, "name": "second", "tags": [], "enabled": false},
+		{"id": 3, "name": "third|pipe", "tags": ["c"]}
+	],
+	"labels": {"b": "2", "a": "1"}
+}`
+
+func TestApply(t *testing.T) {
+	testCases := map[string]struct {
+		expression     string
+		expectedValues []any
+		expectedErr    string
+	}{
+		"identity": {
+			expression:     ".labels | .",
+			expectedValues: []any{map[string]any{"a": "1", "b": "2"}},
+		},
+		"field": {
+			expression:     ".name",
+			expectedValues: []any{"miactl"},
+		},
+		"missing field": {
+			expression:     ".missing.nested",
+			expectedValues: []any{nil},
+		},
+		"quoted field": {
+			expression:     `."with space"`,
+			expectedValues: []any{true},
+		},
+		"bracket field": {
+			expression:     `.["with space"]`,
+			expectedValues: []any{true},
+		},
+		"index": {
+			expression:     ".items[1].name",
+			expectedValues: []any{"second"},
+		},
+		"negative index": {
+			expression:     ".items[-1].id",
+			expectedValues: []any{float64(3)},
+		},
+		"out of range index": {
+			expression:     ".items[10]",
+			expectedValues: []any{nil},
+		},
+		"iterate array": {
+			expression:     ".items[].name",
+			expectedValues: []any{"first", "second", "third|pipe"},
+		},
+		"iterate object": {
+			expression:     ".labels[]",
+			expectedValues: []any{"1", "2"},
+		},
+		"optional iteration": {
+			expression:     ".items[].tags[]?",
+			expectedValues: []any{"a", "b", "c"},
+		},
+		"optional iteration of a string": {
+			expression:     ".name[]?",
+			expectedValues: []any{},
+		},
+		"pipe": {
+			expression:     ".items | .[0] | .tags[]",
+			expectedValues: []any{"a", "b"},
+		},
+		"length": {
+			expression:     ".items[].tags | length",
+			expectedValues: []any{float64(2), float64(0), float64(1)},
+		},
+		"keys": {
+			expression:     ".labels | keys",
+			expectedValues: []any{[]any{"a", "b"}},
+		},
+		"select equal": {
+			expression:     `.items[] | select(.name == "third|pipe") | .id`,
+			expectedValues: []any{float64(3)},
+		},
+		"select not equal": {
+			expression:     ".items[] | select(.id != 2) | .id",
+			expectedValues: []any{float64(1), float64(3)},
+		},
+		"select truthy": {
+			expression:     ".items[] | select(.enabled) | .name",
+			expectedValues: []any{"first"},
+		},
+		"select nested filter": {
+			expression:     ".items[] | select(.tags | length) | .id",
+			expectedValues: []any{float64(1), float64(2), float64(3)},
+		},
+		"field of array": {
+			expression:  ".items.name",
+			expectedErr: `cannot apply filter ".items.name": cannot get the field "name" of an array`,
+		},
+		"iterate string": {
+			expression:  ".name[]",
+			expectedErr: `cannot apply filter ".name[]": cannot iterate over a string`,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			filter, err := Parse(testCase.expression)
+			require.NoError(t, err)
+
+			values, err := filter.ApplyJSON([]byte(document))
+			if len(testCase.expectedErr) > 0 {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedValues, values)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]string{
+		"":                       `invalid filter "": empty expression`,
+		"name":                   `invalid filter "name": unsupported expression "name"`,
+		".items[":                `invalid filter ".items[": unbalanced parenthesis`,
+		".items[a]":              `invalid filter ".items[a]": invalid index "a"`,
+		`."name`:                 `invalid filter ".\"name": unterminated string`,
+		".items |":               `invalid filter ".items |": empty expression`,
+		".a-b":                   `invalid filter ".a-b": unexpected character '-' at position 2`,
+		"select(.id == unknown)": `invalid filter "select(.id == unknown)": the right side of == must be a json value: unknown`,
+	}
+
+	for expression, expectedErr := range testCases {
+		t.Run(expression, func(t *testing.T) {
+			_, err := Parse(expression)
+			assert.EqualError(t, err, expectedErr)
+		})
+	}
+}
+
+func TestApplyInvalidJSON(t *testing.T) {
+	filter, err := Parse(".")
+	require.NoError(t, err)
+
+	_, err = filter.ApplyJSON([]byte("not json"))
+	assert.ErrorContains(t, err, "cannot parse json")
+}
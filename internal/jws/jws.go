@@ -16,6 +16,9 @@ package jws
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -23,6 +26,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 )
@@ -161,9 +165,59 @@ func Encode(header *Header, c *ClaimSet, key *rsa.PrivateKey) (string, error) {
 	return EncodeWithSigner(header, c, sg)
 }
 
+// Algorithms supported by SignerForKey and VerifyWithKey, as defined in RFC 7518 and RFC 8037.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// es256KeySize is the size in bytes of the r and s values of an ES256 signature
+const es256KeySize = 32
+
+// SignerForKey returns a Signer for the given private key and the name of the algorithm
+// that must be set in the header. RSA, ECDSA on the P-256 curve and Ed25519 keys are supported.
+func SignerForKey(key crypto.PrivateKey) (Signer, string, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return func(data []byte) ([]byte, error) {
+			h := sha256.Sum256(data)
+			return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+		}, AlgorithmRS256, nil
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return nil, "", errors.New("jws: only ecdsa keys on the P-256 curve are supported")
+		}
+		return func(data []byte) ([]byte, error) {
+			h := sha256.Sum256(data)
+			r, s, err := ecdsa.Sign(rand.Reader, key, h[:])
+			if err != nil {
+				return nil, err
+			}
+			// RFC 7518 section 3.4: the signature is the concatenation of r and s, each padded to the key size
+			sig := make([]byte, 2*es256KeySize)
+			r.FillBytes(sig[:es256KeySize])
+			s.FillBytes(sig[es256KeySize:])
+			return sig, nil
+		}, AlgorithmES256, nil
+	case ed25519.PrivateKey:
+		return func(data []byte) ([]byte, error) {
+			return ed25519.Sign(key, data), nil
+		}, AlgorithmEdDSA, nil
+	default:
+		return nil, "", fmt.Errorf("jws: unsupported private key type %T", key)
+	}
+}
+
 // Verify tests whether the provided JWT token's signature was produced by the private key
 // associated with the supplied public key.
 func Verify(token string, key *rsa.PublicKey) error {
+	return VerifyWithKey(token, key)
+}
+
+// VerifyWithKey tests whether the provided JWT token's signature was produced by the private key
+// associated with the supplied RSA, ECDSA or Ed25519 public key.
+func VerifyWithKey(token string, key crypto.PublicKey) error {
 	if strings.Count(token, ".") != 2 {
 		return errors.New("jws: invalid token received, token must have 3 parts")
 	}
@@ -175,7 +229,26 @@ func Verify(token string, key *rsa.PublicKey) error {
 		return err
 	}
 
-	h := sha256.New()
-	h.Write([]byte(signedContent))
-	return rsa.VerifyPKCS1v15(key, crypto.SHA256, h.Sum(nil), signatureString)
+	h := sha256.Sum256([]byte(signedContent))
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, h[:], signatureString)
+	case *ecdsa.PublicKey:
+		if len(signatureString) != 2*es256KeySize {
+			return errors.New("jws: invalid ES256 signature length")
+		}
+		r := new(big.Int).SetBytes(signatureString[:es256KeySize])
+		s := new(big.Int).SetBytes(signatureString[es256KeySize:])
+		if !ecdsa.Verify(key, h[:], r, s) {
+			return errors.New("jws: ES256 signature verification failed")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, []byte(signedContent), signatureString) {
+			return errors.New("jws: EdDSA signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("jws: unsupported public key type %T", key)
+	}
 }
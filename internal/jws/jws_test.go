@@ -5,6 +5,10 @@
 package jws
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"testing"
@@ -44,3 +48,69 @@ func TestVerifyFailsOnMalformedClaim(t *testing.T) {
 		t.Error("got no errors; want improperly formed JWT not to be verified")
 	}
 }
+
+func TestSignerForKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		key          crypto.PrivateKey
+		publicKey    crypto.PublicKey
+		expectedAlgo string
+	}{
+		"rsa":     {key: rsaKey, publicKey: &rsaKey.PublicKey, expectedAlgo: AlgorithmRS256},
+		"ecdsa":   {key: ecKey, publicKey: &ecKey.PublicKey, expectedAlgo: AlgorithmES256},
+		"ed25519": {key: edKey, publicKey: edPublicKey, expectedAlgo: AlgorithmEdDSA},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			signer, algorithm, err := SignerForKey(test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if algorithm != test.expectedAlgo {
+				t.Fatalf("got algorithm %s; want %s", algorithm, test.expectedAlgo)
+			}
+
+			header := &Header{Algorithm: algorithm, Typ: "JWT"}
+			token, err := EncodeWithSigner(header, &ClaimSet{Iss: "http://google.com/"}, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := VerifyWithKey(token, test.publicKey); err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyWithKey(token+"A", test.publicKey); err == nil {
+				t.Error("got no errors; want tampered signature not to be verified")
+			}
+		})
+	}
+}
+
+func TestSignerForKeyUnsupportedKeys(t *testing.T) {
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, key := range map[string]crypto.PrivateKey{
+		"ecdsa P-384": p384Key,
+		"unknown":     "key",
+	} {
+		if _, _, err := SignerForKey(key); err == nil {
+			t.Errorf("%s: got no errors; want unsupported key to be rejected", name)
+		}
+	}
+}
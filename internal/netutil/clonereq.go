@@ -15,7 +15,10 @@
 
 package netutil
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+)
 
 // CloneRequest return a cloned version of request, used to abide to the RoundTripper contract
 func CloneRequest(request *http.Request) *http.Request {
@@ -31,3 +34,23 @@ func CloneRequest(request *http.Request) *http.Request {
 
 	return clone
 }
+
+// RewindRequest return a clone of request with a new copy of its body, so it can be sent again after a
+// previous attempt has consumed it; an error is returned if the body cannot be read again
+func RewindRequest(request *http.Request) (*http.Request, error) {
+	clone := CloneRequest(request)
+	if request.Body == nil || request.Body == http.NoBody {
+		return clone, nil
+	}
+
+	if request.GetBody == nil {
+		return nil, errors.New("the request body cannot be read again")
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
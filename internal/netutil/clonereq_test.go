@@ -16,7 +16,9 @@
 package netutil
 
 import (
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,3 +33,28 @@ func TestCloneRequest(t *testing.T) {
 	assert.NotSame(t, clonedReq, req)
 	assert.NotNil(t, clonedReq.Header)
 }
+
+func TestRewindRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "http://example.com", strings.NewReader("body"))
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(req.Body)
+	require.NoError(t, err)
+
+	rewinded, err := RewindRequest(req)
+	require.NoError(t, err)
+	assert.NotSame(t, rewinded, req)
+	data, err := io.ReadAll(rewinded.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "body", string(data))
+
+	req.GetBody = nil
+	_, err = RewindRequest(req)
+	assert.Error(t, err)
+
+	req, err = http.NewRequest(http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	rewinded, err = RewindRequest(req)
+	require.NoError(t, err)
+	assert.Nil(t, rewinded.Body)
+}
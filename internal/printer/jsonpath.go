@@ -0,0 +1,229 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mia-platform/miactl/internal/jsonfilter"
+)
+
+// jsonPathNode is a piece of a jsonpath template: a text printed as is, the values selected by a filter,
+// or a range that print its nodes for every value selected by its filter
+type jsonPathNode struct {
+	text     string
+	filter   *jsonfilter.Filter
+	isRange  bool
+	children []*jsonPathNode
+}
+
+type jsonPathPrinter struct {
+	w     io.Writer
+	nodes []*jsonPathNode
+}
+
+// NewJSONPathPrinter return a ListPrinter that print the values selected by the jsonpath template from the
+// array of the objects, as printed by the json output. The template support the kubectl syntax for fields,
+// indexes and [*], with {range}{end} blocks and quoted strings; a template without braces is a single path
+func NewJSONPathPrinter(template string, w io.Writer) (ListPrinter, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	nodes, err := parseJSONPath(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jsonpath template %q: %w", template, err)
+	}
+	return &jsonPathPrinter{w: w, nodes: nodes}, nil
+}
+
+func (p *jsonPathPrinter) PrintList(list *List) error {
+	data, err := list.data()
+	if err != nil {
+		return err
+	}
+
+	// the output is written only if the whole template has been executed
+	output := new(bytes.Buffer)
+	if err := executeJSONPath(output, p.nodes, data); err != nil {
+		return fmt.Errorf("cannot execute the jsonpath template: %w", err)
+	}
+	_, err = output.WriteTo(p.w)
+	return err
+}
+
+// parseJSONPath split template in its text and actions, nesting the nodes between range and end
+func parseJSONPath(template string) ([]*jsonPathNode, error) {
+	root := &jsonPathNode{isRange: true}
+	stack := []*jsonPathNode{root}
+
+	for len(template) > 0 {
+		current := stack[len(stack)-1]
+
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			current.children = append(current.children, &jsonPathNode{text: template})
+			break
+		}
+		if start > 0 {
+			current.children = append(current.children, &jsonPathNode{text: template[:start]})
+		}
+
+		end := actionEnd(template, start)
+		if end < 0 {
+			return nil, errors.New("unclosed action")
+		}
+		action := strings.TrimSpace(template[start+1 : end])
+		template = template[end+1:]
+
+		switch {
+		case action == "end":
+			if len(stack) == 1 {
+				return nil, errors.New("end without range")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(action, "range ") || action == "range":
+			filter, err := parseJSONPathFilter(strings.TrimSpace(strings.TrimPrefix(action, "range")))
+			if err != nil {
+				return nil, err
+			}
+			node := &jsonPathNode{filter: filter, isRange: true}
+			current.children = append(current.children, node)
+			stack = append(stack, node)
+		case strings.HasPrefix(action, "\"") || strings.HasPrefix(action, "'"):
+			text, err := unquoteJSONPath(action)
+			if err != nil {
+				return nil, err
+			}
+			current.children = append(current.children, &jsonPathNode{text: text})
+		default:
+			filter, err := parseJSONPathFilter(action)
+			if err != nil {
+				return nil, err
+			}
+			current.children = append(current.children, &jsonPathNode{filter: filter})
+		}
+	}
+
+	if len(stack) > 1 {
+		return nil, errors.New("range without end")
+	}
+	return root.children, nil
+}
+
+// actionEnd return the position of the brace closing the action starting at start, ignoring the braces
+// inside quoted strings, or -1 if it is not closed
+func actionEnd(template string, start int) int {
+	var quote byte
+	for position := start + 1; position < len(template); position++ {
+		switch char := template[position]; {
+		case quote != 0 && char == '\\':
+			position++
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '}':
+			return position
+		}
+	}
+	return -1
+}
+
+func unquoteJSONPath(action string) (string, error) {
+	if strings.HasPrefix(action, "'") && strings.HasSuffix(action, "'") && len(action) > 1 {
+		return action[1 : len(action)-1], nil
+	}
+
+	text, err := strconv.Unquote(action)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", action)
+	}
+	return text, nil
+}
+
+// parseJSONPathFilter convert a jsonpath expression, relative to the current value, in the equivalent
+// jq filter
+func parseJSONPathFilter(expression string) (*jsonfilter.Filter, error) {
+	expression = strings.TrimLeft(expression, "$@")
+	// like kubectl, iterating a missing value print nothing instead of failing
+	expression = strings.ReplaceAll(expression, "[*]", "[]?")
+	expression = strings.ReplaceAll(expression, "['", "[\"")
+	expression = strings.ReplaceAll(expression, "']", "\"]")
+	if !strings.HasPrefix(expression, ".") {
+		expression = "." + expression
+	}
+
+	return jsonfilter.Parse(expression)
+}
+
+func executeJSONPath(out *bytes.Buffer, nodes []*jsonPathNode, value any) error {
+	for _, node := range nodes {
+		if node.filter == nil {
+			out.WriteString(node.text)
+			continue
+		}
+
+		values, err := node.filter.Apply(value)
+		if err != nil {
+			return err
+		}
+
+		if node.isRange {
+			for _, item := range values {
+				if err := executeJSONPath(out, node.children, item); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for index, item := range values {
+			if index > 0 {
+				out.WriteByte(' ')
+			}
+			if err := writeJSONPathValue(out, item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeJSONPathValue write strings as they are, nothing for missing values and the json of the others
+func writeJSONPathValue(out *bytes.Buffer, value any) error {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case string:
+		out.WriteString(typed)
+		return nil
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return err
+		}
+		out.Write(encoded)
+		return nil
+	}
+}
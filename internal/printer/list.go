@@ -0,0 +1,99 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ListPrinter print a list of objects in one of the output formats supported by the list commands
+type ListPrinter interface {
+	PrintList(list *List) error
+}
+
+// Table describe the table printed for a list of T: Row return the values of the Headers columns, followed
+// by the values of the WideHeaders columns printed only by the wide output
+type Table[T any] struct {
+	Headers     []string
+	WideHeaders []string
+	Row         func(T) []string
+}
+
+// List contains the objects to print, with the name and the table row of each of them
+type List struct {
+	objects     []any
+	names       []string
+	headers     []string
+	wideHeaders []string
+	rows        [][]string
+
+	emptyMessage string
+}
+
+// NewList return the List of items, name return the identifier of an item printed by the name output and
+// table describe the table printed by the table and wide outputs
+func NewList[T any](items []T, name func(T) string, table Table[T]) *List {
+	list := &List{
+		objects:     make([]any, 0, len(items)),
+		names:       make([]string, 0, len(items)),
+		headers:     table.Headers,
+		wideHeaders: table.WideHeaders,
+		rows:        make([][]string, 0, len(items)),
+	}
+
+	for _, item := range items {
+		list.objects = append(list.objects, item)
+		list.names = append(list.names, name(item))
+		list.rows = append(list.rows, table.Row(item))
+	}
+
+	return list
+}
+
+// WithEmptyMessage set the message printed by the table and wide outputs in place of an empty table
+func (l *List) WithEmptyMessage(message string) *List {
+	l.emptyMessage = message
+	return l
+}
+
+// table return the headers and the rows of the table, including the wide columns only if wide is true
+func (l *List) table(wide bool) ([]string, [][]string) {
+	if wide {
+		return append(append([]string{}, l.headers...), l.wideHeaders...), l.rows
+	}
+
+	rows := make([][]string, 0, len(l.rows))
+	for _, row := range l.rows {
+		rows = append(rows, row[:min(len(row), len(l.headers))])
+	}
+	return l.headers, rows
+}
+
+// data return the objects as the generic values obtained decoding their json representation, so the
+// templates and the yaml output use the same field names of the json output
+func (l *List) data() (any, error) {
+	encoded, err := json.Marshal(l.objects)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode the objects: %w", err)
+	}
+
+	var data any
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, fmt.Errorf("cannot decode the objects: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,193 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	ID    string   `json:"_id"` //nolint:tagliatelle
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func testList() *List {
+	items := []*testItem{
+		{ID: "first-id", Name: "First", Count: 1, Tags: []string{"a", "b"}},
+		{ID: "second-id", Name: "Second", Count: 2},
+	}
+
+	return NewList(items, func(item *testItem) string { return item.ID }, Table[*testItem]{
+		Headers:     []string{"Name", "ID"},
+		WideHeaders: []string{"Count"},
+		Row: func(item *testItem) []string {
+			return []string{item.Name, item.ID, strconv.Itoa(item.Count)}
+		},
+	})
+}
+
+func TestListPrinters(t *testing.T) {
+	testCases := map[string]struct {
+		printer     func(*strings.Builder) (ListPrinter, error)
+		expected    string
+		expectedErr string
+	}{
+		"table": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewTablePrinter(TablePrinterOptions{}, w), nil
+			},
+			expected: "  NAME    ID         \n\n  First   first-id   \n  Second  second-id  \n",
+		},
+		"wide table": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewTablePrinter(TablePrinterOptions{Wide: true}, w), nil
+			},
+			expected: "  NAME    ID         COUNT  \n\n  First   first-id       1  \n  Second  second-id      2  \n",
+		},
+		"json": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewJSONPrinter(w), nil
+			},
+			expected: "[\n  {\n    \"_id\": \"first-id\",\n    \"name\": \"First\",\n    \"count\": 1,\n    \"tags\": [\n      \"a\",\n      \"b\"\n    ]\n  },\n  {\n    \"_id\": \"second-id\",\n    \"name\": \"Second\",\n    \"count\": 2\n  }\n]\n",
+		},
+		"yaml": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewYAMLPrinter(w), nil
+			},
+			expected: "- _id: first-id\n  count: 1\n  name: First\n  tags:\n  - a\n  - b\n- _id: second-id\n  count: 2\n  name: Second\n",
+		},
+		"name": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewNamePrinter(w), nil
+			},
+			expected: "first-id\nsecond-id\n",
+		},
+		"go-template": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewTemplatePrinter(`{{range .}}{{._id}}={{.count}}{{"\n"}}{{end}}`, w)
+			},
+			expected: "first-id=1\nsecond-id=2\n",
+		},
+		"invalid go-template": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewTemplatePrinter(`{{range .}}`, w)
+			},
+			expectedErr: "invalid go-template",
+		},
+		"failing go-template": {
+			printer: func(w *strings.Builder) (ListPrinter, error) {
+				return NewTemplatePrinter(`{{index . 5}}`, w)
+			},
+			expectedErr: "cannot execute the go-template",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			output := &strings.Builder{}
+			p, err := testCase.printer(output)
+			if err == nil {
+				err = p.PrintList(testList())
+			}
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				assert.Empty(t, output.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, output.String())
+		})
+	}
+}
+
+func TestJSONPathPrinter(t *testing.T) {
+	testCases := map[string]struct {
+		template    string
+		expected    string
+		expectedErr string
+	}{
+		"values of all the items": {
+			template: "{[*].name}",
+			expected: "First Second",
+		},
+		"path without braces": {
+			template: "[0]._id",
+			expected: "first-id",
+		},
+		"range with literals": {
+			template: `{range [*]}{._id}{"\t"}{.count}{"\n"}{end}`,
+			expected: "first-id\t1\nsecond-id\t2\n",
+		},
+		"root and bracket notation": {
+			template: "{$[1]['name']}",
+			expected: "Second",
+		},
+		"text and non string values": {
+			template: "tags: {[0].tags} missing: {[1].tags}",
+			expected: `tags: ["a","b"] missing: `,
+		},
+		"nested range": {
+			template: "{range [*]}{range .tags[*]}{@}-{end}{end}",
+			expected: "a-b-",
+		},
+		"unclosed action": {
+			template:    "{.name",
+			expectedErr: "unclosed action",
+		},
+		"range without end": {
+			template:    "{range [*]}{.name}",
+			expectedErr: "range without end",
+		},
+		"end without range": {
+			template:    "{.name}{end}",
+			expectedErr: "end without range",
+		},
+		"invalid path": {
+			template:    "{.name,}",
+			expectedErr: "invalid jsonpath template",
+		},
+		"path on a wrong type": {
+			template:    "{[0].name.first}",
+			expectedErr: "cannot execute the jsonpath template",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			output := &strings.Builder{}
+			p, err := NewJSONPathPrinter(testCase.template, output)
+			if err == nil {
+				err = p.PrintList(testList())
+			}
+			if len(testCase.expectedErr) > 0 {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				assert.Empty(t, output.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, output.String())
+		})
+	}
+}
@@ -39,3 +39,7 @@ func (n *NopPrinter) BulkRecords(_ ...[]string) IPrinter {
 
 func (n *NopPrinter) Print() {
 }
+
+func (n *NopPrinter) PrintList(_ *List) error {
+	return nil
+}
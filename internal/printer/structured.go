@@ -0,0 +1,112 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+type jsonPrinter struct {
+	w io.Writer
+}
+
+// NewJSONPrinter return a ListPrinter that print the objects as an indented json array
+func NewJSONPrinter(w io.Writer) ListPrinter {
+	return &jsonPrinter{w: w}
+}
+
+func (p *jsonPrinter) PrintList(list *List) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(list.objects)
+}
+
+type yamlPrinter struct {
+	w io.Writer
+}
+
+// NewYAMLPrinter return a ListPrinter that print the objects as a yaml sequence
+func NewYAMLPrinter(w io.Writer) ListPrinter {
+	return &yamlPrinter{w: w}
+}
+
+func (p *yamlPrinter) PrintList(list *List) error {
+	data, err := list.data()
+	if err != nil {
+		return err
+	}
+
+	encoded, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("cannot encode the objects: %w", err)
+	}
+	_, err = p.w.Write(encoded)
+	return err
+}
+
+type namePrinter struct {
+	w io.Writer
+}
+
+// NewNamePrinter return a ListPrinter that print only the identifier of every object, one for each line
+func NewNamePrinter(w io.Writer) ListPrinter {
+	return &namePrinter{w: w}
+}
+
+func (p *namePrinter) PrintList(list *List) error {
+	for _, name := range list.names {
+		if _, err := fmt.Fprintln(p.w, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type templatePrinter struct {
+	w        io.Writer
+	template *template.Template
+}
+
+// NewTemplatePrinter return a ListPrinter that execute the go template text with the array of the objects,
+// as printed by the json output, as data
+func NewTemplatePrinter(text string, w io.Writer) (ListPrinter, error) {
+	parsed, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %w", err)
+	}
+	return &templatePrinter{w: w, template: parsed}, nil
+}
+
+func (p *templatePrinter) PrintList(list *List) error {
+	data, err := list.data()
+	if err != nil {
+		return err
+	}
+
+	// the output is written only if the whole template has been executed
+	output := new(bytes.Buffer)
+	if err := p.template.Execute(output, data); err != nil {
+		return fmt.Errorf("cannot execute the go-template: %w", err)
+	}
+	_, err = output.WriteTo(p.w)
+	return err
+}
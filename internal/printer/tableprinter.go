@@ -16,6 +16,7 @@
 package printer
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/olekukonko/tablewriter"
@@ -23,6 +24,8 @@ import (
 
 type TablePrinterOptions struct {
 	WrapLinesDisabled bool
+	// Wide print also the wide columns of the lists
+	Wide bool
 }
 
 type TablePrinter struct {
@@ -70,3 +73,17 @@ func (t *TablePrinter) BulkRecords(records ...[]string) IPrinter {
 func (t *TablePrinter) Print() {
 	t.tw.Render()
 }
+
+// PrintList print the table of list, with the wide columns only if enabled in the options
+func (t *TablePrinter) PrintList(list *List) error {
+	if len(list.rows) == 0 && len(list.emptyMessage) > 0 {
+		_, err := fmt.Fprintln(t.w, list.emptyMessage)
+		return err
+	}
+
+	headers, rows := list.table(t.options.Wide)
+	t.Keys(headers...)
+	t.BulkRecords(rows...)
+	t.Print()
+	return nil
+}
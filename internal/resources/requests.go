@@ -24,6 +24,15 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
+type DeviceCodeRequest struct {
+	AppID      string `json:"appId"`
+	ProviderID string `json:"providerId"`
+}
+
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"deviceCode"`
+}
+
 type ServiceAccountRequest struct {
 	Name      string     `json:"name"`
 	Type      string     `json:"tokenEndpointAuthMethod"` //nolint: tagliatelle
@@ -31,6 +40,10 @@ type ServiceAccountRequest struct {
 	PublicKey *PublicKey `json:"publicKey,omitempty"`
 }
 
+type RotateServiceAccountRequest struct {
+	PublicKey *PublicKey `json:"publicKey,omitempty"`
+}
+
 type AddUserRequest struct {
 	Email string  `json:"email"`
 	Role  IAMRole `json:"role"`
@@ -67,12 +80,15 @@ type EditEnvironmentRole struct {
 }
 
 type PublicKey struct {
-	Type      string `json:"kty"` //nolint: tagliatelle
-	Use       string `json:"use"` //nolint: tagliatelle
-	Algorithm string `json:"alg"` //nolint: tagliatelle
-	KeyID     string `json:"kid"` //nolint: tagliatelle
-	Modulus   string `json:"n"`   //nolint: tagliatelle
-	Exponent  string `json:"e"`   //nolint: tagliatelle
+	Type      string `json:"kty"`           //nolint: tagliatelle
+	Use       string `json:"use"`           //nolint: tagliatelle
+	Algorithm string `json:"alg"`           //nolint: tagliatelle
+	KeyID     string `json:"kid"`           //nolint: tagliatelle
+	Modulus   string `json:"n,omitempty"`   //nolint: tagliatelle
+	Exponent  string `json:"e,omitempty"`   //nolint: tagliatelle
+	Curve     string `json:"crv,omitempty"` //nolint: tagliatelle
+	X         string `json:"x,omitempty"`   //nolint: tagliatelle
+	Y         string `json:"y,omitempty"`   //nolint: tagliatelle
 }
 
 type IAMRole string
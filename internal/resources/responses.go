@@ -47,6 +47,22 @@ type UserToken struct {
 	ExpiresAt    int64  `json:"expiresAt"`
 }
 
+// DeviceCode is the response of a device authorization request
+type DeviceCode struct {
+	DeviceCode              string `json:"deviceCode"`
+	UserCode                string `json:"userCode"`
+	VerificationURI         string `json:"verificationUri"`
+	VerificationURIComplete string `json:"verificationUriComplete"`
+	ExpiresIn               int64  `json:"expiresIn"`
+	Interval                int64  `json:"interval"`
+}
+
+// DeviceAuthorizationError is the error returned while polling for a device authorization
+type DeviceAuthorizationError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
 func (ut *UserToken) JWTToken() *oauth2.Token {
 	return &oauth2.Token{
 		AccessToken:  ut.AccessToken,
@@ -55,6 +71,28 @@ func (ut *UserToken) JWTToken() *oauth2.Token {
 	}
 }
 
+// ExecCredential is the document printed on stdout by an exec credential plugin
+type ExecCredential struct {
+	APIVersion string                `json:"apiVersion,omitempty"`
+	Kind       string                `json:"kind"`
+	Status     *ExecCredentialStatus `json:"status,omitempty"`
+}
+
+type ExecCredentialStatus struct {
+	Token               string     `json:"token"`
+	ExpirationTimestamp *time.Time `json:"expirationTimestamp,omitempty"`
+}
+
+func (ec *ExecCredential) JWTToken() *oauth2.Token {
+	token := &oauth2.Token{
+		AccessToken: ec.Status.Token,
+	}
+	if ec.Status.ExpirationTimestamp != nil {
+		token.Expiry = *ec.Status.ExpirationTimestamp
+	}
+	return token
+}
+
 type ConfigurationManagement struct {
 	SaveChangesRules []*rulesentities.SaveChangesRules `json:"saveChangesRules"`
 }
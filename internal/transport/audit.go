@@ -0,0 +1,99 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/mia-platform/miactl/internal/audit"
+	"github.com/mia-platform/miactl/internal/netutil"
+)
+
+type auditRoundTripper struct {
+	config AuditConfig
+	user   string
+	host   string
+	next   http.RoundTripper
+}
+
+// NewAuditRoundTripper return a RoundTripper that append an entry to the audit log for every request with a
+// method different from GET, HEAD and OPTIONS, once its response has been received
+func NewAuditRoundTripper(config AuditConfig, next http.RoundTripper) http.RoundTripper {
+	rt := &auditRoundTripper{config: config, next: next}
+	if current, err := user.Current(); err == nil {
+		rt.user = current.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		rt.host = host
+	}
+	return rt
+}
+
+func (rt *auditRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return rt.next.RoundTrip(req)
+	}
+
+	body, err := readRequestBody(req)
+	// the next RoundTripper receive a copy of the body, so the original one must be closed here
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clonedReq := netutil.CloneRequest(req)
+	if body != nil {
+		clonedReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	entry := &audit.Entry{
+		Timestamp:   time.Now().UTC(),
+		User:        rt.user,
+		Host:        rt.host,
+		Context:     rt.config.Context,
+		CompanyID:   rt.config.CompanyID,
+		ProjectID:   rt.config.ProjectID,
+		Environment: rt.config.Environment,
+		Command:     rt.config.Command,
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		BodyHash:    audit.HashBody(body),
+	}
+
+	response, err := rt.next.RoundTrip(clonedReq)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.StatusCode = response.StatusCode
+	}
+
+	// the request has already reached the server, so a failure of the audit log must not hide its result
+	if auditErr := audit.Append(rt.config.File, entry); auditErr != nil {
+		logr.FromContextOrDiscard(req.Context()).Error(auditErr, "the request has not been saved in the audit log")
+	}
+
+	return response, err
+}
@@ -0,0 +1,99 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mia-platform/miactl/internal/audit"
+)
+
+func TestAuditRoundTripper(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	config := AuditConfig{
+		File:        path,
+		Context:     "context",
+		CompanyID:   "company",
+		ProjectID:   "project",
+		Environment: "development",
+		Command:     "miactl deploy trigger",
+	}
+
+	var sentBody []byte
+	rt := NewAuditRoundTripper(config, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			sentBody, _ = io.ReadAll(req.Body)
+		}
+		return &http.Response{StatusCode: http.StatusAccepted, Body: http.NoBody}, nil
+	}))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.com/api/projects/", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.com/api/deploy/project/trigger?token=value", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "{}", string(sentBody), "the body must still be sent")
+
+	entries, err := audit.Read(path, audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "context", entry.Context)
+	assert.Equal(t, "company", entry.CompanyID)
+	assert.Equal(t, "project", entry.ProjectID)
+	assert.Equal(t, "development", entry.Environment)
+	assert.Equal(t, "miactl deploy trigger", entry.Command)
+	assert.Equal(t, http.MethodPost, entry.Method)
+	assert.Equal(t, "/api/deploy/project/trigger", entry.Path)
+	assert.Equal(t, http.StatusAccepted, entry.StatusCode)
+	assert.Equal(t, audit.HashBody([]byte("{}")), entry.BodyHash)
+	assert.False(t, entry.Timestamp.IsZero())
+}
+
+func TestAuditClosesRequestBody(t *testing.T) {
+	config := AuditConfig{File: filepath.Join(t.TempDir(), "audit.log")}
+	rt := NewAuditRoundTripper(config, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(body))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	body := &closeTrackingBody{Reader: strings.NewReader("{}")}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodDelete, "https://example.com/api/projects/project", body)
+	require.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("{}")), nil }
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, body.closed, "the original request body must be closed")
+}
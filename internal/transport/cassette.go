@@ -0,0 +1,550 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// cassetteVersion is the version of the json format used for saving the cassettes
+	cassetteVersion = 1
+	// harVersion is the version of the HTTP Archive format used when the cassette has the .har extension
+	harVersion   = "1.2"
+	harExtension = ".har"
+
+	redactedValue = "REDACTED"
+)
+
+// sensitiveFields contains the normalized names of the body fields and query parameters always masked
+var sensitiveFields = map[string]bool{
+	"accesstoken":     true,
+	"refreshtoken":    true,
+	"idtoken":         true,
+	"clientsecret":    true,
+	"clientassertion": true,
+	"assertion":       true,
+	"password":        true,
+	"privatekey":      true,
+	"privatekeydata":  true,
+	"devicecode":      true,
+	"codeverifier":    true,
+}
+
+// sensitiveFormFields contains the normalized names of the form fields and query parameters that are masked
+// in addition to sensitiveFields, because they have a too generic name for masking them in json bodies
+var sensitiveFormFields = map[string]bool{
+	"code":  true,
+	"token": true,
+}
+
+// recordingCassettes and replayCassettes contain the cassettes already opened by the process, so all the
+// transports created for the same file share the recorded interactions
+var (
+	recordingCassettes = make(map[string]*cassette)
+	replayCassettes    = make(map[string]*cassette)
+	cassettesMutex     sync.Mutex
+)
+
+// cassette contains the http interactions recorded in a file
+type cassette struct {
+	path         string
+	interactions []*interaction
+	// used keep track of the interactions already served during a replay
+	used  []bool
+	mutex sync.Mutex
+}
+
+// interaction is a single request and the response or the error received for it
+type interaction struct {
+	Request  recordedRequest   `json:"request"`
+	Response *recordedResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"headers,omitempty"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"status"`
+	Status     string      `json:"statusText"`
+	Header     http.Header `json:"headers,omitempty"`
+	recordedBody
+}
+
+// recordedBody contains a body saved as text, or base64 encoded if it is not valid utf-8
+type recordedBody struct {
+	Body         string `json:"body,omitempty"`
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+type cassetteFile struct {
+	Version      int            `json:"version"`
+	Interactions []*interaction `json:"interactions"`
+}
+
+// cassetteForRecording return the cassette that will be written at path, a cassette is truncated the first
+// time it is opened by the process
+func cassetteForRecording(path string) *cassette {
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+
+	if c, found := recordingCassettes[path]; found {
+		return c
+	}
+
+	c := &cassette{path: path}
+	recordingCassettes[path] = c
+	return c
+}
+
+// cassetteForReplay return the cassette read from path
+func cassetteForReplay(path string) (*cassette, error) {
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+
+	if c, found := replayCassettes[path]; found {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the cassette: %w", err)
+	}
+
+	var interactions []*interaction
+	if isHAR(path) {
+		interactions, err = interactionsFromHAR(data)
+	} else {
+		file := new(cassetteFile)
+		err = json.Unmarshal(data, file)
+		interactions = file.Interactions
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+
+	c := &cassette{path: path, interactions: interactions, used: make([]bool, len(interactions))}
+	replayCassettes[path] = c
+	return c, nil
+}
+
+// record append recorded to the cassette and write it again on disk, so that the file is always complete
+// even if the process is interrupted
+func (c *cassette) record(recorded *interaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.interactions = append(c.interactions, recorded)
+
+	var data []byte
+	var err error
+	if isHAR(c.path) {
+		data, err = json.MarshalIndent(harFromInteractions(c.interactions), "", "  ")
+	} else {
+		data, err = json.MarshalIndent(&cassetteFile{Version: cassetteVersion, Interactions: c.interactions}, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(c.path); len(dir) > 0 {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(c.path, data, fs.FileMode(0600))
+}
+
+// next return the first interaction not already served with the same method and url of req
+func (c *cassette) next(req *http.Request) (*interaction, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	requestURL := maskURL(req.URL)
+	for index, recorded := range c.interactions {
+		if c.used[index] || recorded.Request.Method != req.Method || recorded.Request.URL != requestURL {
+			continue
+		}
+
+		c.used[index] = true
+		return recorded, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response found in %s for %s %s", c.path, req.Method, requestURL)
+}
+
+func isHAR(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), harExtension)
+}
+
+// newRecordedBody return the body to save for data, masking the sensitive values if it has a known content type
+func newRecordedBody(data []byte, contentType string) recordedBody {
+	if len(data) == 0 {
+		return recordedBody{}
+	}
+
+	data = maskBody(data, contentType)
+	if !utf8.Valid(data) {
+		return recordedBody{Body: base64.StdEncoding.EncodeToString(data), BodyEncoding: "base64"}
+	}
+	return recordedBody{Body: string(data)}
+}
+
+// bytes return the original content of the body
+func (b recordedBody) bytes() ([]byte, error) {
+	if b.BodyEncoding == "base64" {
+		return base64.StdEncoding.DecodeString(b.Body)
+	}
+	return []byte(b.Body), nil
+}
+
+// maskHeader return a copy of header with the sensitive values masked
+func maskHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	masked := make(http.Header, len(header))
+	for key, values := range header {
+		for _, value := range values {
+			masked.Add(key, maskSensibleHeaderValue(key, value))
+		}
+	}
+	return masked
+}
+
+// maskURL return the string representation of target with the sensitive query parameters masked
+func maskURL(target *url.URL) string {
+	if len(target.RawQuery) == 0 {
+		return target.String()
+	}
+
+	masked := *target
+	query := target.Query()
+	maskValues(query)
+	masked.RawQuery = query.Encode()
+	return masked.String()
+}
+
+// maskBody return data with the values of the sensitive fields masked if it is a json document or a form
+func maskBody(data []byte, contentType string) []byte {
+	switch {
+	case strings.Contains(contentType, "json"):
+		var document any
+		if err := json.Unmarshal(data, &document); err != nil {
+			return data
+		}
+		masked, err := json.Marshal(maskJSON(document))
+		if err != nil {
+			return data
+		}
+		return masked
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return data
+		}
+		maskValues(values)
+		return []byte(values.Encode())
+	default:
+		return data
+	}
+}
+
+func maskJSON(document any) any {
+	switch value := document.(type) {
+	case map[string]any:
+		for key, fieldValue := range value {
+			if _, isString := fieldValue.(string); isString && sensitiveFields[normalizeFieldName(key)] {
+				value[key] = redactedValue
+				continue
+			}
+			value[key] = maskJSON(fieldValue)
+		}
+	case []any:
+		for index, item := range value {
+			value[index] = maskJSON(item)
+		}
+	}
+	return document
+}
+
+func maskValues(values url.Values) {
+	for key, fieldValues := range values {
+		name := normalizeFieldName(key)
+		if !sensitiveFields[name] && !sensitiveFormFields[name] {
+			continue
+		}
+		for index := range fieldValues {
+			fieldValues[index] = redactedValue
+		}
+	}
+}
+
+// normalizeFieldName return name lower case and without separators, so that client_secret and clientSecret match
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// the following types describe the subset of the HTTP Archive 1.2 format used by miactl, see
+// http://www.softwareishard.com/blog/har-12-spec/
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func harFromInteractions(interactions []*interaction) *harFile {
+	entries := make([]*harEntry, 0, len(interactions))
+	for _, recorded := range interactions {
+		milliseconds := float64(recorded.Duration.Microseconds()) / 1000
+		entry := &harEntry{
+			StartedDateTime: recorded.StartedAt,
+			Time:            milliseconds,
+			Request: harRequest{
+				Method:      recorded.Request.Method,
+				URL:         recorded.Request.URL,
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []harNameValue{},
+				Headers:     harHeaders(recorded.Request.Header),
+				QueryString: harQueryString(recorded.Request.URL),
+				HeadersSize: -1,
+				BodySize:    len(recorded.Request.Body),
+			},
+			Response: harResponse{
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []harNameValue{},
+				Headers:     []harNameValue{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings: harTimings{Send: 0, Wait: milliseconds, Receive: 0},
+			Comment: recorded.Error,
+		}
+
+		if len(recorded.Request.Body) > 0 {
+			// the request body is always saved as text, even if it has been base64 encoded
+			entry.Request.PostData = &harPostData{
+				MimeType: recorded.Request.Header.Get("Content-Type"),
+				Text:     recorded.Request.Body,
+			}
+		}
+
+		if response := recorded.Response; response != nil {
+			entry.Response.Status = response.StatusCode
+			entry.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(response.Status, fmt.Sprint(response.StatusCode)))
+			entry.Response.Headers = harHeaders(response.Header)
+			entry.Response.BodySize = len(response.Body)
+			entry.Response.Content = harContent{
+				Size:     len(response.Body),
+				MimeType: response.Header.Get("Content-Type"),
+				Text:     response.Body,
+				Encoding: response.BodyEncoding,
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return &harFile{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{Name: "miactl", Version: creatorVersion()},
+			Entries: entries,
+		},
+	}
+}
+
+func interactionsFromHAR(data []byte) ([]*interaction, error) {
+	file := new(harFile)
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, err
+	}
+
+	interactions := make([]*interaction, 0, len(file.Log.Entries))
+	for _, entry := range file.Log.Entries {
+		recorded := &interaction{
+			Request: recordedRequest{
+				Method: entry.Request.Method,
+				URL:    entry.Request.URL,
+				Header: headerFromHAR(entry.Request.Headers),
+			},
+			Error:     entry.Comment,
+			StartedAt: entry.StartedDateTime,
+			Duration:  time.Duration(entry.Time * float64(time.Millisecond)),
+		}
+		if entry.Request.PostData != nil {
+			recorded.Request.Body = entry.Request.PostData.Text
+		}
+
+		// a response with status zero is how the HAR format represents a request that has not received one
+		if entry.Response.Status != 0 {
+			recorded.Response = &recordedResponse{
+				StatusCode: entry.Response.Status,
+				Status:     strings.TrimSpace(fmt.Sprintf("%d %s", entry.Response.Status, entry.Response.StatusText)),
+				Header:     headerFromHAR(entry.Response.Headers),
+				recordedBody: recordedBody{
+					Body:         entry.Response.Content.Text,
+					BodyEncoding: entry.Response.Content.Encoding,
+				},
+			}
+		} else if len(recorded.Error) == 0 {
+			return nil, errors.New("entry without response and error")
+		}
+
+		interactions = append(interactions, recorded)
+	}
+
+	return interactions, nil
+}
+
+// creatorVersion return the version of the running binary, as saved by the go toolchain
+func creatorVersion() string {
+	if info, found := debug.ReadBuildInfo(); found && len(info.Main.Version) > 0 {
+		return info.Main.Version
+	}
+	return "unknown"
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	headers := make([]harNameValue, 0, len(header))
+	for key, values := range header {
+		for _, value := range values {
+			headers = append(headers, harNameValue{Name: key, Value: value})
+		}
+	}
+	sort.SliceStable(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	return headers
+}
+
+func harQueryString(rawURL string) []harNameValue {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return []harNameValue{}
+	}
+
+	query := parsed.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]harNameValue, 0, len(query))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			values = append(values, harNameValue{Name: key, Value: value})
+		}
+	}
+	return values
+}
+
+func headerFromHAR(headers []harNameValue) http.Header {
+	header := make(http.Header, len(headers))
+	for _, value := range headers {
+		header.Add(value.Name, value.Value)
+	}
+	return header
+}
@@ -15,7 +15,11 @@
 
 package transport
 
-import "net/http"
+import (
+	"io"
+	"net/http"
+	"time"
+)
 
 // Config transport layer configurations for setting up http.Transport
 type Config struct {
@@ -26,8 +30,59 @@ type Config struct {
 	UserAgent string
 	// AuthorizeWrapper will add authorization header to the wrapped RoundTripper
 	AuthorizeWrapper AuthorizeWrapperFunc
+	// BearerToken will be added as authorization header to every request, if set AuthorizeWrapper will be ignored
+	BearerToken string
 	// Verbose will add logging function to the call via a wrapper RoundTripper
 	Verbose bool
+	// Retry contains the settings for sending again the requests failed for a transient error
+	Retry RetryConfig
+	// Proxy contains the settings for sending the requests through a proxy different from the system one
+	Proxy ProxyConfig
+	// RecordFile is the path of a cassette where all the requests and responses will be saved
+	RecordFile string
+	// ReplayFile is the path of a cassette used for answering the requests in place of the network
+	ReplayFile string
+	// DryRun contains the settings for printing the requests that change the remote state in place of sending them
+	DryRun DryRunConfig
+	// Audit contains the settings for saving the requests that change the remote state in the audit log
+	Audit AuditConfig
+}
+
+type AuditConfig struct {
+	// File is the path of the audit log, if empty no request is saved
+	File string
+	// Context, CompanyID, ProjectID and Environment are saved with every request for knowing where it has been sent
+	Context     string
+	CompanyID   string
+	ProjectID   string
+	Environment string
+	// Command is the command line that has sent the requests
+	Command string
+}
+
+type DryRunConfig struct {
+	// Enabled intercept all the requests with a method different from GET, HEAD and OPTIONS
+	Enabled bool
+	// JSON print every intercepted request as a json object on its own line
+	JSON bool
+	// Output is where the intercepted requests are printed, os.Stderr if nil
+	Output io.Writer
+}
+
+// ProxyConfig contains the settings for sending the requests through a proxy
+type ProxyConfig struct {
+	// URL of the proxy used for all the requests, if empty the proxy set in the environment is used
+	URL string
+	// NoProxy is a comma separated list of hosts, domains, ip addresses or CIDRs that are reached directly
+	NoProxy string
+}
+
+// RetryConfig contains the settings for sending again the requests failed for a transient error
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of times a request is sent, a value of zero or one disable the retries
+	MaxAttempts int
+	// MaxDelay is the maximum wait between two attempts, zero means DefaultRetryMaxDelay
+	MaxDelay time.Duration
 }
 
 // TLSConfig contains settings to enable transport layer security
@@ -37,6 +92,13 @@ type TLSConfig struct {
 
 	// Trusted root certificates for server
 	CAFile string
+
+	// Client certificate and key for authenticating to a server requiring mutual TLS, the data fields
+	// are pem encoded and are used only when the corresponding file is not set
+	CertFile string
+	KeyFile  string
+	CertData []byte
+	KeyData  []byte
 }
 
 type AuthorizeWrapperFunc func(rt http.RoundTripper) http.RoundTripper
@@ -44,3 +106,13 @@ type AuthorizeWrapperFunc func(rt http.RoundTripper) http.RoundTripper
 func (c *Config) HasCA() bool {
 	return len(c.CAFile) > 0
 }
+
+// HasCertAuth return true if a client certificate or key has been set
+func (c *Config) HasCertAuth() bool {
+	return len(c.CertFile) > 0 || len(c.CertData) > 0 || len(c.KeyFile) > 0 || len(c.KeyData) > 0
+}
+
+// HasProxy return true if the proxy settings of the environment are changed
+func (c *Config) HasProxy() bool {
+	return len(c.Proxy.URL) > 0 || len(c.Proxy.NoProxy) > 0
+}
@@ -0,0 +1,151 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DryRunHeader is set on the synthetic responses returned for the intercepted requests
+const DryRunHeader = "Miactl-Dry-Run"
+
+type dryRunRoundTripper struct {
+	config DryRunConfig
+	lock   sync.Mutex
+	next   http.RoundTripper
+}
+
+// dryRunRequest is the json representation of an intercepted request
+type dryRunRequest struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        any    `json:"body,omitempty"`
+}
+
+// NewDryRunRoundTripper return a RoundTripper that send to next only the requests that cannot change the
+// remote state; the other requests are printed, with the credentials masked, and answered with a successful
+// response without a content for DELETE or with an empty json object
+func NewDryRunRoundTripper(config DryRunConfig, next http.RoundTripper) http.RoundTripper {
+	if config.Output == nil {
+		config.Output = os.Stderr
+	}
+	return &dryRunRoundTripper{config: config, next: next}
+}
+
+func (rt *dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return rt.next.RoundTrip(req)
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	if err := rt.print(req, body); err != nil {
+		return nil, fmt.Errorf("cannot print the dry run request: %w", err)
+	}
+
+	statusCode := http.StatusOK
+	responseBody := []byte("{}")
+	if req.Method == http.MethodDelete {
+		statusCode = http.StatusNoContent
+		responseBody = nil
+	}
+
+	header := make(http.Header)
+	header.Set(DryRunHeader, "true")
+	if responseBody != nil {
+		header.Set("Content-Type", "application/json")
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       req,
+	}, nil
+}
+
+// print write the request to the output, the lock keep the output of parallel requests separated
+func (rt *dryRunRoundTripper) print(req *http.Request, body []byte) error {
+	contentType := req.Header.Get("Content-Type")
+	if len(body) > 0 {
+		body = maskBody(body, contentType)
+	}
+
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+
+	if rt.config.JSON {
+		request := dryRunRequest{
+			Method:      req.Method,
+			URL:         maskURL(req.URL),
+			ContentType: contentType,
+		}
+		switch {
+		case len(body) == 0:
+		case json.Valid(body):
+			request.Body = json.RawMessage(body)
+		default:
+			request.Body = printableBody(body, contentType)
+		}
+		return json.NewEncoder(rt.config.Output).Encode(request)
+	}
+
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "DRY RUN: %s %s\n", req.Method, maskURL(req.URL))
+	if len(body) > 0 {
+		indented := new(bytes.Buffer)
+		if err := json.Indent(indented, body, "", "  "); err == nil {
+			builder.WriteString(indented.String())
+		} else {
+			builder.WriteString(printableBody(body, contentType))
+		}
+		builder.WriteString("\n")
+	}
+	_, err := io.WriteString(rt.config.Output, builder.String())
+	return err
+}
+
+// printableBody return body as a string if it is text, or a description of its content
+func printableBody(body []byte, contentType string) string {
+	if strings.HasPrefix(contentType, "text/") || strings.HasPrefix(contentType, "application/x-www-form-urlencoded") ||
+		strings.Contains(contentType, "yaml") {
+		return string(body)
+	}
+	if len(contentType) == 0 {
+		contentType = "unknown type"
+	}
+	return fmt.Sprintf("<%d bytes of %s>", len(body), contentType)
+}
@@ -0,0 +1,166 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunRoundTripper(t *testing.T) {
+	testCases := map[string]struct {
+		method         string
+		url            string
+		contentType    string
+		body           string
+		json           bool
+		expectForward  bool
+		expectedStatus int
+		expectedBody   string
+		expectedOutput string
+	}{
+		"get request is sent": {
+			method:        http.MethodGet,
+			url:           "https://example.com/api/projects/",
+			expectForward: true,
+		},
+		"head request is sent": {
+			method:        http.MethodHead,
+			url:           "https://example.com/api/projects/",
+			expectForward: true,
+		},
+		"post request is printed": {
+			method:         http.MethodPost,
+			url:            "https://example.com/api/projects/",
+			contentType:    "application/json",
+			body:           `{"name":"project","clientSecret":"secret"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{}",
+			expectedOutput: "DRY RUN: POST https://example.com/api/projects/\n{\n  \"clientSecret\": \"REDACTED\",\n  \"name\": \"project\"\n}\n",
+		},
+		"delete request is printed": {
+			method:         http.MethodDelete,
+			url:            "https://example.com/api/projects/id?access_token=token",
+			expectedStatus: http.StatusNoContent,
+			expectedOutput: "DRY RUN: DELETE https://example.com/api/projects/id?access_token=REDACTED\n",
+		},
+		"binary body": {
+			method:         http.MethodPut,
+			url:            "https://example.com/api/files",
+			contentType:    "application/octet-stream",
+			body:           "binary",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{}",
+			expectedOutput: "DRY RUN: PUT https://example.com/api/files\n<6 bytes of application/octet-stream>\n",
+		},
+		"json output": {
+			method:         http.MethodPatch,
+			url:            "https://example.com/api/projects/id",
+			contentType:    "application/json",
+			body:           `{"name": "project"}`,
+			json:           true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{}",
+			expectedOutput: `{"method":"PATCH","url":"https://example.com/api/projects/id","contentType":"application/json","body":{"name":"project"}}` + "\n",
+		},
+		"json output with text body": {
+			method:         http.MethodPost,
+			url:            "https://example.com/api/projects/",
+			contentType:    "text/plain",
+			body:           "some text",
+			json:           true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "{}",
+			expectedOutput: `{"method":"POST","url":"https://example.com/api/projects/","contentType":"text/plain","body":"some text"}` + "\n",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			next := &testRoundTripper{}
+			output := new(bytes.Buffer)
+			rt := NewDryRunRoundTripper(DryRunConfig{Enabled: true, JSON: testCase.json, Output: output}, next)
+
+			var body io.Reader
+			if len(testCase.body) > 0 {
+				body = strings.NewReader(testCase.body)
+			}
+			req, err := http.NewRequestWithContext(t.Context(), testCase.method, testCase.url, body)
+			require.NoError(t, err)
+			if len(testCase.contentType) > 0 {
+				req.Header.Set("Content-Type", testCase.contentType)
+			}
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			if testCase.expectForward {
+				assert.Same(t, req, next.Request)
+				assert.Empty(t, output.String())
+				return
+			}
+
+			defer resp.Body.Close()
+			assert.Nil(t, next.Request)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+			assert.Equal(t, "true", resp.Header.Get(DryRunHeader))
+			responseBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedBody, string(responseBody))
+			assert.Equal(t, testCase.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestDryRunWrapsAuthorization(t *testing.T) {
+	authorized := 0
+	config := &Config{
+		DryRun: DryRunConfig{Enabled: true, Output: io.Discard},
+		AuthorizeWrapper: func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				authorized++
+				return rt.RoundTrip(req)
+			})
+		},
+	}
+
+	next := &testRoundTripper{}
+	rt := roundTripperWrappersForConfig(config, next)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.com/api/projects/", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Zero(t, authorized, "intercepted requests must not need the authorization")
+
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.com/api/projects/", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req) //nolint:bodyclose
+	require.NoError(t, err)
+	assert.Equal(t, 1, authorized)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
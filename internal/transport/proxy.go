@@ -0,0 +1,107 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFuncFor return the function used by http.Transport for selecting the proxy of a request, the
+// requests to a host matching config.NoProxy are always sent directly, the other ones are sent to
+// config.URL or to the proxy set in the environment if it is empty
+func ProxyFuncFor(config ProxyConfig) (func(*http.Request) (*url.URL, error), error) {
+	var proxyURL *url.URL
+	if len(config.URL) > 0 {
+		parsed, err := url.Parse(config.URL)
+		if err != nil || len(parsed.Scheme) == 0 || len(parsed.Host) == 0 {
+			return nil, fmt.Errorf("invalid proxy url %q: must be an absolute url like http://proxy.example.com:3128", config.URL)
+		}
+		proxyURL = parsed
+	}
+
+	matchers := noProxyMatchers(config.NoProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		for _, matches := range matchers {
+			if matches(req.URL) {
+				return nil, nil
+			}
+		}
+
+		if proxyURL != nil {
+			return proxyURL, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// noProxyMatchers return a matcher for every entry of the comma separated noProxy list, an entry can be
+// "*" for matching all the hosts, an ip address, a CIDR or a domain name that will match also its
+// subdomains; ip addresses and domain names can contain a port
+func noProxyMatchers(noProxy string) []func(*url.URL) bool {
+	matchers := make([]func(*url.URL) bool, 0)
+	for entry := range strings.SplitSeq(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if len(entry) == 0 {
+			continue
+		}
+
+		if entry == "*" {
+			matchers = append(matchers, func(*url.URL) bool { return true })
+			continue
+		}
+
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			matchers = append(matchers, func(target *url.URL) bool {
+				ip := net.ParseIP(target.Hostname())
+				return ip != nil && network.Contains(ip)
+			})
+			continue
+		}
+
+		host, port := entry, ""
+		if splitHost, splitPort, err := net.SplitHostPort(entry); err == nil {
+			host, port = splitHost, splitPort
+		}
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "*"), ".")
+
+		matchers = append(matchers, func(target *url.URL) bool {
+			if len(port) > 0 && port != targetPort(target) {
+				return false
+			}
+
+			hostname := strings.ToLower(target.Hostname())
+			return hostname == host || strings.HasSuffix(hostname, "."+host)
+		})
+	}
+
+	return matchers
+}
+
+// targetPort return the port of target, or the default one of its scheme
+func targetPort(target *url.URL) string {
+	if port := target.Port(); len(port) > 0 {
+		return port
+	}
+
+	if target.Scheme == "http" {
+		return "80"
+	}
+	return "443"
+}
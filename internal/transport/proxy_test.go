@@ -0,0 +1,113 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyFuncFor(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+
+	proxyURL := "http://proxy.example.com:3128"
+	noProxy := "internal.example.com, .corp.example.com, 10.0.0.0/8, 192.168.1.1, local.example.com:8443"
+	testCases := map[string]struct {
+		config        ProxyConfig
+		requestURL    string
+		expectedProxy string
+	}{
+		"proxied request": {
+			config:        ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL:    "https://console.example.com/api",
+			expectedProxy: proxyURL,
+		},
+		"exact host": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL: "https://internal.example.com/api",
+		},
+		"subdomain of host": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL: "https://console.internal.example.com/api",
+		},
+		"domain with leading dot": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL: "https://console.corp.example.com/api",
+		},
+		"similar domain is proxied": {
+			config:        ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL:    "https://notinternal.example.com/api",
+			expectedProxy: proxyURL,
+		},
+		"ip in cidr": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL: "https://10.1.2.3/api",
+		},
+		"ip address": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL: "http://192.168.1.1:8080/api",
+		},
+		"host with matching port": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL: "https://local.example.com:8443/api",
+		},
+		"host with different port": {
+			config:        ProxyConfig{URL: proxyURL, NoProxy: noProxy},
+			requestURL:    "https://local.example.com/api",
+			expectedProxy: proxyURL,
+		},
+		"wildcard": {
+			config:     ProxyConfig{URL: proxyURL, NoProxy: "*"},
+			requestURL: "https://console.example.com/api",
+		},
+		"no proxy url uses environment": {
+			config:     ProxyConfig{NoProxy: noProxy},
+			requestURL: "https://console.example.com/api",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			proxyFunc, err := ProxyFuncFor(testCase.config)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodGet, testCase.requestURL, nil)
+			require.NoError(t, err)
+
+			proxy, err := proxyFunc(request)
+			require.NoError(t, err)
+			if len(testCase.expectedProxy) == 0 {
+				assert.Nil(t, proxy)
+				return
+			}
+
+			require.NotNil(t, proxy)
+			assert.Equal(t, testCase.expectedProxy, proxy.String())
+		})
+	}
+}
+
+func TestInvalidProxyURL(t *testing.T) {
+	for _, proxyURL := range []string{"proxy.example.com:3128", "://proxy", "/relative"} {
+		_, err := ProxyFuncFor(ProxyConfig{URL: proxyURL})
+		assert.ErrorContains(t, err, "invalid proxy url", proxyURL)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/mia-platform/miactl/internal/netutil"
+)
+
+type recordRoundTripper struct {
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+// NewRecordRoundTripper return a RoundTripper that save every request and its response in the cassette
+// file at path, masking the credentials found in the headers and in the bodies; if the path has the
+// .har extension the file is written in the HTTP Archive format
+func NewRecordRoundTripper(path string, next http.RoundTripper) http.RoundTripper {
+	return &recordRoundTripper{
+		cassette: cassetteForRecording(path),
+		next:     next,
+	}
+}
+
+func (rt *recordRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	// the next RoundTripper receive a copy of the body, so the original one must be closed here
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clonedReq := netutil.CloneRequest(req)
+	if requestBody != nil {
+		clonedReq.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	recorded := &interaction{
+		Request: recordedRequest{
+			Method:       req.Method,
+			URL:          maskURL(req.URL),
+			Header:       maskHeader(req.Header),
+			recordedBody: newRecordedBody(requestBody, req.Header.Get("Content-Type")),
+		},
+		StartedAt: time.Now(),
+	}
+
+	response, err := rt.next.RoundTrip(clonedReq)
+	if err != nil {
+		recorded.Error = err.Error()
+		recorded.Duration = time.Since(recorded.StartedAt)
+		rt.save(req, recorded)
+		return response, err
+	}
+
+	// the interaction is saved when the body has been read, so streamed responses are not blocked
+	response.Body = &recordingBody{
+		ReadCloser: response.Body,
+		done: func(body []byte) {
+			recorded.Duration = time.Since(recorded.StartedAt)
+			recorded.Response = &recordedResponse{
+				StatusCode:   response.StatusCode,
+				Status:       response.Status,
+				Header:       maskHeader(response.Header),
+				recordedBody: newRecordedBody(body, response.Header.Get("Content-Type")),
+			}
+			rt.save(req, recorded)
+		},
+	}
+	return response, nil
+}
+
+// save write the interaction in the cassette, a failure is only logged for not breaking the command
+func (rt *recordRoundTripper) save(req *http.Request, recorded *interaction) {
+	if err := rt.cassette.record(recorded); err != nil {
+		logr.FromContextOrDiscard(req.Context()).V(0).Info(fmt.Sprintf("cannot record the request in %s: %s", rt.cassette.path, err))
+	}
+}
+
+// readRequestBody return the body of req without consuming it
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	return data, err
+}
+
+// recordingBody copy the data read from a response body and call done with all of it once the body has
+// been read completely or closed
+type recordingBody struct {
+	io.ReadCloser
+	buffer bytes.Buffer
+	once   sync.Once
+	done   func([]byte)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buffer.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		b.once.Do(func() { b.done(b.buffer.Bytes()) })
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.once.Do(func() { b.done(b.buffer.Bytes()) })
+	return b.ReadCloser.Close()
+}
+
+type replayRoundTripper struct {
+	cassette *cassette
+}
+
+// NewReplayRoundTripper return a RoundTripper that never contact the network, and answer every request with
+// the first response not already used that has been recorded in the cassette at path for the same method
+// and url
+func NewReplayRoundTripper(path string) (http.RoundTripper, error) {
+	cassette, err := cassetteForReplay(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &replayRoundTripper{cassette: cassette}, nil
+}
+
+func (rt *replayRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	recorded, err := rt.cassette.next(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if recorded.Response == nil {
+		return nil, errors.New(recorded.Error)
+	}
+
+	body, err := recorded.Response.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid body recorded for %s %s: %w", req.Method, recorded.Request.URL, err)
+	}
+
+	header := recorded.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        recorded.Response.Status,
+		StatusCode:    recorded.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
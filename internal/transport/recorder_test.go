@@ -0,0 +1,217 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recorderTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/oauth/token":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.Write([]byte(`{"access_token":"secret-token","refresh_token":"secret-refresh","expires_in":3600}`))
+		case "/api/projects":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"_id":"project","name":"Project"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	for _, fileName := range []string{"cassette.json", "cassette.har"} {
+		t.Run(fileName, func(t *testing.T) {
+			server := recorderTestServer(t)
+			cassettePath := filepath.Join(t.TempDir(), fileName)
+
+			recorder, err := NewTransport(&Config{RecordFile: cassettePath})
+			require.NoError(t, err)
+
+			tokenRequest, err := http.NewRequest(http.MethodPost, server.URL+"/api/oauth/token", strings.NewReader("grant_type=client_credentials&client_secret=secret-value"))
+			require.NoError(t, err)
+			tokenRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			tokenRequest.Header.Set("Authorization", "Basic c2VjcmV0")
+			tokenBody := roundTripBody(t, recorder, tokenRequest)
+			assert.Contains(t, tokenBody, "secret-token", "the recording must not change the response")
+
+			projectsRequest, err := http.NewRequest(http.MethodGet, server.URL+"/api/projects?search=name&code=secret-code", nil)
+			require.NoError(t, err)
+			projectsRequest.Header.Set("Authorization", "Bearer secret-token")
+			projectsBody := roundTripBody(t, recorder, projectsRequest)
+
+			_, err = recorder.RoundTrip(mustRequest(t, http.MethodGet, "http://127.0.0.1:1/unreachable"))
+			require.Error(t, err)
+
+			data, err := os.ReadFile(cassettePath)
+			require.NoError(t, err)
+			for _, secret := range []string{"secret-token", "secret-refresh", "secret-value", "secret-code", "c2VjcmV0", "session=secret"} {
+				assert.NotContains(t, string(data), secret)
+			}
+			assert.Contains(t, string(data), "Bearer REDACTED")
+
+			replay, err := NewTransport(&Config{ReplayFile: cassettePath})
+			require.NoError(t, err)
+
+			replayedProjectsBody := roundTripBody(t, replay, mustRequest(t, http.MethodGet, server.URL+"/api/projects?code=other&search=name"))
+			assert.Equal(t, projectsBody, replayedProjectsBody)
+
+			// every interaction is served only once
+			_, err = replay.RoundTrip(mustRequest(t, http.MethodGet, server.URL+"/api/projects?code=other&search=name"))
+			assert.ErrorContains(t, err, "no recorded response found")
+
+			tokenRequest, err = http.NewRequest(http.MethodPost, server.URL+"/api/oauth/token", strings.NewReader("grant_type=client_credentials"))
+			require.NoError(t, err)
+			replayedTokenBody := roundTripBody(t, replay, tokenRequest)
+			assert.JSONEq(t, `{"access_token":"REDACTED","refresh_token":"REDACTED","expires_in":3600}`, replayedTokenBody)
+
+			_, err = replay.RoundTrip(mustRequest(t, http.MethodGet, "http://127.0.0.1:1/unreachable"))
+			assert.Error(t, err)
+			assert.NotContains(t, err.Error(), "no recorded response found")
+		})
+	}
+}
+
+func TestReplayInvalidCassette(t *testing.T) {
+	tempDir := t.TempDir()
+	_, err := NewTransport(&Config{ReplayFile: filepath.Join(tempDir, "missing.json")})
+	assert.ErrorContains(t, err, "cannot read the cassette")
+
+	invalidPath := filepath.Join(tempDir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("not json"), 0600))
+	_, err = NewTransport(&Config{ReplayFile: invalidPath})
+	assert.ErrorContains(t, err, "invalid cassette")
+}
+
+func TestRecordingBody(t *testing.T) {
+	var recorded []byte
+	calls := 0
+	body := &recordingBody{
+		ReadCloser: io.NopCloser(strings.NewReader("streamed body")),
+		done: func(data []byte) {
+			calls++
+			recorded = append([]byte{}, data...)
+		},
+	}
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "streamed body", string(data))
+	assert.Equal(t, "streamed body", string(recorded))
+	assert.Equal(t, 1, calls)
+}
+
+func TestMaskBody(t *testing.T) {
+	testCases := map[string]struct {
+		body        string
+		contentType string
+		expected    string
+	}{
+		"json body": {
+			body:        `{"clientSecret":"secret","name":"service","nested":[{"private_key":"key","code":"kept"}]}`,
+			contentType: "application/json; charset=utf-8",
+			expected:    `{"clientSecret":"REDACTED","name":"service","nested":[{"code":"kept","private_key":"REDACTED"}]}`,
+		},
+		"form body": {
+			body:        "code=secret&grant_type=authorization_code&password=secret",
+			contentType: "application/x-www-form-urlencoded",
+			expected:    "code=REDACTED&grant_type=authorization_code&password=REDACTED",
+		},
+		"other body": {
+			body:        "password=secret",
+			contentType: "text/plain",
+			expected:    "password=secret",
+		},
+		"invalid json": {
+			body:        `{"password":`,
+			contentType: "application/json",
+			expected:    `{"password":`,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, string(maskBody([]byte(testCase.body), testCase.contentType)))
+		})
+	}
+}
+
+func mustRequest(t *testing.T, method, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	return req
+}
+
+func roundTripBody(t *testing.T, roundTripper http.RoundTripper, req *http.Request) string {
+	t.Helper()
+	resp, err := roundTripper.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		require.NoError(t, err)
+	}
+	return string(data)
+}
+
+func TestRecordClosesRequestBody(t *testing.T) {
+	rt := NewRecordRoundTripper(filepath.Join(t.TempDir(), "cassette.json"), roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(body))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}))
+
+	body := &closeTrackingBody{Reader: strings.NewReader("{}")}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.com/api/projects", body)
+	require.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("{}")), nil }
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, body.closed, "the original request body must be closed")
+}
+
+// closeTrackingBody is a request body that remember if it has been closed
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
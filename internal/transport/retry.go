@@ -0,0 +1,239 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/mia-platform/miactl/internal/netutil"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of attempts made for a request by the cli when it is not configured
+	DefaultRetryMaxAttempts = 4
+	// DefaultRetryMaxDelay is the maximum wait between two attempts when RetryConfig.MaxDelay is zero
+	DefaultRetryMaxDelay = 30 * time.Second
+
+	// retryBaseDelay is the wait before the first retry, it is doubled at every following attempt
+	retryBaseDelay = 500 * time.Millisecond
+)
+
+type retryRoundTripper struct {
+	maxAttempts int
+	maxDelay    time.Duration
+	baseDelay   time.Duration
+	next        http.RoundTripper
+}
+
+// NewRetryRoundTripper return a RoundTripper that send again the requests with a safe method that have failed
+// for a connection error or have received a 429, 502, 503 or 504 status code, waiting an exponential backoff
+// with jitter or the time requested by the Retry-After header between the attempts; the PUT and DELETE
+// requests are sent again only if they have failed before being written, because the server can have
+// already applied them
+func NewRetryRoundTripper(config RetryConfig, next http.RoundTripper) http.RoundTripper {
+	maxDelay := config.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	return &retryRoundTripper{
+		maxAttempts: config.MaxAttempts,
+		maxDelay:    maxDelay,
+		baseDelay:   retryBaseDelay,
+		next:        next,
+	}
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.maxAttempts <= 1 || !isRetryable(req.Method) {
+		return rt.next.RoundTrip(req)
+	}
+
+	logger := logr.FromContextOrDiscard(req.Context())
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := netutil.RewindRequest(req)
+		if err != nil {
+			// the body cannot be sent again, so only one attempt is possible
+			return rt.next.RoundTrip(req)
+		}
+
+		written := new(atomic.Bool)
+		if !isSafe(req.Method) {
+			attemptReq = traceWrite(attemptReq, written)
+		}
+
+		resp, err := rt.next.RoundTrip(attemptReq)
+		retry := shouldRetry(req.Context(), resp, err)
+		if !isSafe(req.Method) {
+			retry = retry && err != nil && !written.Load()
+		}
+		if attempt >= rt.maxAttempts || !retry {
+			return resp, err
+		}
+
+		delay, ok := rt.delayForAttempt(attempt, resp)
+		if !ok || !canWait(req.Context(), delay) {
+			return resp, err
+		}
+
+		reason := fmt.Sprintf("%v", err)
+		if err == nil {
+			reason = resp.Status
+			discardBody(resp)
+		}
+		logger.V(5).Info(fmt.Sprintf("%s %s failed with %s, retrying in %s", req.Method, req.URL.String(), reason, delay.Round(time.Millisecond)))
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// delayForAttempt return how much to wait before the attempt following the one numbered attempt, if the
+// server has asked with Retry-After to wait more than the max delay false is returned
+func (rt *retryRoundTripper) delayForAttempt(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if retryAfter, found := parseRetryAfter(resp.Header.Get("Retry-After")); found {
+			return retryAfter, retryAfter <= rt.maxDelay
+		}
+	}
+
+	delay := rt.maxDelay
+	if shift := attempt - 1; shift < 32 {
+		delay = min(rt.baseDelay<<shift, rt.maxDelay)
+	}
+
+	// use a random delay between half and the full backoff for avoiding parallel clients retrying together
+	half := delay / 2
+	return half + rand.N(half+1), true //nolint:gosec // the jitter does not need a secure random source
+}
+
+// isSafe return true for the methods that do not change the remote state, so they can always be sent again
+func isSafe(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable return true for the methods that can be sent again at least when they have not reached the
+// server; POST and PATCH are never sent again
+func isRetryable(method string) bool {
+	return isSafe(method) || method == http.MethodPut || method == http.MethodDelete
+}
+
+// traceWrite return a copy of req that set written when the request has been written on the connection
+func traceWrite(req *http.Request, written *atomic.Bool) *http.Request {
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) { written.Store(true) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// shouldRetry return true if the response or the error are caused by a transient failure
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return !isPermanentError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPermanentError return true for the errors that will not change sending the request again
+func isPermanentError(err error) bool {
+	var certificateErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &certificateErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// parseRetryAfter return the wait requested by a Retry-After header, that can contain the seconds to wait
+// or an http date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(date), 0), true
+}
+
+// canWait return false if the context deadline will expire before the end of delay
+func canWait(ctx context.Context, delay time.Duration) bool {
+	deadline, found := ctx.Deadline()
+	return !found || time.Until(deadline) > delay
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardBody read and close the body of a response that will not be returned, so the connection can be reused
+func discardBody(resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
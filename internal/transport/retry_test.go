@@ -0,0 +1,244 @@
+// Copyright Mia srl
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceRoundTripper return its responses in order, recording the body of every request received; the
+// attempts flagged in written notify the client trace that the request has been written before failing
+type sequenceRoundTripper struct {
+	responses []*http.Response
+	errs      []error
+	written   []bool
+	bodies    []string
+}
+
+func (rt *sequenceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt := len(rt.bodies)
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	rt.bodies = append(rt.bodies, body)
+
+	if attempt < len(rt.written) && rt.written[attempt] {
+		if trace := httptrace.ContextClientTrace(req.Context()); trace != nil && trace.WroteRequest != nil {
+			trace.WroteRequest(httptrace.WroteRequestInfo{})
+		}
+	}
+
+	var err error
+	if attempt < len(rt.errs) {
+		err = rt.errs[attempt]
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rt.responses[attempt], nil
+}
+
+func statusResponse(statusCode int, headers ...string) *http.Response {
+	header := make(http.Header)
+	for i := 0; i+1 < len(headers); i += 2 {
+		header.Set(headers[i], headers[i+1])
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+func TestRetryRoundTripper(t *testing.T) {
+	connectionErr := errors.New("connection reset by peer")
+	testCases := map[string]struct {
+		method           string
+		responses        []*http.Response
+		errs             []error
+		written          []bool
+		maxAttempts      int
+		expectedStatus   int
+		expectedErr      error
+		expectedAttempts int
+	}{
+		"success at first attempt": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{statusResponse(http.StatusOK)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 1,
+		},
+		"retry bad gateway": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{statusResponse(http.StatusBadGateway), statusResponse(http.StatusServiceUnavailable), statusResponse(http.StatusOK)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		"retry connection error with body": {
+			method:           http.MethodPut,
+			responses:        []*http.Response{nil, statusResponse(http.StatusOK)},
+			errs:             []error{connectionErr},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		"do not retry put after the request has been written": {
+			method:           http.MethodPut,
+			responses:        []*http.Response{nil},
+			errs:             []error{connectionErr},
+			written:          []bool{true},
+			maxAttempts:      3,
+			expectedErr:      connectionErr,
+			expectedAttempts: 1,
+		},
+		"do not retry put on bad gateway": {
+			method:           http.MethodPut,
+			responses:        []*http.Response{statusResponse(http.StatusBadGateway)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusBadGateway,
+			expectedAttempts: 1,
+		},
+		"do not retry delete on service unavailable": {
+			method:           http.MethodDelete,
+			responses:        []*http.Response{statusResponse(http.StatusServiceUnavailable)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+		},
+		"stop after max attempts": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{statusResponse(http.StatusGatewayTimeout), statusResponse(http.StatusGatewayTimeout)},
+			maxAttempts:      2,
+			expectedStatus:   http.StatusGatewayTimeout,
+			expectedAttempts: 2,
+		},
+		"do not retry post": {
+			method:           http.MethodPost,
+			responses:        []*http.Response{statusResponse(http.StatusBadGateway)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusBadGateway,
+			expectedAttempts: 1,
+		},
+		"do not retry client errors": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{statusResponse(http.StatusNotFound)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusNotFound,
+			expectedAttempts: 1,
+		},
+		"honor retry after": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{statusResponse(http.StatusTooManyRequests, "Retry-After", "0"), statusResponse(http.StatusOK)},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		"retry after longer than max delay": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{statusResponse(http.StatusTooManyRequests, "Retry-After", "3600")},
+			maxAttempts:      3,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedAttempts: 1,
+		},
+		"do not retry canceled requests": {
+			method:           http.MethodGet,
+			responses:        []*http.Response{nil},
+			errs:             []error{context.Canceled},
+			maxAttempts:      3,
+			expectedErr:      context.Canceled,
+			expectedAttempts: 1,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			next := &sequenceRoundTripper{responses: testCase.responses, errs: testCase.errs, written: testCase.written}
+			rt := NewRetryRoundTripper(RetryConfig{MaxAttempts: testCase.maxAttempts, MaxDelay: time.Second}, next)
+			rt.(*retryRoundTripper).baseDelay = time.Millisecond
+
+			req, err := http.NewRequestWithContext(t.Context(), testCase.method, "https://example.com", strings.NewReader("body"))
+			require.NoError(t, err)
+
+			resp, err := rt.RoundTrip(req)
+			assert.Len(t, next.bodies, testCase.expectedAttempts)
+			for _, body := range next.bodies {
+				assert.Equal(t, "body", body)
+			}
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRetryRoundTripperDeadline(t *testing.T) {
+	next := &sequenceRoundTripper{responses: []*http.Response{statusResponse(http.StatusServiceUnavailable, "Retry-After", "1")}}
+	rt := NewRetryRoundTripper(RetryConfig{MaxAttempts: 3}, next)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, next.bodies, 1)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, found := parseRetryAfter("5")
+	assert.True(t, found)
+	assert.Equal(t, 5*time.Second, delay)
+
+	delay, found = parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, found)
+	assert.InDelta(t, time.Minute, delay, float64(2*time.Second))
+
+	_, found = parseRetryAfter("soon")
+	assert.False(t, found)
+	_, found = parseRetryAfter("")
+	assert.False(t, found)
+}
+
+func TestRetryDelayForAttempt(t *testing.T) {
+	rt := &retryRoundTripper{maxDelay: 4 * time.Second, baseDelay: time.Second}
+	for attempt, expectedMax := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: 4 * time.Second, 100: 4 * time.Second} {
+		delay, ok := rt.delayForAttempt(attempt, nil)
+		assert.True(t, ok)
+		assert.LessOrEqual(t, delay, expectedMax)
+		assert.GreaterOrEqual(t, delay, expectedMax/2)
+	}
+}
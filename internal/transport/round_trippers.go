@@ -27,18 +27,42 @@ import (
 )
 
 func roundTripperWrappersForConfig(config *Config, roundTripper http.RoundTripper) http.RoundTripper {
+	// record the requests as they are sent on the network, including every attempt and the authorization flow
+	if len(config.RecordFile) > 0 {
+		roundTripper = NewRecordRoundTripper(config.RecordFile, roundTripper)
+	}
+
 	if config.Verbose {
 		roundTripper = NewDebugRoundTripper(roundTripper)
 	}
 
+	// wrap the debug roundTripper for logging every attempt
+	if config.Retry.MaxAttempts > 1 {
+		roundTripper = NewRetryRoundTripper(config.Retry, roundTripper)
+	}
+
 	if len(config.UserAgent) > 0 {
 		roundTripper = NewUserAgentRoundTripper(config.UserAgent, roundTripper)
 	}
 
 	// keep this wrapping as the latest possible to allow the reusage of the roundTripper during auth flow
-	if config.AuthorizeWrapper != nil {
+	switch {
+	case len(config.BearerToken) > 0:
+		roundTripper = NewBearerAuthRoundTripper(config.BearerToken, roundTripper)
+	case config.AuthorizeWrapper != nil:
 		roundTripper = config.AuthorizeWrapper(roundTripper)
 	}
+
+	// the audit log save the requests that reach the server, once for all their attempts and without the
+	// requests of the authorization flow
+	if len(config.Audit.File) > 0 {
+		roundTripper = NewAuditRoundTripper(config.Audit, roundTripper)
+	}
+
+	// the intercepted requests never reach the authorization, so no login is needed for printing them
+	if config.DryRun.Enabled {
+		roundTripper = NewDryRunRoundTripper(config.DryRun, roundTripper)
+	}
 	return roundTripper
 }
 
@@ -67,6 +91,10 @@ func (rt *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	requestStartTime := time.Now()
 	response, err := rt.next.RoundTrip(clonedReq)
 	requestEndTime := time.Since(requestStartTime)
+	if err != nil {
+		logger.V(6).Info(fmt.Sprintf("Request failed: %s in %d milliseconds", err, requestEndTime.Milliseconds()))
+		return response, err
+	}
 	logger.V(6).Info(fmt.Sprintf("Response Status: %s in %d milliseconds", response.Status, requestEndTime.Milliseconds()))
 	logger.V(7).Info("Response Headers:")
 	for headerKey, headerValues := range response.Header {
@@ -79,16 +107,21 @@ func (rt *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 }
 
 func maskSensibleHeaderValue(headerKey string, value string) string {
-	// mask value only if the header is "Authorization"
-	if !strings.EqualFold(headerKey, "Authorization") {
-		return value
-	}
-
 	// don't do anything if the value is empty
 	if len(value) == 0 {
 		return ""
 	}
 
+	// cookies can contain session tokens and have no scheme to preserve
+	if strings.EqualFold(headerKey, "Cookie") || strings.EqualFold(headerKey, "Set-Cookie") {
+		return redactedValue
+	}
+
+	// mask value only if the header is "Authorization" or "Proxy-Authorization"
+	if !strings.EqualFold(headerKey, "Authorization") && !strings.EqualFold(headerKey, "Proxy-Authorization") {
+		return value
+	}
+
 	var authType string
 	if i := strings.Index(value, " "); i > 0 {
 		authType = value[0:i]
@@ -143,3 +176,24 @@ func (rt *userAgentRoundTripper) RoundTrip(req *http.Request) (*http.Response, e
 	clonedReq.Header.Set("User-Agent", rt.userAgent)
 	return rt.next.RoundTrip(clonedReq)
 }
+
+type bearerAuthRoundTripper struct {
+	token string
+	next  http.RoundTripper
+}
+
+func NewBearerAuthRoundTripper(token string, next http.RoundTripper) http.RoundTripper {
+	return &bearerAuthRoundTripper{
+		token: token,
+		next:  next,
+	}
+}
+
+func (rt *bearerAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(req.Header.Get("Authorization")) != 0 {
+		return rt.next.RoundTrip(req)
+	}
+	clonedReq := netutil.CloneRequest(req)
+	clonedReq.Header.Set("Authorization", "Bearer "+rt.token)
+	return rt.next.RoundTrip(clonedReq)
+}
@@ -74,6 +74,35 @@ func TestUserAgentRoundTripper(t *testing.T) {
 	})
 }
 
+func TestBearerAuthRoundTripper(t *testing.T) {
+	rt := &testRoundTripper{}
+
+	t.Run("authorization header already present", func(t *testing.T) {
+		req := &http.Request{
+			Header: make(http.Header),
+		}
+		req.Header.Set("Authorization", "Basic other")
+		// turn off bodyclose, because we don't have body to close here...
+		NewBearerAuthRoundTripper("token", rt).RoundTrip(req) //nolint:bodyclose
+		require.NotNil(t, rt.Request)
+
+		rtRequest := rt.Request
+		assert.Same(t, rtRequest, req)
+		assert.Equal(t, "Basic other", rtRequest.Header.Get("Authorization"))
+	})
+
+	t.Run("missing authorization in request", func(t *testing.T) {
+		req := &http.Request{}
+		// turn off bodyclose, because we don't have body to close here...
+		NewBearerAuthRoundTripper("token", rt).RoundTrip(req) //nolint:bodyclose
+		require.NotNil(t, rt.Request)
+
+		rtRequest := rt.Request
+		assert.NotSame(t, rtRequest, req)
+		assert.Equal(t, "Bearer token", rtRequest.Header.Get("Authorization"))
+	})
+}
+
 type testAuthTripper struct{}
 
 func (rt *testAuthTripper) RoundTrip(_ *http.Request) (*http.Response, error) { return nil, nil }
@@ -93,6 +122,10 @@ func TestRoundTripperWrapping(t *testing.T) {
 			config:       &Config{Verbose: true},
 			expectedType: &debugRoundTripper{},
 		},
+		"retry": {
+			config:       &Config{Retry: RetryConfig{MaxAttempts: 2}},
+			expectedType: &retryRoundTripper{},
+		},
 		"user agent": {
 			config:       &Config{UserAgent: "foo"},
 			expectedType: &userAgentRoundTripper{},
@@ -103,6 +136,17 @@ func TestRoundTripperWrapping(t *testing.T) {
 			},
 			expectedType: &testAuthTripper{},
 		},
+		"bearer token": {
+			config:       &Config{BearerToken: "token"},
+			expectedType: &bearerAuthRoundTripper{},
+		},
+		"bearer token wins over auth wrapper": {
+			config: &Config{
+				BearerToken:      "token",
+				AuthorizeWrapper: func(_ http.RoundTripper) http.RoundTripper { return &testAuthTripper{} },
+			},
+			expectedType: &bearerAuthRoundTripper{},
+		},
 		"both config, return auth wrapper": {
 			config: &Config{
 				UserAgent:        "foo",
@@ -164,6 +208,16 @@ func TestRedactSensibleHeaders(t *testing.T) {
 			value:    "",
 			expected: "",
 		},
+		{
+			key:      "Proxy-Authorization",
+			value:    "Basic dXNlcjpwYXNzd29yZA==",
+			expected: "Basic REDACTED",
+		},
+		{
+			key:      "Set-Cookie",
+			value:    "session=secret; Path=/",
+			expected: "REDACTED",
+		},
 	}
 
 	for _, testCase := range testCases {
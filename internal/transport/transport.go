@@ -26,31 +26,38 @@ import (
 )
 
 func NewTransport(config *Config) (http.RoundTripper, error) {
+	// a replayed cassette replace the network, so no connection setting is used
+	if len(config.ReplayFile) > 0 {
+		replay, err := NewReplayRoundTripper(config.ReplayFile)
+		if err != nil {
+			return nil, err
+		}
+		return roundTripperWrappersForConfig(config, replay), nil
+	}
+
 	transport := http.DefaultTransport.(*http.Transport)
 
-	// if transport needs special configuration for TLS config create a custom one
-	if config.HasCA() || config.Insecure {
-		tlsConfig := &tls.Config{
-			MinVersion: tls.VersionTLS12,
-			// disable gosec because will trigger G402 but we want to be able to configure this for debug purprose
-			InsecureSkipVerify: config.Insecure, //nolint:gosec
+	tlsConfig, err := TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := transport.Proxy
+	if config.HasProxy() {
+		if proxy, err = ProxyFuncFor(config.Proxy); err != nil {
+			return nil, err
 		}
+	}
 
-		if config.CAFile != "" {
-			certData, err := dataFromFile(config.CAFile)
-			if err != nil {
-				return nil, err
-			}
-			certPool, err := certPool(certData)
-			if err != nil {
-				return nil, err
-			}
-			tlsConfig.RootCAs = certPool
+	// if transport needs special configuration for TLS config or proxy create a custom one
+	if tlsConfig != nil || config.HasProxy() {
+		if tlsConfig == nil {
+			tlsConfig = transport.TLSClientConfig
 		}
 
 		// read the default transport and use its default, and then set the TLSClientConfig
 		transport = &http.Transport{
-			Proxy:                 transport.Proxy,
+			Proxy:                 proxy,
 			DialContext:           transport.DialContext,
 			ForceAttemptHTTP2:     transport.ForceAttemptHTTP2,
 			MaxIdleConns:          transport.MaxIdleConns,
@@ -64,6 +71,75 @@ func NewTransport(config *Config) (http.RoundTripper, error) {
 	return roundTripperWrappersForConfig(config, transport), nil
 }
 
+// TLSConfigFor return the tls.Config described by config, or nil if the default one can be used
+func TLSConfigFor(config *Config) (*tls.Config, error) {
+	if !config.HasCA() && !config.Insecure && !config.HasCertAuth() {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// disable gosec because will trigger G402 but we want to be able to configure this for debug purprose
+		InsecureSkipVerify: config.Insecure, //nolint:gosec
+	}
+
+	if config.CAFile != "" {
+		certData, err := dataFromFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		certPool, err := certPool(certData)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if config.HasCertAuth() {
+		certificate, err := clientCertificate(config.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCertificate return the client certificate and key found in config, reading the files if set
+func clientCertificate(config TLSConfig) (tls.Certificate, error) {
+	certData := config.CertData
+	if len(config.CertFile) > 0 {
+		data, err := dataFromFile(config.CertFile)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		certData = data
+	}
+
+	keyData := config.KeyData
+	if len(config.KeyFile) > 0 {
+		data, err := dataFromFile(config.KeyFile)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		keyData = data
+	}
+
+	switch {
+	case len(certData) == 0:
+		return tls.Certificate{}, errors.New("a client key has been set without the client certificate")
+	case len(keyData) == 0:
+		return tls.Certificate{}, errors.New("a client certificate has been set without the client key")
+	}
+
+	certificate, err := tls.X509KeyPair(certData, keyData)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	return certificate, nil
+}
+
 // dataFromFile return the read data from filePath or an error if occurred
 func dataFromFile(filePath string) ([]byte, error) {
 	if len(filePath) > 0 {
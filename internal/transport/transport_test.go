@@ -36,7 +36,12 @@ import (
 
 func TestTransportForConfig(t *testing.T) {
 	wd, _ := os.Getwd()
-	caFilePath, keyFilePath, _ := generateCertificates(t)
+	caFilePath, keyFilePath, certFilePath := generateCertificates(t)
+	certData, err := os.ReadFile(certFilePath)
+	require.NoError(t, err)
+	keyData, err := os.ReadFile(keyFilePath)
+	require.NoError(t, err)
+
 	testCases := map[string]struct {
 		Config       *Config
 		Err          bool
@@ -44,6 +49,7 @@ func TestTransportForConfig(t *testing.T) {
 		Default      bool
 		Insecure     bool
 		DefaultRoots bool
+		ClientCert   bool
 	}{
 		"default transport": {
 			Default: true,
@@ -83,6 +89,56 @@ func TestTransportForConfig(t *testing.T) {
 				},
 			},
 		},
+		"client certificate files transport": {
+			TLS:          true,
+			DefaultRoots: true,
+			ClientCert:   true,
+			Config: &Config{
+				TLSConfig: TLSConfig{
+					CertFile: certFilePath,
+					KeyFile:  keyFilePath,
+				},
+			},
+		},
+		"client certificate data transport": {
+			TLS:          true,
+			DefaultRoots: true,
+			ClientCert:   true,
+			Config: &Config{
+				TLSConfig: TLSConfig{
+					CertData: certData,
+					KeyData:  keyData,
+				},
+			},
+		},
+		"client certificate without key": {
+			Err: true,
+			Config: &Config{
+				TLSConfig: TLSConfig{
+					CertFile: certFilePath,
+				},
+			},
+		},
+		"client certificate with wrong key": {
+			Err: true,
+			Config: &Config{
+				TLSConfig: TLSConfig{
+					CertFile: certFilePath,
+					KeyFile:  caFilePath,
+				},
+			},
+		},
+		"proxy transport": {
+			Config: &Config{
+				Proxy: ProxyConfig{URL: "http://proxy.example.com:3128"},
+			},
+		},
+		"invalid proxy transport": {
+			Err: true,
+			Config: &Config{
+				Proxy: ProxyConfig{URL: "proxy.example.com"},
+			},
+		},
 		"not a pem file transport": {
 			Err: true,
 			Config: &Config{
@@ -127,6 +183,11 @@ func TestTransportForConfig(t *testing.T) {
 			}
 
 			assert.Equal(t, testCase.Insecure, transport.TLSClientConfig.InsecureSkipVerify)
+			if testCase.ClientCert {
+				assert.Len(t, transport.TLSClientConfig.Certificates, 1)
+			} else {
+				assert.Empty(t, transport.TLSClientConfig.Certificates)
+			}
 		})
 	}
 }
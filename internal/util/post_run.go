@@ -24,17 +24,22 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func CheckVersionAndShowMessage(opts *clioptions.CLIOptions, versionMajor, versionMinor int, message string) func(cmd *cobra.Command, _ []string) {
-	return func(cmd *cobra.Command, _ []string) {
+func CheckVersionAndShowMessage(opts *clioptions.CLIOptions, versionMajor, versionMinor int, message string) func(cmd *cobra.Command, _ []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
 		restConfig, err := opts.ToRESTConfig()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 		client, err := client.APIClientForConfig(restConfig)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		canUseNewAPI, versionError := VersionCheck(cmd.Context(), client, versionMajor, versionMinor)
 		if versionError == nil && canUseNewAPI {
 			writer := cmd.ErrOrStderr()
 			fmt.Fprintln(writer, message)
 		}
+		return nil
 	}
 }